@@ -0,0 +1,459 @@
+package mermaidgen
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Color represents a CSS color value for use with styles of all diagram types.
+// The empty Color means "not set". Any valid CSS color notation can be assigned
+// directly, e.g. Color("#f9f") or Color("rebeccapurple"), however the
+// constructors RGB, RGBA, HSL, ParseColor and Named always yield valid Colors.
+type Color string
+
+// Color definitions for use with styles.
+const (
+	ColorBlack   Color = `#000`
+	ColorBlue    Color = `#00f`
+	ColorGreen   Color = `#0f0`
+	ColorCyan    Color = `#0ff`
+	ColorRed     Color = `#f00`
+	ColorMagenta Color = `#f0f`
+	ColorYellow  Color = `#ff0`
+	ColorWhite   Color = `#fff`
+)
+
+// lookup table for the CSS named colors
+var namedColors = map[string]Color{
+	"aliceblue":            `#f0f8ff`,
+	"antiquewhite":         `#faebd7`,
+	"aqua":                 `#00ffff`,
+	"aquamarine":           `#7fffd4`,
+	"azure":                `#f0ffff`,
+	"beige":                `#f5f5dc`,
+	"bisque":               `#ffe4c4`,
+	"black":                `#000000`,
+	"blanchedalmond":       `#ffebcd`,
+	"blue":                 `#0000ff`,
+	"blueviolet":           `#8a2be2`,
+	"brown":                `#a52a2a`,
+	"burlywood":            `#deb887`,
+	"cadetblue":            `#5f9ea0`,
+	"chartreuse":           `#7fff00`,
+	"chocolate":            `#d2691e`,
+	"coral":                `#ff7f50`,
+	"cornflowerblue":       `#6495ed`,
+	"cornsilk":             `#fff8dc`,
+	"crimson":              `#dc143c`,
+	"cyan":                 `#00ffff`,
+	"darkblue":             `#00008b`,
+	"darkcyan":             `#008b8b`,
+	"darkgoldenrod":        `#b8860b`,
+	"darkgray":             `#a9a9a9`,
+	"darkgreen":            `#006400`,
+	"darkgrey":             `#a9a9a9`,
+	"darkkhaki":            `#bdb76b`,
+	"darkmagenta":          `#8b008b`,
+	"darkolivegreen":       `#556b2f`,
+	"darkorange":           `#ff8c00`,
+	"darkorchid":           `#9932cc`,
+	"darkred":              `#8b0000`,
+	"darksalmon":           `#e9967a`,
+	"darkseagreen":         `#8fbc8f`,
+	"darkslateblue":        `#483d8b`,
+	"darkslategray":        `#2f4f4f`,
+	"darkslategrey":        `#2f4f4f`,
+	"darkturquoise":        `#00ced1`,
+	"darkviolet":           `#9400d3`,
+	"deeppink":             `#ff1493`,
+	"deepskyblue":          `#00bfff`,
+	"dimgray":              `#696969`,
+	"dimgrey":              `#696969`,
+	"dodgerblue":           `#1e90ff`,
+	"firebrick":            `#b22222`,
+	"floralwhite":          `#fffaf0`,
+	"forestgreen":          `#228b22`,
+	"fuchsia":              `#ff00ff`,
+	"gainsboro":            `#dcdcdc`,
+	"ghostwhite":           `#f8f8ff`,
+	"gold":                 `#ffd700`,
+	"goldenrod":            `#daa520`,
+	"gray":                 `#808080`,
+	"green":                `#008000`,
+	"greenyellow":          `#adff2f`,
+	"grey":                 `#808080`,
+	"honeydew":             `#f0fff0`,
+	"hotpink":              `#ff69b4`,
+	"indianred":            `#cd5c5c`,
+	"indigo":               `#4b0082`,
+	"ivory":                `#fffff0`,
+	"khaki":                `#f0e68c`,
+	"lavender":             `#e6e6fa`,
+	"lavenderblush":        `#fff0f5`,
+	"lawngreen":            `#7cfc00`,
+	"lemonchiffon":         `#fffacd`,
+	"lightblue":            `#add8e6`,
+	"lightcoral":           `#f08080`,
+	"lightcyan":            `#e0ffff`,
+	"lightgoldenrodyellow": `#fafad2`,
+	"lightgray":            `#d3d3d3`,
+	"lightgreen":           `#90ee90`,
+	"lightgrey":            `#d3d3d3`,
+	"lightpink":            `#ffb6c1`,
+	"lightsalmon":          `#ffa07a`,
+	"lightseagreen":        `#20b2aa`,
+	"lightskyblue":         `#87cefa`,
+	"lightslategray":       `#778899`,
+	"lightslategrey":       `#778899`,
+	"lightsteelblue":       `#b0c4de`,
+	"lightyellow":          `#ffffe0`,
+	"lime":                 `#00ff00`,
+	"limegreen":            `#32cd32`,
+	"linen":                `#faf0e6`,
+	"magenta":              `#ff00ff`,
+	"maroon":               `#800000`,
+	"mediumaquamarine":     `#66cdaa`,
+	"mediumblue":           `#0000cd`,
+	"mediumorchid":         `#ba55d3`,
+	"mediumpurple":         `#9370db`,
+	"mediumseagreen":       `#3cb371`,
+	"mediumslateblue":      `#7b68ee`,
+	"mediumspringgreen":    `#00fa9a`,
+	"mediumturquoise":      `#48d1cc`,
+	"mediumvioletred":      `#c71585`,
+	"midnightblue":         `#191970`,
+	"mintcream":            `#f5fffa`,
+	"mistyrose":            `#ffe4e1`,
+	"moccasin":             `#ffe4b5`,
+	"navajowhite":          `#ffdead`,
+	"navy":                 `#000080`,
+	"oldlace":              `#fdf5e6`,
+	"olive":                `#808000`,
+	"olivedrab":            `#6b8e23`,
+	"orange":               `#ffa500`,
+	"orangered":            `#ff4500`,
+	"orchid":               `#da70d6`,
+	"palegoldenrod":        `#eee8aa`,
+	"palegreen":            `#98fb98`,
+	"paleturquoise":        `#afeeee`,
+	"palevioletred":        `#db7093`,
+	"papayawhip":           `#ffefd5`,
+	"peachpuff":            `#ffdab9`,
+	"peru":                 `#cd853f`,
+	"pink":                 `#ffc0cb`,
+	"plum":                 `#dda0dd`,
+	"powderblue":           `#b0e0e6`,
+	"purple":               `#800080`,
+	"rebeccapurple":        `#663399`,
+	"red":                  `#ff0000`,
+	"rosybrown":            `#bc8f8f`,
+	"royalblue":            `#4169e1`,
+	"saddlebrown":          `#8b4513`,
+	"salmon":               `#fa8072`,
+	"sandybrown":           `#f4a460`,
+	"seagreen":             `#2e8b57`,
+	"seashell":             `#fff5ee`,
+	"sienna":               `#a0522d`,
+	"silver":               `#c0c0c0`,
+	"skyblue":              `#87ceeb`,
+	"slateblue":            `#6a5acd`,
+	"slategray":            `#708090`,
+	"slategrey":            `#708090`,
+	"snow":                 `#fffafa`,
+	"springgreen":          `#00ff7f`,
+	"steelblue":            `#4682b4`,
+	"tan":                  `#d2b48c`,
+	"teal":                 `#008080`,
+	"thistle":              `#d8bfd8`,
+	"tomato":               `#ff6347`,
+	"turquoise":            `#40e0d0`,
+	"violet":               `#ee82ee`,
+	"wheat":                `#f5deb3`,
+	"white":                `#ffffff`,
+	"whitesmoke":           `#f5f5f5`,
+	"yellow":               `#ffff00`,
+	"yellowgreen":          `#9acd32`,
+	"transparent":          `#00000000`,
+}
+
+// regular expressions to parse the functional notations
+var (
+	rgbFunc = regexp.MustCompile(
+		`^rgba?\(\s*(\d+)\s*,\s*(\d+)\s*,\s*(\d+)\s*(?:,\s*([0-9.]+)\s*)?\)$`)
+	hslFunc = regexp.MustCompile(
+		`^hsla?\(\s*([0-9.]+)\s*,\s*([0-9.]+)%\s*,\s*([0-9.]+)%\s*(?:,\s*([0-9.]+)\s*)?\)$`)
+)
+
+// RGB creates a Color from its red, green and blue components.
+func RGB(r, g, b uint8) (c Color) {
+	return Color(fmt.Sprintf("#%02x%02x%02x", r, g, b))
+}
+
+// RGBA creates a Color from its red, green and blue components and an alpha
+// value between 0.0 (transparent) and 1.0 (opaque). Values out of range are
+// clamped. Opaque Colors render like RGB, others render to 8-digit hex.
+func RGBA(r, g, b uint8, a float64) (c Color) {
+	a = clamp(a, 0, 1)
+	if a == 1 {
+		return RGB(r, g, b)
+	}
+	return Color(fmt.Sprintf("#%02x%02x%02x%02x", r, g, b,
+		uint8(math.Round(a*255))))
+}
+
+// HSL creates a Color from hue (degrees), saturation and lightness (both 0.0 to
+// 1.0). Values out of range are clamped, the hue wraps around.
+func HSL(h, s, l float64) (c Color) {
+	r, g, b := hslToRGB(h, s, l)
+	return RGB(r, g, b)
+}
+
+// Named looks up one of the CSS named colors, e.g. "rebeccapurple", and returns
+// its hex notation. The lookup is case-insensitive. An error is returned if the
+// name is unknown.
+func Named(name string) (c Color, err error) {
+	c, found := namedColors[strings.ToLower(name)]
+	if !found {
+		return "", fmt.Errorf("unknown color name %q", name)
+	}
+	return c, nil
+}
+
+// ParseColor parses a CSS color definition in hex (#rgb, #rgba, #rrggbb,
+// #rrggbbaa), functional (rgb(), rgba(), hsl(), hsla()) or named notation and
+// returns it as normalized hex Color. An error is returned if the definition
+// can't be parsed.
+func ParseColor(definition string) (c Color, err error) {
+	r, g, b, a, err := Color(strings.TrimSpace(definition)).Components()
+	if err != nil {
+		return "", err
+	}
+	return RGBA(r, g, b, a), nil
+}
+
+// Components returns the red, green and blue components and the alpha value of
+// the Color. An error is returned if the Color isn't valid.
+func (c Color) Components() (r, g, b uint8, a float64, err error) {
+	s := string(c)
+	if named, found := namedColors[strings.ToLower(s)]; found {
+		s = string(named)
+	}
+	a = 1
+	switch {
+	case strings.HasPrefix(s, "#"):
+		hex := s[1:]
+		if len(hex) == 3 || len(hex) == 4 {
+			long := make([]byte, 0, len(hex)*2)
+			for i := 0; i < len(hex); i++ {
+				long = append(long, hex[i], hex[i])
+			}
+			hex = string(long)
+		}
+		if len(hex) != 6 && len(hex) != 8 {
+			break
+		}
+		v, perr := strconv.ParseUint(hex, 16, 32)
+		if perr != nil {
+			break
+		}
+		if len(hex) == 8 {
+			a = float64(v&0xff) / 255
+			v >>= 8
+		}
+		return uint8(v >> 16), uint8(v >> 8), uint8(v), a, nil
+	case rgbFunc.MatchString(s):
+		m := rgbFunc.FindStringSubmatch(s)
+		var v [3]uint8
+		for i := range v {
+			x, perr := strconv.ParseUint(m[i+1], 10, 8)
+			if perr != nil {
+				return 0, 0, 0, 0, fmt.Errorf("invalid color %q", c)
+			}
+			v[i] = uint8(x)
+		}
+		if m[4] != "" {
+			a, _ = strconv.ParseFloat(m[4], 64)
+		}
+		return v[0], v[1], v[2], clamp(a, 0, 1), nil
+	case hslFunc.MatchString(s):
+		m := hslFunc.FindStringSubmatch(s)
+		h, _ := strconv.ParseFloat(m[1], 64)
+		sat, _ := strconv.ParseFloat(m[2], 64)
+		l, _ := strconv.ParseFloat(m[3], 64)
+		if m[4] != "" {
+			a, _ = strconv.ParseFloat(m[4], 64)
+		}
+		r, g, b = hslToRGB(h, sat/100, l/100)
+		return r, g, b, clamp(a, 0, 1), nil
+	}
+	return 0, 0, 0, 0, fmt.Errorf("invalid color %q", c)
+}
+
+// Validate returns an error if the Color is set but can't be parsed.
+func (c Color) Validate() (err error) {
+	if c == "" {
+		return nil
+	}
+	_, _, _, _, err = c.Components()
+	return
+}
+
+// Lighten returns a Color with a lightness increased by amount (0.0 to 1.0) in
+// HSL color space. Invalid Colors are returned unchanged.
+func (c Color) Lighten(amount float64) (lighter Color) {
+	return c.adjustLightness(amount)
+}
+
+// Darken returns a Color with a lightness decreased by amount (0.0 to 1.0) in
+// HSL color space. Invalid Colors are returned unchanged.
+func (c Color) Darken(amount float64) (darker Color) {
+	return c.adjustLightness(-amount)
+}
+
+// Helperfunction to deduplicate code.
+func (c Color) adjustLightness(amount float64) (adjusted Color) {
+	r, g, b, a, err := c.Components()
+	if err != nil {
+		return c
+	}
+	h, s, l := rgbToHSL(r, g, b)
+	r, g, b = hslToRGB(h, s, clamp(l+amount, 0, 1))
+	return RGBA(r, g, b, a)
+}
+
+// Mix blends this Color with another one. A weight of 0.0 yields this Color, a
+// weight of 1.0 yields the other one. If any of the Colors is invalid, this
+// Color is returned unchanged.
+func (c Color) Mix(other Color, weight float64) (mixed Color) {
+	r1, g1, b1, a1, err := c.Components()
+	if err != nil {
+		return c
+	}
+	r2, g2, b2, a2, err := other.Components()
+	if err != nil {
+		return c
+	}
+	w := clamp(weight, 0, 1)
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x)*(1-w) + float64(y)*w))
+	}
+	return RGBA(mix(r1, r2), mix(g1, g2), mix(b1, b2), a1*(1-w)+a2*w)
+}
+
+// Luminance returns the relative luminance of the Color as defined by WCAG,
+// from 0.0 (black) to 1.0 (white). Invalid Colors yield 0.
+func (c Color) Luminance() (luminance float64) {
+	r, g, b, _, err := c.Components()
+	if err != nil {
+		return 0
+	}
+	channel := func(v uint8) float64 {
+		x := float64(v) / 255
+		if x <= 0.03928 {
+			return x / 12.92
+		}
+		return math.Pow((x+0.055)/1.055, 2.4)
+	}
+	return 0.2126*channel(r) + 0.7152*channel(g) + 0.0722*channel(b)
+}
+
+// Contrast returns the WCAG contrast ratio between this and the other Color,
+// from 1.0 (no contrast) to 21.0 (black on white).
+func (c Color) Contrast(other Color) (ratio float64) {
+	l1, l2 := c.Luminance(), other.Luminance()
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// ReadableTextColor returns ColorBlack or ColorWhite, whichever has the higher
+// contrast when used as text color on this Color as background.
+func (c Color) ReadableTextColor() (textColor Color) {
+	if c.Contrast(ColorBlack) >= c.Contrast(ColorWhite) {
+		return ColorBlack
+	}
+	return ColorWhite
+}
+
+// ANSI returns the 24-bit ANSI escape sequence that sets this Color as
+// terminal foreground color, or as background color if background is true.
+// An empty string is returned if the Color isn't valid.
+func (c Color) ANSI(background bool) (sequence string) {
+	r, g, b, _, err := c.Components()
+	if err != nil {
+		return ""
+	}
+	layer := 38
+	if background {
+		layer = 48
+	}
+	return fmt.Sprintf("\x1b[%d;2;%d;%d;%dm", layer, r, g, b)
+}
+
+// Helperfunction to limit a value to a range.
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, v))
+}
+
+// Helperfunction to convert HSL (degrees, 0.0-1.0, 0.0-1.0) to RGB.
+func hslToRGB(h, s, l float64) (r, g, b uint8) {
+	h = math.Mod(math.Mod(h, 360)+360, 360) / 360
+	s, l = clamp(s, 0, 1), clamp(l, 0, 1)
+	if s == 0 {
+		v := uint8(math.Round(l * 255))
+		return v, v, v
+	}
+	q := l * (1 + s)
+	if l >= 0.5 {
+		q = l + s - l*s
+	}
+	p := 2*l - q
+	hue := func(t float64) uint8 {
+		t = math.Mod(t+1, 1)
+		var v float64
+		switch {
+		case t < 1.0/6:
+			v = p + (q-p)*6*t
+		case t < 1.0/2:
+			v = q
+		case t < 2.0/3:
+			v = p + (q-p)*(2.0/3-t)*6
+		default:
+			v = p
+		}
+		return uint8(math.Round(v * 255))
+	}
+	return hue(h + 1.0/3), hue(h), hue(h - 1.0/3)
+}
+
+// Helperfunction to convert RGB to HSL (degrees, 0.0-1.0, 0.0-1.0).
+func rgbToHSL(r, g, b uint8) (h, s, l float64) {
+	rf, gf, bf := float64(r)/255, float64(g)/255, float64(b)/255
+	max := math.Max(rf, math.Max(gf, bf))
+	min := math.Min(rf, math.Min(gf, bf))
+	l = (max + min) / 2
+	if max == min {
+		return 0, 0, l
+	}
+	d := max - min
+	s = d / (2 - max - min)
+	if l < 0.5 {
+		s = d / (max + min)
+	}
+	switch max {
+	case rf:
+		h = (gf - bf) / d
+		if gf < bf {
+			h += 6
+		}
+	case gf:
+		h = (bf-rf)/d + 2
+	default:
+		h = (rf-gf)/d + 4
+	}
+	return h * 60, s, l
+}
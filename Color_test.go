@@ -0,0 +1,86 @@
+package mermaidgen_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen"
+)
+
+// Creating Colors
+func ExampleColor() {
+	// there are constructors for different notations
+	fmt.Println(mermaidgen.RGB(255, 128, 0))
+	fmt.Println(mermaidgen.RGBA(255, 128, 0, 0.5))
+	fmt.Println(mermaidgen.HSL(120, 1, 0.25))
+	// definitions can be parsed, the result is normalized
+	c, err := mermaidgen.ParseColor("rgb(10, 20, 30)")
+	fmt.Println(c, err)
+	c, err = mermaidgen.ParseColor("#ABC")
+	fmt.Println(c, err)
+	// and CSS named colors can be looked up
+	c, err = mermaidgen.Named("RebeccaPurple")
+	fmt.Println(c, err)
+	c, err = mermaidgen.Named("no-color")
+	fmt.Printf("%q %s\n", c, err)
+	//Output:
+	//#ff8000
+	//#ff800080
+	//#008000
+	//#0a141e <nil>
+	//#aabbcc <nil>
+	//#663399 <nil>
+	//"" unknown color name "no-color"
+}
+
+// Deriving Colors from other Colors
+func ExampleColor_helpers() {
+	c := mermaidgen.RGB(0x33, 0x66, 0x99)
+	fmt.Println(c.Lighten(0.2), c.Darken(0.2))
+	fmt.Println(mermaidgen.ColorRed.Mix(mermaidgen.ColorBlue, 0.5))
+	// select a text color with good contrast
+	fmt.Println(mermaidgen.ColorYellow.ReadableTextColor())
+	fmt.Println(c.ReadableTextColor())
+	fmt.Printf("%.1f\n", mermaidgen.ColorBlack.Contrast(mermaidgen.ColorWhite))
+	// terminal escape sequences
+	fmt.Printf("%q %q\n", c.ANSI(false), mermaidgen.ColorRed.ANSI(true))
+	//Output:
+	//#6699cc #19334d
+	//#800080
+	//#000
+	//#fff
+	//21.0
+	//"\x1b[38;2;51;102;153m" "\x1b[48;2;255;0;0m"
+}
+
+func TestColor_Components(t *testing.T) {
+	for definition, expected := range map[mermaidgen.Color][4]float64{
+		"#f00":                       {255, 0, 0, 1},
+		"#ff000080":                  {255, 0, 0, 128.0 / 255},
+		"rgba(1, 2, 3, 0.25)":        {1, 2, 3, 0.25},
+		"hsl(240, 100%, 50%)":        {0, 0, 255, 1},
+		"hsla(0, 0%, 100%, 0.5)":     {255, 255, 255, 0.5},
+		"White":                      {255, 255, 255, 1},
+		mermaidgen.Color("navy"):     {0, 0, 128, 1},
+		mermaidgen.HSL(-120, 1, 0.5): {0, 0, 255, 1},
+	} {
+		r, g, b, a, err := definition.Components()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", definition, err)
+			continue
+		}
+		if got := [4]float64{float64(r), float64(g), float64(b), a}; got != expected {
+			t.Errorf("%s: expected %v, got %v", definition, expected, got)
+		}
+	}
+	for _, definition := range []mermaidgen.Color{
+		"#12", "#ggg", "red;", "rgb(256, 0, 0)", "hsl(0, 0, 0)", "#1234567",
+	} {
+		if err := definition.Validate(); err == nil {
+			t.Errorf("%s: no error returned", definition)
+		}
+	}
+	if err := mermaidgen.Color("").Validate(); err != nil {
+		t.Errorf("unset Color: unexpected error: %s", err)
+	}
+}
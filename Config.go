@@ -0,0 +1,451 @@
+package mermaidgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+////////// Theme ///////////////////////////////////////////////////////////////
+
+type theme string
+
+// Theme definitions as described at https://mermaid.js.org/config/theming.html.
+// New Configs get ThemeDefault as the default. ThemeBase is the only theme
+// that can be modified via ThemeVariables.
+const (
+	ThemeDefault theme = `default`
+	ThemeDark    theme = `dark`
+	ThemeForest  theme = `forest`
+	ThemeNeutral theme = `neutral`
+	ThemeBase    theme = `base`
+)
+
+////////// ConfigSyntax ////////////////////////////////////////////////////////
+
+type configSyntax string
+
+// Syntax definitions for rendering a Config into the diagram code.
+// New Configs get SyntaxFrontmatter as the default.
+const (
+	SyntaxFrontmatter configSyntax = `frontmatter` // YAML frontmatter block
+	SyntaxDirective   configSyntax = `directive`   // %%{init: ...}%% line
+)
+
+////////// Curve ///////////////////////////////////////////////////////////////
+
+type curve string
+
+// Curve definitions for the edges of flowcharts.
+// The default is no curve setting which results in CurveBasis.
+const (
+	CurveBasis      curve = `basis`
+	CurveLinear     curve = `linear`
+	CurveCardinal   curve = `cardinal`
+	CurveMonotoneX  curve = `monotoneX`
+	CurveMonotoneY  curve = `monotoneY`
+	CurveNatural    curve = `natural`
+	CurveStep       curve = `step`
+	CurveStepAfter  curve = `stepAfter`
+	CurveStepBefore curve = `stepBefore`
+)
+
+////////// Config //////////////////////////////////////////////////////////////
+
+// Config holds the mermaid configuration of a diagram, such as the theme.
+// It is rendered in front of the diagram code and passed to the live editor.
+// Create an instance of Config via its constructor NewConfig, do not create
+// instances directly. Unset (zero value) fields are omitted.
+type Config struct {
+	Syntax         configSyntax      `json:"-"`                        // How the Config is rendered
+	Title          string            `json:"-"`                        // Optional title in the frontmatter
+	Theme          theme             `json:"theme,omitempty"`          // The theme to use
+	ThemeVariables map[string]string `json:"themeVariables,omitempty"` // Modifications of ThemeBase
+	FontFamily     string            `json:"fontFamily,omitempty"`     // Font for all diagrams
+	Flowchart      *FlowchartConfig  `json:"flowchart,omitempty"`      // Settings for flowcharts
+	Gantt          *GanttConfig      `json:"gantt,omitempty"`          // Settings for gantt diagrams
+	GitGraph       *GitGraphConfig   `json:"gitGraph,omitempty"`       // Settings for git graphs
+}
+
+// FlowchartConfig holds the settings specific to flowcharts.
+// Unset (zero value) fields are omitted.
+type FlowchartConfig struct {
+	Curve          curve `json:"curve,omitempty"`          // Edge interpolation
+	Padding        int   `json:"padding,omitempty"`        // Padding between label and node shape
+	NodeSpacing    int   `json:"nodeSpacing,omitempty"`    // Spacing between nodes on the same level
+	RankSpacing    int   `json:"rankSpacing,omitempty"`    // Spacing between nodes on different levels
+	DiagramPadding int   `json:"diagramPadding,omitempty"` // Padding around the whole diagram
+	HTMLLabels     *bool `json:"htmlLabels,omitempty"`     // Whether labels are rendered as HTML
+}
+
+// GanttConfig holds the settings specific to gantt diagrams.
+// Unset (zero value) fields are omitted.
+type GanttConfig struct {
+	BarHeight            int    `json:"barHeight,omitempty"`            // Height of the task bars
+	BarGap               int    `json:"barGap,omitempty"`               // Gap between the task bars
+	TopPadding           int    `json:"topPadding,omitempty"`           // Space above the chart
+	LeftPadding          int    `json:"leftPadding,omitempty"`          // Space for the section names
+	RightPadding         int    `json:"rightPadding,omitempty"`         // Space right of the chart
+	GridLineStartPadding int    `json:"gridLineStartPadding,omitempty"` // Vertical start of the grid lines
+	FontSize             int    `json:"fontSize,omitempty"`             // Font size of the task labels
+	SectionFontSize      int    `json:"sectionFontSize,omitempty"`      // Font size of the section names
+	NumberSectionStyles  int    `json:"numberSectionStyles,omitempty"`  // Number of alternating section styles
+	TopAxis              *bool  `json:"topAxis,omitempty"`              // Whether to add an axis on top
+	DisplayMode          string `json:"displayMode,omitempty"`          // e.g. compact
+}
+
+// GitGraphConfig holds the settings specific to git graphs.
+// Unset (zero value) fields are omitted.
+type GitGraphConfig struct {
+	MainBranchName    string `json:"mainBranchName,omitempty"`    // Name of the default branch, main if not set
+	MainBranchOrder   int    `json:"mainBranchOrder,omitempty"`   // Position of the default branch
+	ShowBranches      *bool  `json:"showBranches,omitempty"`      // Whether to show branch names and lines
+	ShowCommitLabel   *bool  `json:"showCommitLabel,omitempty"`   // Whether to show commit IDs
+	RotateCommitLabel *bool  `json:"rotateCommitLabel,omitempty"` // Whether to rotate commit IDs
+}
+
+// NewConfig is the constructor used to create a new Config object.
+// Optional initializer parameters can be given in the order Theme, Syntax.
+func NewConfig(init ...interface{}) (newConfig *Config, err error) {
+	c := &Config{Theme: ThemeDefault, Syntax: SyntaxFrontmatter}
+	switch l := len(init); {
+	case l > 1:
+		switch v := init[1].(type) {
+		case configSyntax:
+			c.Syntax = v
+		case string:
+			c.Syntax = configSyntax(v)
+		default:
+			return nil, fmt.Errorf("value for Syntax was no configSyntax")
+		}
+		fallthrough
+	case l > 0:
+		switch v := init[0].(type) {
+		case theme:
+			c.Theme = v
+		case string:
+			c.Theme = theme(v)
+		default:
+			return nil, fmt.Errorf("value for Theme was no theme")
+		}
+	}
+	return c, nil
+}
+
+// JSON renders the Config to a JSON object as used by mermaid's initialize
+// function and the live editor. A nil Config renders to the default theme.
+func (c *Config) JSON() (renderedJSON []byte) {
+	if c == nil {
+		c = &Config{Theme: ThemeDefault}
+	}
+	data, _ := json.Marshal(c)
+	return data
+}
+
+// String renders the Config to a YAML frontmatter block or an init directive
+// line, depending on Syntax. A Title is always rendered to a frontmatter block,
+// which precedes the directive line. A nil Config renders to an empty string.
+func (c *Config) String() (renderedElement string) {
+	if c == nil {
+		return ""
+	}
+	title := ""
+	if c.Title != "" {
+		title = "title: " + yamlScalar(c.Title) + "\n"
+	}
+	if c.Syntax == SyntaxDirective {
+		directive := fmt.Sprintf("%%%%{init: %s}%%%%\n", c.JSON())
+		if title != "" {
+			return "---\n" + title + "---\n" + directive
+		}
+		return directive
+	}
+	var tree map[string]interface{}
+	json.Unmarshal(c.JSON(), &tree)
+	text := "---\n" + title + "config:\n"
+	if len(tree) == 0 {
+		if title != "" {
+			return "---\n" + title + "---\n"
+		}
+		text = "---\nconfig: {}\n"
+	}
+	return text + renderYAML(tree, "  ") + "---\n"
+}
+
+// plain YAML scalars that don't need to be quoted
+var isPlainYAML = regexp.MustCompile(
+	`^[a-zA-Z_]([a-zA-Z0-9_ .-]*[a-zA-Z0-9_.-])?$`).MatchString
+
+// Helperfunction to render a decoded JSON object to YAML with sorted keys.
+func renderYAML(tree map[string]interface{}, indent string) (renderedYAML string) {
+	keys := make([]string, 0, len(tree))
+	for k := range tree {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		switch v := tree[k].(type) {
+		case map[string]interface{}:
+			if len(v) == 0 {
+				renderedYAML += indent + k + ": {}\n"
+			} else {
+				renderedYAML += indent + k + ":\n" + renderYAML(v, indent+"  ")
+			}
+		case string:
+			renderedYAML += indent + k + ": " + yamlScalar(v) + "\n"
+		default:
+			renderedYAML += fmt.Sprintf("%s%s: %v\n", indent, k, v)
+		}
+	}
+	return
+}
+
+// Helperfunction to quote strings which can't be plain YAML scalars.
+func yamlScalar(value string) (renderedYAML string) {
+	if !isPlainYAML(value) || value == "true" || value == "false" ||
+		value == "null" {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+////////// parse Config ////////////////////////////////////////////////////////
+
+// ParseConfigJSON creates a Config from a JSON object as used by mermaid's
+// initialize function and the live editor. Unknown settings are ignored,
+// non-string ThemeVariables are converted to strings. Syntax is set to
+// SyntaxFrontmatter.
+func ParseConfigJSON(data []byte) (newConfig *Config, err error) {
+	var tree map[string]interface{}
+	if err = json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("ParseConfigJSON: %s", err)
+	}
+	if vars, ok := tree["themeVariables"].(map[string]interface{}); ok {
+		for k, v := range vars {
+			if _, isString := v.(string); !isString {
+				vars[k] = fmt.Sprint(v)
+			}
+		}
+		data, _ = json.Marshal(tree)
+	}
+	c := &Config{Syntax: SyntaxFrontmatter}
+	if err = json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("ParseConfigJSON: %s", err)
+	}
+	return c, nil
+}
+
+// MarshalYAML returns the Config as a tree of maps with the same keys as its
+// JSON, so YAML libraries like gopkg.in/yaml.v2 and gopkg.in/yaml.v3 render it
+// like mermaid expects it. Syntax is not included.
+func (c *Config) MarshalYAML() (tree interface{}, err error) {
+	var m map[string]interface{}
+	err = json.Unmarshal(c.JSON(), &m)
+	return m, err
+}
+
+// UnmarshalYAML reads the Config from a tree of maps as written by
+// MarshalYAML, see ParseConfigJSON for details. It is called by YAML libraries
+// like gopkg.in/yaml.v2 and gopkg.in/yaml.v3.
+func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) (err error) {
+	var tree interface{}
+	if err = unmarshal(&tree); err != nil {
+		return err
+	}
+	data, err := json.Marshal(jsonTree(tree))
+	if err != nil {
+		return fmt.Errorf("UnmarshalYAML: %s", err)
+	}
+	parsed, err := ParseConfigJSON(data)
+	if err != nil {
+		return err
+	}
+	*c = *parsed
+	return nil
+}
+
+// Helperfunction to convert maps with non-string keys, as decoded by YAML
+// libraries, recursively to map[string]interface{} so the tree can be marshaled
+// to JSON. Other values are returned unchanged.
+func jsonTree(tree interface{}) (converted interface{}) {
+	switch v := tree.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, value := range v {
+			m[fmt.Sprint(k)] = jsonTree(value)
+		}
+		return m
+	case map[string]interface{}:
+		for k, value := range v {
+			v[k] = jsonTree(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = jsonTree(value)
+		}
+	}
+	return tree
+}
+
+// regular expression to match init directives
+var initDirective = regexp.MustCompile(
+	`(?s)^%%\{\s*(?:init|initialize)\s*:\s*(\{.*\})\s*\}%%$`)
+
+// SplitConfig separates a leading YAML frontmatter block and/or init directive
+// from mermaid code and parses it to a Config, including the frontmatter's
+// title. The remaining code is returned as body. If there is no configuration,
+// config is nil. The frontmatter is parsed for the simple YAML subset rendered
+// by Config's String method (nested maps, plain and quoted scalars). An error
+// is returned if the configuration can't be parsed.
+func SplitConfig(code string) (body string, config *Config, err error) {
+	trimmed := strings.TrimLeft(code, " \t\r\n")
+	if strings.HasPrefix(trimmed, "%%{") {
+		end := strings.Index(trimmed, "}%%")
+		if end < 0 {
+			return "", nil, fmt.Errorf("SplitConfig: unterminated directive")
+		}
+		m := initDirective.FindStringSubmatch(trimmed[:end+3])
+		if m == nil {
+			// some other directive, e.g. wrap
+			return code, nil, nil
+		}
+		config, err = ParseConfigJSON([]byte(directiveJSON(m[1])))
+		if err == nil {
+			config.Syntax = SyntaxDirective
+		}
+		return trimmed[end+3:], config, err
+	}
+	if !strings.HasPrefix(trimmed, "---") {
+		return code, nil, nil
+	}
+	lines := strings.Split(trimmed, "\n")
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			tree, err := parseYAML(lines[1:i])
+			if err != nil {
+				return "", nil, fmt.Errorf("SplitConfig: %s", err)
+			}
+			body = strings.Join(lines[i+1:], "\n")
+			title := ""
+			if t, ok := tree["title"]; ok {
+				title = fmt.Sprint(t)
+			}
+			if configTree, ok := tree["config"].(map[string]interface{}); ok {
+				data, _ := json.Marshal(configTree)
+				if config, err = ParseConfigJSON(data); err != nil {
+					return "", nil, err
+				}
+			} else if strings.HasPrefix(strings.TrimLeft(body, " \t\r\n"),
+				"%%{") {
+				// the frontmatter may be followed by a directive
+				if body, config, err = SplitConfig(body); err != nil {
+					return "", nil, err
+				}
+			}
+			if config == nil && title != "" {
+				config = &Config{Syntax: SyntaxFrontmatter}
+			}
+			if config != nil {
+				config.Title = title
+			}
+			return body, config, nil
+		}
+	}
+	return "", nil, fmt.Errorf("SplitConfig: unterminated frontmatter")
+}
+
+// Helperfunction to convert the single quoted strings directives are often
+// written with to JSON strings. Apostrophes within double quoted strings are
+// kept as they are.
+func directiveJSON(directive string) (jsonObject string) {
+	var b strings.Builder
+	var quote byte // delimiter of the current string, 0 outside strings
+	for i := 0; i < len(directive); i++ {
+		c := directive[i]
+		switch {
+		case quote != 0 && c == '\\' && i+1 < len(directive):
+			i++
+			if quote == '\'' && directive[i] == '\'' {
+				b.WriteByte('\'')
+			} else {
+				b.WriteByte(c)
+				b.WriteByte(directive[i])
+			}
+		case quote == 0 && (c == '\'' || c == '"'):
+			quote = c
+			b.WriteByte('"')
+		case quote == c:
+			quote = 0
+			b.WriteByte('"')
+		case quote == '\'' && c == '"':
+			b.WriteString(`\"`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// Helperfunction to parse a simple YAML subset: nested maps by indentation and
+// scalar values.
+func parseYAML(lines []string) (tree map[string]interface{}, err error) {
+	type level struct {
+		indent int
+		tree   map[string]interface{}
+	}
+	root := map[string]interface{}{}
+	stack := []level{{-1, root}}
+	for n, line := range lines {
+		content := strings.TrimSpace(line)
+		if content == "" || strings.HasPrefix(content, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		for indent <= stack[len(stack)-1].indent {
+			stack = stack[:len(stack)-1]
+		}
+		colon := strings.Index(content, ":")
+		if colon <= 0 {
+			return nil, fmt.Errorf("line %d: expected key: value", n+1)
+		}
+		key := strings.Trim(content[:colon], `"'`)
+		value := strings.TrimSpace(content[colon+1:])
+		current := stack[len(stack)-1].tree
+		switch {
+		case value == "":
+			sub := map[string]interface{}{}
+			current[key] = sub
+			stack = append(stack, level{indent, sub})
+		case value == "{}":
+			current[key] = map[string]interface{}{}
+		default:
+			current[key] = parseYAMLScalar(value)
+		}
+	}
+	return root, nil
+}
+
+// Helperfunction to convert a YAML scalar to its JSON compatible Go value.
+func parseYAMLScalar(value string) (scalar interface{}) {
+	switch {
+	case strings.HasPrefix(value, `"`):
+		if s, err := strconv.Unquote(value); err == nil {
+			return s
+		}
+	case strings.HasPrefix(value, `'`) && strings.HasSuffix(value, `'`):
+		return strings.Replace(value[1:len(value)-1], "''", "'", -1)
+	case value == "true" || value == "false":
+		return value == "true"
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	// strip trailing comments of plain scalars
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value
+}
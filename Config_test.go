@@ -0,0 +1,127 @@
+package mermaidgen_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen"
+)
+
+// Rendering a Config as YAML frontmatter or init directive
+func ExampleConfig() {
+	// setting Theme and Syntax on object creation
+	c, _ := mermaidgen.NewConfig(mermaidgen.ThemeBase)
+	c.ThemeVariables = map[string]string{"primaryColor": "#ff0000"}
+	c.FontFamily = "Trebuchet MS"
+	htmlLabels := false
+	c.Flowchart = &mermaidgen.FlowchartConfig{
+		Curve: mermaidgen.CurveStepBefore, HTMLLabels: &htmlLabels,
+	}
+	fmt.Print(c)
+	// the same Config as init directive
+	c.Syntax = mermaidgen.SyntaxDirective
+	fmt.Print(c)
+	//Output:
+	//---
+	//config:
+	//   flowchart:
+	//     curve: stepBefore
+	//     htmlLabels: false
+	//   fontFamily: Trebuchet MS
+	//   theme: base
+	//   themeVariables:
+	//     primaryColor: "#ff0000"
+	//---
+	//%%{init: {"theme":"base","themeVariables":{"primaryColor":"#ff0000"},"fontFamily":"Trebuchet MS","flowchart":{"curve":"stepBefore","htmlLabels":false}}}%%
+}
+
+// The Config creation may yield errors
+func ExampleConfig_errorHandling() {
+	c1, err := mermaidgen.NewConfig(5)
+	fmt.Println(c1 == nil, err)
+	c2, err := mermaidgen.NewConfig(mermaidgen.ThemeDark, 5)
+	fmt.Println(c2 == nil, err)
+	c3, err := mermaidgen.NewConfig("forest", "directive")
+	fmt.Print(c3)
+	fmt.Println(err)
+	//Output:
+	//true value for Theme was no theme
+	//true value for Syntax was no configSyntax
+	//%%{init: {"theme":"forest"}}%%
+	//<nil>
+}
+
+// Separating the configuration from mermaid code
+func ExampleSplitConfig() {
+	body, config, err := mermaidgen.SplitConfig(`---
+title: my chart
+config:
+  theme: base
+  themeVariables:
+    primaryColor: "#ff0000"
+    fontSize: 16
+  gantt:
+    barHeight: 30
+---
+gantt
+`)
+	fmt.Printf("%q %v\n", body, err)
+	fmt.Println(config.Title, config.Theme, config.ThemeVariables["fontSize"],
+		config.Gantt.BarHeight)
+	body, config, err = mermaidgen.SplitConfig(
+		"%%{init: {'theme': 'forest', 'fontFamily': \"Tom's font\"}}%%\ngraph LR\n")
+	fmt.Printf("%q %v\n", body, err)
+	fmt.Print(config)
+	//Output:
+	//"gantt\n" <nil>
+	//my chart base 16 30
+	//"\ngraph LR\n" <nil>
+	//%%{init: {"theme":"forest","fontFamily":"Tom's font"}}%%
+}
+
+// YAML libraries like gopkg.in/yaml.v2 use MarshalYAML and UnmarshalYAML
+func ExampleConfig_MarshalYAML() {
+	c, _ := mermaidgen.NewConfig(mermaidgen.ThemeBase)
+	c.ThemeVariables = map[string]string{"primaryColor": "#ff0000"}
+	tree, _ := c.MarshalYAML()
+	fmt.Println(tree)
+	// yaml.v2 decodes nested maps with interface{} keys
+	var d mermaidgen.Config
+	d.UnmarshalYAML(func(v interface{}) error {
+		*(v.(*interface{})) = map[interface{}]interface{}{
+			"theme":     "dark",
+			"flowchart": map[interface{}]interface{}{"curve": "linear"},
+		}
+		return nil
+	})
+	fmt.Print(d.String())
+	//Output:
+	//map[theme:base themeVariables:map[primaryColor:#ff0000]]
+	//---
+	//config:
+	//   flowchart:
+	//     curve: linear
+	//   theme: dark
+	//---
+}
+
+func TestSplitConfig_title(t *testing.T) {
+	for _, code := range []string{
+		"---\ntitle: My chart\nconfig:\n  theme: dark\n---\ngraph LR\n",
+		"---\ntitle: \"a: b\"\n---\ngraph LR\n",
+		"---\ntitle: x\n---\n%%{init: {\"theme\":\"dark\"}}%%\ngraph LR\n",
+	} {
+		body, config, err := mermaidgen.SplitConfig(code)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config == nil || config.Title == "" {
+			t.Errorf("%q: title missing", code)
+			continue
+		}
+		if config.String()+strings.TrimLeft(body, "\n") != code {
+			t.Errorf("%q: unexpected round trip\n%s%s", code, config, body)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package mermaidgen
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os/exec"
+	"runtime"
+)
+
+////////// DiagramKind /////////////////////////////////////////////////////////
+
+// DiagramKind identifies the type of a Diagram.
+type DiagramKind string
+
+// Kind definitions for all diagram types implemented by the subpackages.
+const (
+	KindFlowchart    DiagramKind = `flowchart`
+	KindGantt        DiagramKind = `gantt`
+	KindClassDiagram DiagramKind = `classDiagram`
+	KindStateDiagram DiagramKind = `stateDiagram`
+	KindERDiagram    DiagramKind = `erDiagram`
+	KindPie          DiagramKind = `pie`
+	KindGitGraph     DiagramKind = `gitGraph`
+	KindMindmap      DiagramKind = `mindmap`
+	KindTimeline     DiagramKind = `timeline`
+	KindJourney      DiagramKind = `journey`
+)
+
+////////// Diagram /////////////////////////////////////////////////////////////
+
+// Diagram is implemented by the entrypoint types of all diagram packages,
+// e.g. flowchart.Flowchart, gantt.Gantt and classdiagram.ClassDiagram, so they
+// can be handled generically.
+type Diagram interface {
+	// Render writes the mermaid code of the whole diagram to w.
+	Render(w io.Writer) (err error)
+	// Kind tells which type of diagram this is.
+	Kind() (kind DiagramKind)
+	// GetConfig returns the diagram's Config, which may be nil.
+	GetConfig() (config *Config)
+	// SetConfig replaces the diagram's Config, nil removes it.
+	SetConfig(config *Config)
+}
+
+// RenderString renders the given Diagram to a string of mermaid code.
+func RenderString(d Diagram) (code string, err error) {
+	var b bytes.Buffer
+	err = d.Render(&b)
+	return b.String(), err
+}
+
+// OpenBrowser opens the given URL in the OS's default browser. It returns as
+// soon as the browser was started and eventually returns any error occured.
+func OpenBrowser(url string) (err error) {
+	name, args, err := browserCommand(runtime.GOOS, url)
+	if err != nil {
+		return err
+	}
+	return exec.Command(name, args...).Start()
+}
+
+// ViewInBrowser uses the URL generated by LiveURL and opens that URL in the
+// OS's default browser via BrowserRenderer.
+func ViewInBrowser(d Diagram) (err error) {
+	return (&BrowserRenderer{}).RenderDiagram(context.Background(), d,
+		ioutil.Discard)
+}
@@ -0,0 +1,41 @@
+package mermaidgen_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/flowchart"
+	"github.com/Heiko-san/mermaidgen/gantt"
+)
+
+// Handling different diagram types generically
+func ExampleDiagram() {
+	f := flowchart.NewFlowchart()
+	f.AddNode("n1")
+	g, _ := gantt.NewGantt("my gantt")
+	for _, d := range []mermaidgen.Diagram{f, g} {
+		// the Config of any Diagram can be accessed
+		config, _ := mermaidgen.NewConfig(mermaidgen.ThemeForest,
+			mermaidgen.SyntaxDirective)
+		d.SetConfig(config)
+		// Render writes to any io.Writer
+		var b strings.Builder
+		d.Render(&b)
+		fmt.Printf("%s: %q\n", d.Kind(), b.String())
+	}
+	//Output:
+	//flowchart: "%%{init: {\"theme\":\"forest\"}}%%\ngraph TB\nn1[\"n1\"]\n"
+	//gantt: "%%{init: {\"theme\":\"forest\"}}%%\ngantt\ndateFormat YYYY-MM-DDTHH:mm:ssZ\ntitle my gantt\n"
+}
+
+// Generating URLs for the mermaid live editor
+func ExampleLiveURL() {
+	f := flowchart.NewFlowchart()
+	f.AddEdge(f.AddNode("n1"), f.AddNode("n2"))
+	// you can also use mermaidgen.ViewInBrowser(f) to open the URL directly
+	url, err := mermaidgen.LiveURL(f)
+	fmt.Println(url, err)
+	//Output:
+	//https://mermaid.live/view/#pako:eNqqVkrOT0lVslJKL0osyFAIcYrJyzOMjlHKM4xRio3JyzMCsY0gbEMFXd2YUgMD41SFPKOYPCUdpdzUotzEzBQlq2qlkozUXJA5KalpiaU5JUq1tYABACEwHQk= <nil>
+}
@@ -0,0 +1,131 @@
+package mermaidgen
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+)
+
+// LiveEditorURL is the base URL used to generate live editor links.
+const LiveEditorURL = `https://mermaid.live`
+
+// Structs for JSON encode and decode
+type dataJSON struct {
+	Code    string          `json:"code"`
+	Mermaid json.RawMessage `json:"mermaid"`
+}
+
+////////// encode //////////////////////////////////////////////////////////////
+
+// PakoEncode generates the "pako:" payload used by the live editor from
+// mermaid code and its Config (nil yields the default theme): a JSON state
+// object, zlib compressed and base64url encoded.
+func PakoEncode(code string, config *Config) (payload string) {
+	data, _ := json.Marshal(dataJSON{Code: code, Mermaid: config.JSON()})
+	var b bytes.Buffer
+	w, _ := zlib.NewWriterLevel(&b, zlib.BestCompression)
+	w.Write(data)
+	w.Close()
+	return "pako:" + base64.URLEncoding.EncodeToString(b.Bytes())
+}
+
+// LiveURL renders the Diagram and generates a view URL for
+// https://mermaid.live from it. The Diagram's Config is passed to the live
+// editor, too, the default theme is used if it's nil. Use URLBuilder for edit
+// URLs, other renderers or self-hosted instances.
+func LiveURL(d Diagram) (url string, err error) {
+	return (&URLBuilder{Target: TargetLiveView}).URL(d)
+}
+
+////////// decode //////////////////////////////////////////////////////////////
+
+// DecodeLiveURL extracts the mermaid code and the Config from a live editor
+// URL. Both /view and /edit links are supported, with "#pako:" (zlib and
+// base64url) or legacy "#base64:" fragments, as well as the old "#/view/..."
+// and "#/edit/..." fragment routes. An error is returned if the URL doesn't
+// contain a decodable diagram.
+func DecodeLiveURL(liveURL string) (code string, config *Config, err error) {
+	u, err := url.Parse(strings.TrimSpace(liveURL))
+	if err != nil {
+		return "", nil, fmt.Errorf("DecodeLiveURL: %s", err)
+	}
+	fragment := u.Fragment
+	// old editors used the fragment for routing
+	for _, route := range []string{"/view/", "/edit/"} {
+		if strings.HasPrefix(fragment, route) {
+			fragment = strings.TrimPrefix(fragment, route)
+			if !strings.HasPrefix(fragment, "pako:") {
+				fragment = "base64:" + fragment
+			}
+		}
+	}
+	var data []byte
+	switch {
+	case strings.HasPrefix(fragment, "pako:"):
+		data, err = PakoDecode(fragment)
+	case strings.HasPrefix(fragment, "base64:"):
+		data, err = decodeBase64(strings.TrimPrefix(fragment, "base64:"))
+	default:
+		return "", nil, fmt.Errorf("DecodeLiveURL: no diagram found in %q",
+			liveURL)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("DecodeLiveURL: %s", err)
+	}
+	return decodeState(data)
+}
+
+// PakoDecode reverts PakoEncode, it returns the uncompressed JSON state object
+// of a "pako:" payload. The "pako:" prefix is optional.
+func PakoDecode(payload string) (state []byte, err error) {
+	return zlibDecode(strings.TrimPrefix(payload, "pako:"))
+}
+
+// Helperfunction to decode base64 encoded zlib data.
+func zlibDecode(payload string) (data []byte, err error) {
+	compressed, err := decodeBase64(payload)
+	if err != nil {
+		return nil, err
+	}
+	r, err := zlib.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// Helperfunction to decode base64 in any of the variants used by the
+// different versions of the live editor.
+func decodeBase64(payload string) (data []byte, err error) {
+	payload = strings.TrimRight(payload, "=")
+	data, err = base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		data, err = base64.RawStdEncoding.DecodeString(payload)
+	}
+	return
+}
+
+// Helperfunction to extract code and Config from a JSON state object.
+func decodeState(data []byte) (code string, config *Config, err error) {
+	var state dataJSON
+	if err = json.Unmarshal(data, &state); err != nil {
+		return "", nil, fmt.Errorf("DecodeLiveURL: %s", err)
+	}
+	if len(state.Mermaid) == 0 {
+		return state.Code, nil, nil
+	}
+	// newer editors store the config as a JSON string
+	configJSON := []byte(state.Mermaid)
+	var configString string
+	if json.Unmarshal(configJSON, &configString) == nil {
+		configJSON = []byte(configString)
+	}
+	config, err = ParseConfigJSON(configJSON)
+	return state.Code, config, err
+}
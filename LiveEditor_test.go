@@ -0,0 +1,71 @@
+package mermaidgen_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/flowchart"
+)
+
+// Decoding live editor URLs
+func ExampleDecodeLiveURL() {
+	f := flowchart.NewFlowchart()
+	f.AddEdge(f.AddNode("n1"), f.AddNode("n2"))
+	f.Config, _ = mermaidgen.NewConfig(mermaidgen.ThemeDark)
+	url, _ := mermaidgen.LiveURL(f)
+	// get back the code and the Config
+	code, config, err := mermaidgen.DecodeLiveURL(url)
+	fmt.Print(code)
+	fmt.Println(config.Theme, err)
+	// legacy base64 links are supported, too
+	code, config, err = mermaidgen.DecodeLiveURL("https://mermaid.live/edit#" +
+		"base64:eyJjb2RlIjogImdyYXBoIFREXG5BLS0-QiIsICJtZXJtYWlkIjogIntcInRo" +
+		"ZW1lXCI6IFwiZm9yZXN0XCJ9In0")
+	fmt.Println(code)
+	fmt.Println(config.Theme, err)
+	//Output:
+	//---
+	//config:
+	//   theme: dark
+	//---
+	//graph TB
+	//n1["n1"]
+	//n2["n2"]
+	//n1 --> n2
+	//dark <nil>
+	//graph TD
+	//A-->B
+	//forest <nil>
+}
+
+func TestDecodeLiveURL(t *testing.T) {
+	for _, url := range []string{
+		"https://mermaid-js.github.io/mermaid-live-editor/#/edit/" +
+			"eyJjb2RlIjogImdhbnR0XG50MSA6IDFoIiwgIm1lcm1haWQiOiB7InRoZW1lIjogImRhcmsifX0=",
+		"https://mermaid.live/view#base64:" +
+			"eyJjb2RlIjogImdhbnR0XG50MSA6IDFoIiwgIm1lcm1haWQiOiB7InRoZW1lIjogImRhcmsifX0",
+		"https://mermaid.live/edit#" + mermaidgen.PakoEncode("gantt\nt1 : 1h",
+			&mermaidgen.Config{Theme: mermaidgen.ThemeDark}),
+	} {
+		code, config, err := mermaidgen.DecodeLiveURL(url)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", url, err)
+			continue
+		}
+		if code != "gantt\nt1 : 1h" || config.Theme != mermaidgen.ThemeDark {
+			t.Errorf("%s: unexpected result %q %v", url, code, config)
+		}
+	}
+	for _, url := range []string{
+		"https://mermaid.live/edit",
+		"https://mermaid.live/edit#pako:not-base64!",
+		"https://mermaid.live/edit#pako:eyJjb2RlIjo",
+		"https://mermaid.live/edit#base64:bm8ganNvbg",
+		"%zz",
+	} {
+		if _, _, err := mermaidgen.DecodeLiveURL(url); err == nil {
+			t.Errorf("%s: no error returned", url)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package mermaidgen
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+////////// ImageFormat /////////////////////////////////////////////////////////
+
+type imageFormat string
+
+// Format definitions for images rendered by MMDCRenderer.
+// New MMDCRenderers get FormatSVG as the default.
+const (
+	FormatSVG imageFormat = `svg`
+	FormatPNG imageFormat = `png`
+	FormatPDF imageFormat = `pdf`
+)
+
+////////// Renderer ////////////////////////////////////////////////////////////
+
+// Renderer is implemented by all types that can turn a Diagram into some
+// output, e.g. MMDCRenderer produces images, BrowserRenderer opens the live
+// editor and FakeRenderer records Diagrams for tests.
+type Renderer interface {
+	// RenderDiagram renders d and writes the result to w. The context can be
+	// used to cancel external commands.
+	RenderDiagram(ctx context.Context, d Diagram, w io.Writer) (err error)
+}
+
+// CommandRunner runs an external command to completion. stdin may be nil,
+// stdout receives the command's output. It is used by MMDCRenderer and
+// BrowserRenderer and can be replaced to test them without the commands.
+type CommandRunner func(ctx context.Context, name string, args []string,
+	stdin io.Reader, stdout io.Writer) (err error)
+
+// RunCommand is the default CommandRunner, it uses os/exec. The command's
+// stderr is added to the returned error.
+func RunCommand(ctx context.Context, name string, args []string,
+	stdin io.Reader, stdout io.Writer) (err error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, stdout, &stderr
+	if err = cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %s: %s", name, err, msg)
+		}
+		return fmt.Errorf("%s: %s", name, err)
+	}
+	return nil
+}
+
+////////// MMDCRenderer ////////////////////////////////////////////////////////
+
+// MMDCRenderer renders Diagrams to images using the mermaid-cli binary mmdc,
+// see https://github.com/mermaid-js/mermaid-cli. The Diagram's Config is part
+// of the code passed to mmdc. Create an instance of MMDCRenderer via its
+// constructor NewMMDCRenderer, do not create instances directly.
+// Unset (zero value) options are not passed to mmdc.
+type MMDCRenderer struct {
+	Binary          string        // Path or name of the mmdc binary
+	Format          imageFormat   // Output format
+	Width           int           // Width of the page in pixels
+	Height          int           // Height of the page in pixels
+	Scale           int           // Scale factor, e.g. for sharper PNGs
+	Background      Color         // Background color, e.g. "transparent"
+	PuppeteerConfig string        // Path of a puppeteer config JSON file
+	Timeout         time.Duration // Limits the runtime of mmdc if set
+	Stdin           bool          // Pass the code via stdin, not a temp file
+	Runner          CommandRunner // Runs mmdc, nil uses RunCommand
+}
+
+// NewMMDCRenderer is the constructor used to create a new MMDCRenderer object.
+// Optional initializer parameters can be given in the order Format, Binary.
+// Binary defaults to "mmdc", which is looked up in PATH.
+func NewMMDCRenderer(init ...interface{}) (newRenderer *MMDCRenderer, err error) {
+	r := &MMDCRenderer{Binary: "mmdc", Format: FormatSVG}
+	switch l := len(init); {
+	case l > 1:
+		binary, ok := init[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("value for Binary was no string")
+		}
+		r.Binary = binary
+		fallthrough
+	case l > 0:
+		switch v := init[0].(type) {
+		case imageFormat:
+			r.Format = v
+		case string:
+			r.Format = imageFormat(v)
+		default:
+			return nil, fmt.Errorf("value for Format was no imageFormat")
+		}
+	}
+	return r, nil
+}
+
+// RenderDiagram renders d to mermaid code, passes it to mmdc and writes the
+// resulting image to w. mmdc writes to a file in a temporary directory, which
+// is removed afterwards. Implements Renderer.
+func (r *MMDCRenderer) RenderDiagram(ctx context.Context, d Diagram,
+	w io.Writer) (err error) {
+	code, err := RenderString(d)
+	if err != nil {
+		return err
+	}
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+	dir, err := ioutil.TempDir("", "mermaidgen")
+	if err != nil {
+		return fmt.Errorf("RenderDiagram: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	output := filepath.Join(dir, "diagram."+string(r.Format))
+	var stdin io.Reader
+	input := "-"
+	if r.Stdin {
+		stdin = strings.NewReader(code)
+	} else {
+		input = filepath.Join(dir, "diagram.mmd")
+		if err = ioutil.WriteFile(input, []byte(code), 0600); err != nil {
+			return fmt.Errorf("RenderDiagram: %s", err)
+		}
+	}
+	runner := r.Runner
+	if runner == nil {
+		runner = RunCommand
+	}
+	var stdout bytes.Buffer
+	err = runner(ctx, r.Binary, r.args(input, output), stdin, &stdout)
+	if err != nil {
+		return fmt.Errorf("RenderDiagram: %s", err)
+	}
+	image, err := os.Open(output)
+	if err != nil {
+		return fmt.Errorf("RenderDiagram: no output from %s: %s", r.Binary, err)
+	}
+	defer image.Close()
+	_, err = io.Copy(w, image)
+	return
+}
+
+// Helperfunction to create the command line arguments for mmdc.
+func (r *MMDCRenderer) args(input, output string) (args []string) {
+	args = []string{"--quiet", "--input", input, "--output", output}
+	if r.Format != "" {
+		args = append(args, "--outputFormat", string(r.Format))
+	}
+	if r.Width > 0 {
+		args = append(args, "--width", strconv.Itoa(r.Width))
+	}
+	if r.Height > 0 {
+		args = append(args, "--height", strconv.Itoa(r.Height))
+	}
+	if r.Scale > 0 {
+		args = append(args, "--scale", strconv.Itoa(r.Scale))
+	}
+	if r.Background != "" {
+		args = append(args, "--backgroundColor", string(r.Background))
+	}
+	if r.PuppeteerConfig != "" {
+		args = append(args, "--puppeteerConfigFile", r.PuppeteerConfig)
+	}
+	return
+}
+
+////////// BrowserRenderer /////////////////////////////////////////////////////
+
+// BrowserRenderer opens Diagrams in the OS's default browser, e.g. in the live
+// editor. Unset (zero value) fields use the defaults.
+type BrowserRenderer struct {
+	URLBuilder *URLBuilder   // Generates the URL, nil uses TargetLiveView
+	Runner     CommandRunner // Runs the browser command, nil uses OpenBrowser
+}
+
+// RenderDiagram generates the URL for d, opens it in the OS's default browser
+// and writes the URL to w. Without Runner it returns as soon as the browser
+// was started. Implements Renderer.
+func (r *BrowserRenderer) RenderDiagram(ctx context.Context, d Diagram,
+	w io.Writer) (err error) {
+	builder := r.URLBuilder
+	if builder == nil {
+		builder = &URLBuilder{Target: TargetLiveView}
+	}
+	url, err := builder.URL(d)
+	if err != nil {
+		return err
+	}
+	if r.Runner == nil {
+		err = OpenBrowser(url)
+	} else {
+		var name string
+		var args []string
+		if name, args, err = browserCommand(runtime.GOOS, url); err == nil {
+			err = r.Runner(ctx, name, args, nil, ioutil.Discard)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, url)
+	return
+}
+
+// Helperfunction to determine the command that opens url on the given OS.
+func browserCommand(goos, url string) (name string, args []string, err error) {
+	switch goos {
+	case "openbsd", "freebsd", "netbsd", "linux":
+		return "xdg-open", []string{url}, nil
+	case "darwin":
+		return "open", []string{url}, nil
+	case "windows":
+		return "rundll32", []string{"url.dll,FileProtocolHandler", url}, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported platform")
+	}
+}
+
+////////// FakeRenderer ////////////////////////////////////////////////////////
+
+// FakeRenderer is a Renderer for tests. It records the code of all rendered
+// Diagrams and writes Output to w, or returns Err if set.
+type FakeRenderer struct {
+	Output   []byte   // Written to w for every Diagram
+	Err      error    // Returned instead of rendering if set
+	Rendered []string // The mermaid code of all rendered Diagrams
+}
+
+// RenderDiagram records the code of d and writes Output to w.
+// Implements Renderer.
+func (r *FakeRenderer) RenderDiagram(ctx context.Context, d Diagram,
+	w io.Writer) (err error) {
+	if r.Err != nil {
+		return r.Err
+	}
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+	code, err := RenderString(d)
+	if err != nil {
+		return err
+	}
+	r.Rendered = append(r.Rendered, code)
+	_, err = w.Write(r.Output)
+	return
+}
@@ -0,0 +1,139 @@
+package mermaidgen_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/flowchart"
+)
+
+// Rendering images via mermaid-cli
+func ExampleMMDCRenderer() {
+	f := flowchart.NewFlowchart()
+	f.AddEdge(f.AddNode("n1"), f.AddNode("n2"))
+	r, _ := mermaidgen.NewMMDCRenderer(mermaidgen.FormatPNG,
+		"/usr/local/bin/mmdc")
+	r.Width = 1024
+	r.Background = "transparent"
+	r.Timeout = time.Minute
+	// Runner is replaced here to show the command, leave it nil to run mmdc
+	r.Runner = func(ctx context.Context, name string, args []string,
+		stdin io.Reader, stdout io.Writer) error {
+		_, hasDeadline := ctx.Deadline()
+		fmt.Println(name, hasDeadline)
+		fmt.Println(strings.Join(args[5:], " "))
+		return ioutil.WriteFile(args[4], []byte("PNG data"), 0600)
+	}
+	var b bytes.Buffer
+	err := r.RenderDiagram(context.Background(), f, &b)
+	fmt.Println(b.String(), err)
+	//Output:
+	///usr/local/bin/mmdc true
+	//--outputFormat png --width 1024 --backgroundColor transparent
+	//PNG data <nil>
+}
+
+// Using a FakeRenderer in tests
+func ExampleFakeRenderer() {
+	f := flowchart.NewFlowchart()
+	f.AddNode("n1")
+	r := &mermaidgen.FakeRenderer{Output: []byte("<svg/>")}
+	var b bytes.Buffer
+	// code that accepts a Renderer can be tested without mmdc
+	var renderer mermaidgen.Renderer = r
+	err := renderer.RenderDiagram(context.Background(), f, &b)
+	fmt.Println(b.String(), err)
+	fmt.Printf("%q\n", r.Rendered)
+	//Output:
+	//<svg/> <nil>
+	//["graph TB\nn1[\"n1\"]\n"]
+}
+
+func TestMMDCRenderer_RenderDiagram(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	f.AddNode("n1")
+	for _, stdin := range []bool{false, true} {
+		r, _ := mermaidgen.NewMMDCRenderer()
+		r.Stdin = stdin
+		r.PuppeteerConfig = "puppeteer.json"
+		r.Runner = func(ctx context.Context, name string, args []string,
+			in io.Reader, out io.Writer) error {
+			input, output := args[2], args[4]
+			var code []byte
+			var err error
+			if stdin {
+				if input != "-" {
+					t.Errorf("expected stdin input, got %q", input)
+				}
+				code, err = ioutil.ReadAll(in)
+			} else {
+				code, err = ioutil.ReadFile(input)
+			}
+			if err != nil || string(code) != f.String() {
+				t.Errorf("unexpected input %q %v", code, err)
+			}
+			if !strings.HasSuffix(output, ".svg") || name != "mmdc" ||
+				args[len(args)-1] != "puppeteer.json" {
+				t.Errorf("unexpected command %s %q", name, args)
+			}
+			return ioutil.WriteFile(output, []byte("<svg/>"), 0600)
+		}
+		var b bytes.Buffer
+		if err := r.RenderDiagram(context.Background(), f, &b); err != nil ||
+			b.String() != "<svg/>" {
+			t.Errorf("unexpected result %q %v", b.String(), err)
+		}
+	}
+	// errors of the command and missing output are reported
+	r, _ := mermaidgen.NewMMDCRenderer()
+	r.Runner = func(context.Context, string, []string, io.Reader,
+		io.Writer) error {
+		return nil
+	}
+	if err := r.RenderDiagram(context.Background(), f,
+		ioutil.Discard); err == nil {
+		t.Error("no error for missing output")
+	}
+	r.Binary = "/nonexistent/mmdc"
+	r.Runner = nil
+	if err := r.RenderDiagram(context.Background(), f,
+		ioutil.Discard); err == nil {
+		t.Error("no error for missing binary")
+	}
+	if _, err := mermaidgen.NewMMDCRenderer(1); err == nil {
+		t.Error("no error for invalid Format")
+	}
+	if _, err := mermaidgen.NewMMDCRenderer(mermaidgen.FormatPDF, 1); err == nil {
+		t.Error("no error for invalid Binary")
+	}
+}
+
+func TestBrowserRenderer_RenderDiagram(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	f.AddNode("n1")
+	builder, _ := mermaidgen.NewURLBuilder(mermaidgen.TargetLiveEdit,
+		"https://live.example.com")
+	var opened []string
+	r := &mermaidgen.BrowserRenderer{URLBuilder: builder,
+		Runner: func(ctx context.Context, name string, args []string,
+			in io.Reader, out io.Writer) error {
+			opened = append(opened, args[len(args)-1])
+			return nil
+		}}
+	var b bytes.Buffer
+	if err := r.RenderDiagram(context.Background(), f, &b); err != nil {
+		// unsupported platform
+		t.Skip(err)
+	}
+	url, _ := builder.URL(f)
+	if b.String() != url || len(opened) != 1 || opened[0] != url {
+		t.Errorf("unexpected result %q %q", b.String(), opened)
+	}
+}
@@ -0,0 +1,127 @@
+package mermaidgen
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Default base URLs of the public renderer services, see URLBuilder.
+const (
+	MermaidInkURL = `https://mermaid.ink`
+	KrokiURL      = `https://kroki.io`
+)
+
+////////// URLTarget ///////////////////////////////////////////////////////////
+
+type urlTarget string
+
+// Target definitions for URLBuilder. New URLBuilders get TargetLiveView as the
+// default. The mermaid.ink image target renders JPEG, PNG can be requested by
+// appending "?type=png" to the URL.
+const (
+	TargetLiveView urlTarget = `live/view` // live editor, view only
+	TargetLiveEdit urlTarget = `live/edit` // live editor, editable
+	TargetInkImage urlTarget = `ink/img`   // mermaid.ink image
+	TargetInkSVG   urlTarget = `ink/svg`   // mermaid.ink SVG
+	TargetInkPDF   urlTarget = `ink/pdf`   // mermaid.ink PDF
+	TargetKrokiSVG urlTarget = `kroki/svg` // Kroki SVG
+	TargetKrokiPNG urlTarget = `kroki/png` // Kroki PNG
+	TargetKrokiPDF urlTarget = `kroki/pdf` // Kroki PDF
+)
+
+////////// URLBuilder //////////////////////////////////////////////////////////
+
+// URLBuilder generates URLs that show a Diagram in the live editor or render
+// it via mermaid.ink or Kroki. The live editor and mermaid.ink get the code
+// and the Config as "pako:" payload (see PakoEncode), Kroki gets the code as
+// zlib compressed base64url (see KrokiEncode), the Config is part of the code.
+// Create an instance of URLBuilder via its constructor NewURLBuilder, do not
+// create instances directly.
+type URLBuilder struct {
+	Target  urlTarget // The service and endpoint to generate URLs for
+	BaseURL string    // e.g. a self-hosted instance, if empty the public one
+}
+
+// NewURLBuilder is the constructor used to create a new URLBuilder object.
+// Optional initializer parameters can be given in the order Target, BaseURL.
+func NewURLBuilder(init ...interface{}) (newURLBuilder *URLBuilder, err error) {
+	b := &URLBuilder{Target: TargetLiveView}
+	switch l := len(init); {
+	case l > 1:
+		baseURL, ok := init[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("value for BaseURL was no string")
+		}
+		b.BaseURL = baseURL
+		fallthrough
+	case l > 0:
+		switch v := init[0].(type) {
+		case urlTarget:
+			b.Target = v
+		case string:
+			b.Target = urlTarget(v)
+		default:
+			return nil, fmt.Errorf("value for Target was no urlTarget")
+		}
+	}
+	return b, nil
+}
+
+// URL renders the Diagram and generates a URL for it according to Target.
+// An error is returned if rendering fails or Target is unknown.
+func (b *URLBuilder) URL(d Diagram) (url string, err error) {
+	code, err := RenderString(d)
+	if err != nil {
+		return "", err
+	}
+	return b.CodeURL(code, d.GetConfig())
+}
+
+// CodeURL generates a URL for already rendered mermaid code according to
+// Target. The Config is only used for the live editor and mermaid.ink targets,
+// nil yields the default theme. An error is returned if Target is unknown.
+func (b *URLBuilder) CodeURL(code string, config *Config) (url string, err error) {
+	service := strings.SplitN(string(b.Target), "/", 2)
+	if len(service) != 2 {
+		return "", fmt.Errorf("CodeURL: unknown Target %q", b.Target)
+	}
+	base := strings.TrimRight(b.BaseURL, "/")
+	switch b.Target {
+	case TargetLiveView, TargetLiveEdit:
+		if base == "" {
+			base = LiveEditorURL
+		}
+		return base + "/" + service[1] + "/#" + PakoEncode(code, config), nil
+	case TargetInkImage, TargetInkSVG, TargetInkPDF:
+		if base == "" {
+			base = MermaidInkURL
+		}
+		return base + "/" + service[1] + "/" + PakoEncode(code, config), nil
+	case TargetKrokiSVG, TargetKrokiPNG, TargetKrokiPDF:
+		if base == "" {
+			base = KrokiURL
+		}
+		return base + "/mermaid/" + service[1] + "/" + KrokiEncode(code), nil
+	}
+	return "", fmt.Errorf("CodeURL: unknown Target %q", b.Target)
+}
+
+// KrokiEncode generates the payload used by Kroki's GET API from mermaid code:
+// zlib compressed and base64url encoded.
+func KrokiEncode(code string) (payload string) {
+	var b bytes.Buffer
+	w, _ := zlib.NewWriterLevel(&b, zlib.BestCompression)
+	w.Write([]byte(code))
+	w.Close()
+	return base64.URLEncoding.EncodeToString(b.Bytes())
+}
+
+// KrokiDecode reverts KrokiEncode, it returns the mermaid code of a Kroki
+// payload.
+func KrokiDecode(payload string) (code string, err error) {
+	data, err := zlibDecode(payload)
+	return string(data), err
+}
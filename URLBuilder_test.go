@@ -0,0 +1,81 @@
+package mermaidgen_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/flowchart"
+)
+
+// Generating URLs for other targets and self-hosted instances
+func ExampleURLBuilder() {
+	f := flowchart.NewFlowchart()
+	f.AddEdge(f.AddNode("n1"), f.AddNode("n2"))
+	// the public live editor in edit mode
+	b, _ := mermaidgen.NewURLBuilder(mermaidgen.TargetLiveEdit)
+	url, err := b.URL(f)
+	fmt.Println(url, err)
+	// a self-hosted Kroki instance
+	b, _ = mermaidgen.NewURLBuilder(mermaidgen.TargetKrokiSVG,
+		"https://kroki.example.com/")
+	url, err = b.URL(f)
+	fmt.Println(url, err)
+	// unknown targets are reported
+	b.Target = "ink/gif"
+	url, err = b.URL(f)
+	fmt.Printf("%q %s\n", url, err)
+	//Output:
+	//https://mermaid.live/edit/#pako:eNqqVkrOT0lVslJKL0osyFAIcYrJyzOMjlHKM4xRio3JyzMCsY0gbEMFXd2YUgMD41SFPKOYPCUdpdzUotzEzBQlq2qlkozUXJA5KalpiaU5JUq1tYABACEwHQk= <nil>
+	//https://kroki.example.com/mermaid/svg/eNpKL0osyFAIceLKM4xWyjNUiuXKM4pWyjMCMQwVdHXtFPKMuAADAMe7CX4= <nil>
+	//"" CodeURL: unknown Target "ink/gif"
+}
+
+func TestURLBuilder_CodeURL(t *testing.T) {
+	code := "graph TB\nn1 --> n2\n"
+	config := &mermaidgen.Config{Theme: mermaidgen.ThemeDark}
+	for target, prefix := range map[string]string{
+		"live/view": "https://mermaid.live/view/#pako:",
+		"live/edit": "https://mermaid.live/edit/#pako:",
+		"ink/img":   "https://mermaid.ink/img/pako:",
+		"ink/svg":   "https://mermaid.ink/svg/pako:",
+		"ink/pdf":   "https://mermaid.ink/pdf/pako:",
+		"kroki/svg": "https://kroki.io/mermaid/svg/",
+		"kroki/png": "https://kroki.io/mermaid/png/",
+		"kroki/pdf": "https://kroki.io/mermaid/pdf/",
+	} {
+		b, err := mermaidgen.NewURLBuilder(target)
+		if err != nil {
+			t.Fatal(err)
+		}
+		url, err := b.CodeURL(code, config)
+		if err != nil || !strings.HasPrefix(url, prefix) {
+			t.Errorf("%s: unexpected result %q %v", target, url, err)
+			continue
+		}
+		// encoding works offline, so verify it by decoding
+		payload := strings.TrimPrefix(url, prefix)
+		if strings.HasPrefix(target, "kroki") {
+			decoded, err := mermaidgen.KrokiDecode(payload)
+			if err != nil || decoded != code {
+				t.Errorf("%s: decoded %q %v", target, decoded, err)
+			}
+			continue
+		}
+		decoded, decodedConfig, err := mermaidgen.DecodeLiveURL(
+			"https://mermaid.live/view#pako:" + payload)
+		if err != nil || decoded != code || decodedConfig.Theme != config.Theme {
+			t.Errorf("%s: decoded %q %v %v", target, decoded, decodedConfig, err)
+		}
+	}
+	if _, err := mermaidgen.NewURLBuilder(1); err == nil {
+		t.Error("no error for invalid Target")
+	}
+	if _, err := mermaidgen.NewURLBuilder("live/view", 1); err == nil {
+		t.Error("no error for invalid BaseURL")
+	}
+	if _, err := (&mermaidgen.URLBuilder{}).CodeURL(code, nil); err == nil {
+		t.Error("no error for empty Target")
+	}
+}
@@ -0,0 +1,114 @@
+package classdiagram
+
+import (
+	"bufio"
+	"strings"
+)
+
+type classAnnotation string
+
+// Annotation definitions for Classes as described at
+// https://mermaid.js.org/syntax/classDiagram.html#annotations-on-classes.
+// Any other text can be used as annotation, too.
+const (
+	AnnotationInterface   classAnnotation = `interface`
+	AnnotationAbstract    classAnnotation = `abstract`
+	AnnotationService     classAnnotation = `service`
+	AnnotationEnumeration classAnnotation = `enumeration`
+)
+
+// Class represents a single, unique class (or interface, struct, ...) of the
+// ClassDiagram. Create an instance of Class via ClassDiagram's AddClass method,
+// do not create instances directly. Already defined IDs can be looked up via
+// ClassDiagram's GetClass method or iterated over via its ListClasses method.
+type Class struct {
+	id          string
+	diagram     *ClassDiagram     // top lvl pointer
+	Label       string            // The displayed name, ID if not set.
+	Generic     string            // Optional type parameters, e.g. "T".
+	Annotations []classAnnotation // Optional annotations like <<interface>>.
+	Members     []*Member         // The attributes of this Class.
+	Methods     []*Method         // The operations of this Class.
+	Comment     string            // Optional %% comment rendered above.
+}
+
+// ID provides access to the Class's readonly field id.
+func (c *Class) ID() (id string) {
+	return c.id
+}
+
+// ClassDiagram provides access to the top level ClassDiagram to be able to
+// access Adder, Getter and Lister methods.
+func (c *Class) ClassDiagram() (topLevel *ClassDiagram) {
+	return c.diagram
+}
+
+// AddMember is used to add a new attribute with the given name and type to the
+// Class. The type may be empty.
+func (c *Class) AddMember(name, memberType string) (newMember *Member) {
+	newMember = &Member{Name: name, Type: memberType,
+		Visibility: VisibilityPublic}
+	c.Members = append(c.Members, newMember)
+	return
+}
+
+// AddMethod is used to add a new operation with the given name to the Class.
+// Optional initializer parameters can be given in the order ReturnType,
+// Parameters (a string for each).
+func (c *Class) AddMethod(name string, init ...string) (newMethod *Method) {
+	newMethod = &Method{Name: name, Visibility: VisibilityPublic}
+	if len(init) > 0 {
+		newMethod.ReturnType = init[0]
+		newMethod.Parameters = init[1:]
+	}
+	c.Methods = append(c.Methods, newMethod)
+	return
+}
+
+// AddAnnotations adds one or more annotations, each renders to an
+// <<annotation>> line.
+func (c *Class) AddAnnotations(annotations ...classAnnotation) {
+	c.Annotations = append(c.Annotations, annotations...)
+}
+
+// String renders this diagram element to a class definition, followed by a
+// block with the annotations, Members and Methods if there are any.
+func (c *Class) String() (renderedElement string) {
+	var s strings.Builder
+	b := bufio.NewWriter(&s)
+	c.writeClass(b, c.diagram.Indent)
+	b.Flush()
+	return s.String()
+}
+
+// Helperfunction for String and ClassDiagram's WriteTo, renders the Class with
+// its body indented by indent.
+func (c *Class) writeClass(w *bufio.Writer, indent string) {
+	if c.Comment != "" {
+		for _, line := range strings.Split(c.Comment, "\n") {
+			w.WriteString(strings.TrimRight("%% "+line, " \r") + "\n")
+		}
+	}
+	w.WriteString("class " + c.id)
+	if c.Generic != "" {
+		w.WriteString("~" + c.Generic + "~")
+	}
+	if c.Label != "" {
+		w.WriteString(`["` + c.Label + `"]`)
+	}
+	if len(c.Annotations) == 0 && len(c.Members) == 0 && len(c.Methods) == 0 {
+		w.WriteString("\n")
+		return
+	}
+	w.WriteString(" {\n")
+	for _, a := range c.Annotations {
+		w.WriteString(indent + "<<" + string(a) + ">>\n")
+	}
+	for _, m := range c.Members {
+		w.WriteString(indent + m.String() + "\n")
+	}
+	for _, m := range c.Methods {
+		w.WriteString(indent + m.String() + "\n")
+	}
+	w.WriteString("}\n")
+}
@@ -0,0 +1,193 @@
+package classdiagram
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/internal/counting"
+)
+
+////////// DiagramDirection ////////////////////////////////////////////////////
+
+type diagramDirection string
+
+// Direction definitions for ClassDiagrams as described at
+// https://mermaid.js.org/syntax/classDiagram.html#setting-the-direction-of-the-diagram.
+// New ClassDiagrams get DirectionTopDown as the default.
+const (
+	DirectionTopDown   diagramDirection = `TB`
+	DirectionBottomUp  diagramDirection = `BT`
+	DirectionRightLeft diagramDirection = `RL`
+	DirectionLeftRight diagramDirection = `LR`
+)
+
+// IsValidID is used to check if Class IDs are valid: IsValidID(string) bool.
+// Use the Class's Label to display other names.
+var IsValidID = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`).MatchString
+
+////////// ClassDiagram ////////////////////////////////////////////////////////
+
+// ClassDiagram objects are the entrypoints to this package, the whole diagram
+// is constructed around a ClassDiagram object. Create an instance of
+// ClassDiagram via ClassDiagram's constructor NewClassDiagram, do not create
+// instances directly.
+type ClassDiagram struct {
+	classesMap    map[string]*Class  // lookup table for existing Classes
+	classes       []*Class           // Classes for ordered rendering
+	relationships []*Relationship    // Relationships for ordered rendering
+	Direction     diagramDirection   // The direction used to render the diagram.
+	Config        *mermaidgen.Config // Optional theme and configuration.
+	Indent        string             // Indentation of class bodies.
+}
+
+// NewClassDiagram is the constructor used to create a new ClassDiagram object.
+// This object is the entrypoint for any further interactions with your diagram.
+// Always use the constructor, don't create ClassDiagram objects directly.
+// An optional initializer parameter can be given for Direction.
+func NewClassDiagram(init ...interface{}) (newClassDiagram *ClassDiagram,
+	err error) {
+	cd := &ClassDiagram{Direction: DirectionTopDown, Indent: "  "}
+	cd.classesMap = make(map[string]*Class)
+	if len(init) > 0 {
+		switch v := init[0].(type) {
+		case diagramDirection:
+			cd.Direction = v
+		case string:
+			cd.Direction = diagramDirection(v)
+		default:
+			return nil, fmt.Errorf("value for Direction was no diagramDirection")
+		}
+	}
+	return cd, nil
+}
+
+// String recursively renders the whole diagram to mermaid code lines.
+// It is a shorthand for WriteTo with a strings.Builder.
+func (cd *ClassDiagram) String() (renderedElement string) {
+	var s strings.Builder
+	cd.WriteTo(&s)
+	return s.String()
+}
+
+// WriteTo renders the Classes with their members and the Relations to mermaid
+// code lines and writes them to w. Class bodies are indented by Indent.
+// Implements io.WriterTo.
+func (cd *ClassDiagram) WriteTo(w io.Writer) (n int64, err error) {
+	cw := &counting.Writer{W: w}
+	b := bufio.NewWriter(cw)
+	b.WriteString(cd.Config.String())
+	b.WriteString("classDiagram\n")
+	fmt.Fprintf(b, "direction %s\n", cd.Direction)
+	for _, c := range cd.classes {
+		c.writeClass(b, cd.Indent)
+	}
+	for _, r := range cd.relationships {
+		b.WriteString(r.String())
+	}
+	err = b.Flush()
+	return cw.N, err
+}
+
+// Render writes the mermaid code of the whole diagram to w.
+// Implements mermaidgen.Diagram.
+func (cd *ClassDiagram) Render(w io.Writer) (err error) {
+	_, err = cd.WriteTo(w)
+	return
+}
+
+// Kind returns mermaidgen.KindClassDiagram. Implements mermaidgen.Diagram.
+func (cd *ClassDiagram) Kind() (kind mermaidgen.DiagramKind) {
+	return mermaidgen.KindClassDiagram
+}
+
+// GetConfig returns the ClassDiagram's Config, which may be nil.
+// Implements mermaidgen.Diagram.
+func (cd *ClassDiagram) GetConfig() (config *mermaidgen.Config) {
+	return cd.Config
+}
+
+// SetConfig replaces the ClassDiagram's Config, nil removes it.
+// Implements mermaidgen.Diagram.
+func (cd *ClassDiagram) SetConfig(config *mermaidgen.Config) {
+	cd.Config = config
+}
+
+// LiveURL renders the ClassDiagram and generates a view URL for
+// https://mermaid.live from it, see mermaidgen.LiveURL for details.
+func (cd *ClassDiagram) LiveURL() (url string) {
+	url, _ = mermaidgen.LiveURL(cd)
+	return
+}
+
+// ViewInBrowser uses the URL generated by ClassDiagram's LiveURL method and
+// opens that URL in the OS's default browser via mermaidgen.ViewInBrowser. It
+// eventually returns any error occured.
+func (cd *ClassDiagram) ViewInBrowser() (err error) {
+	return mermaidgen.ViewInBrowser(cd)
+}
+
+////////// add Items ///////////////////////////////////////////////////////////
+
+// AddClass is used to add a new Class to the ClassDiagram. If the provided ID
+// already exists or is invalid, no new Class is created and an error is
+// returned. The ID can later be used to look up the created Class using
+// ClassDiagram's GetClass method.
+func (cd *ClassDiagram) AddClass(id string) (newClass *Class, err error) {
+	if !IsValidID(id) {
+		return nil, fmt.Errorf("invalid id")
+	}
+	if _, alreadyExists := cd.classesMap[id]; alreadyExists {
+		return nil, fmt.Errorf("id already exists")
+	}
+	newClass = &Class{id: id, diagram: cd}
+	cd.classesMap[id] = newClass
+	cd.classes = append(cd.classes, newClass)
+	return
+}
+
+// AddRelationship is used to add a new Relationship of the given type between
+// two Classes of this ClassDiagram, read as "from relates to to", e.g. from
+// inherits to for RelationInheritance. An error is returned if a Class doesn't
+// belong to this ClassDiagram.
+func (cd *ClassDiagram) AddRelationship(from, to *Class,
+	relation relationType) (newRelationship *Relationship, err error) {
+	if from == nil || to == nil || cd.classesMap[from.id] != from ||
+		cd.classesMap[to.id] != to {
+		return nil, fmt.Errorf("Class doesn't belong to the ClassDiagram")
+	}
+	newRelationship = &Relationship{From: from, To: to, Type: relation}
+	cd.relationships = append(cd.relationships, newRelationship)
+	return
+}
+
+////////// get Items ///////////////////////////////////////////////////////////
+
+// GetClass looks up a previously defined Class by its ID.
+// If this ID doesn't exist, nil is returned.
+// Use ClassDiagram's AddClass to create new Classes.
+func (cd *ClassDiagram) GetClass(id string) (existingClass *Class) {
+	// if not found -> nil
+	return cd.classesMap[id]
+}
+
+////////// list Items //////////////////////////////////////////////////////////
+
+// ListClasses returns a slice of all Classes previously added to this
+// ClassDiagram in the order they were defined.
+func (cd *ClassDiagram) ListClasses() (allClasses []*Class) {
+	allClasses = make([]*Class, len(cd.classes))
+	copy(allClasses, cd.classes)
+	return
+}
+
+// ListRelationships returns a slice of all Relationships previously added to
+// this ClassDiagram in the order they were defined.
+func (cd *ClassDiagram) ListRelationships() (allRelationships []*Relationship) {
+	allRelationships = make([]*Relationship, len(cd.relationships))
+	copy(allRelationships, cd.relationships)
+	return
+}
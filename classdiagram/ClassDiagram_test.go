@@ -0,0 +1,91 @@
+package classdiagram_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen/classdiagram"
+)
+
+// Defining and rendering a class diagram
+func ExampleClassDiagram() {
+	cd, _ := classdiagram.NewClassDiagram(classdiagram.DirectionLeftRight)
+	// an interface
+	shape, _ := cd.AddClass("Shape")
+	shape.AddAnnotations(classdiagram.AnnotationInterface)
+	shape.AddMethod("Area", "float64")
+	// a class with members and methods
+	circle, _ := cd.AddClass("Circle")
+	circle.AddMember("radius", "float64").Visibility =
+		classdiagram.VisibilityPrivate
+	circle.AddMethod("Area", "float64")
+	circle.AddMethod("New", "*Circle", "r float64").Static = true
+	// a generic class
+	list, _ := cd.AddClass("List")
+	list.Generic = "T"
+	list.AddMember("Items", "[]T")
+	// relationships
+	cd.AddRelationship(circle, shape, classdiagram.RelationRealization)
+	r, _ := cd.AddRelationship(list, shape, classdiagram.RelationAggregation)
+	r.Label = "contains"
+	r.FromCardinality = classdiagram.CardinalityOne
+	r.ToCardinality = classdiagram.CardinalityMany
+	fmt.Print(cd)
+	//Output:
+	//classDiagram
+	//direction LR
+	//class Shape {
+	//   <<interface>>
+	//   +Area() float64
+	//}
+	//class Circle {
+	//   -float64 radius
+	//   +Area() float64
+	//   +New(r float64) *Circle$
+	//}
+	//class List~T~ {
+	//   +[]T Items
+	//}
+	//Circle ..|> Shape
+	//List "1" o-- "*" Shape : contains
+}
+
+func TestClassDiagram_AddClass(t *testing.T) {
+	cd, _ := classdiagram.NewClassDiagram()
+	if _, err := cd.AddClass("A"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cd.AddClass("A"); err == nil {
+		t.Error("duplicate id not detected")
+	}
+	if _, err := cd.AddClass("1A"); err == nil {
+		t.Error("invalid id not detected")
+	}
+	other, _ := classdiagram.NewClassDiagram()
+	b, _ := other.AddClass("B")
+	if _, err := cd.AddRelationship(cd.GetClass("A"), b,
+		classdiagram.RelationLink); err == nil {
+		t.Error("foreign Class not detected")
+	}
+	if _, err := classdiagram.NewClassDiagram(1); err == nil {
+		t.Error("invalid Direction not detected")
+	}
+	if s := cd.GetClass("A").String(); s != "class A\n" {
+		t.Errorf("unexpected %q", s)
+	}
+}
+
+func TestClassDiagram_WriteTo(t *testing.T) {
+	cd, _ := classdiagram.NewClassDiagram()
+	cd.Indent = "\t"
+	c, _ := cd.AddClass("A")
+	c.Comment = "the A"
+	c.AddMember("X", "int")
+	var b strings.Builder
+	n, err := cd.WriteTo(&b)
+	expected := "classDiagram\ndirection TB\n%% the A\nclass A {\n\t+int X\n}\n"
+	if err != nil || b.String() != expected || n != int64(len(expected)) {
+		t.Errorf("unexpected %d %v %q", n, err, b.String())
+	}
+}
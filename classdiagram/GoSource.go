@@ -0,0 +1,361 @@
+package classdiagram
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+)
+
+// GoOptions control which parts of a Go package FromGoPackage puts into the
+// ClassDiagram. A nil *GoOptions uses the zero value.
+type GoOptions struct {
+	Unexported   bool // Include unexported types, fields and methods.
+	Dependencies bool // Add RelationDependency for method signatures.
+}
+
+// FromGoPackage loads the Go package in directory dir from source, type checks
+// it with go/types and creates a ClassDiagram from its named types:
+//
+// Structs become Classes with their fields as Members, interfaces become
+// Classes annotated <<interface>>, other named types are annotated with their
+// underlying type. Methods are added to their receiver's Class, type
+// parameters to the Class's Generic field.
+//
+// Embedded structs are rendered as RelationComposition (RelationAggregation
+// if embedded by pointer), embedded interfaces as RelationInheritance. Fields
+// with a type of the package are rendered as RelationComposition (values),
+// RelationAssociation (pointers) or RelationAggregation with ToCardinality
+// "*" (slices, arrays, maps). Types whose method set satisfies an interface of
+// the package get a RelationRealization to that interface.
+//
+// Test files and files excluded by build constraints are skipped. Imported
+// packages are type checked from source, too; type errors (e.g. unresolvable
+// imports) are tolerated as long as the package itself can be parsed.
+func FromGoPackage(dir string, options *GoOptions) (newClassDiagram *ClassDiagram,
+	err error) {
+	if options == nil {
+		options = &GoOptions{}
+	}
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, fmt.Errorf("FromGoPackage: %v", err)
+	}
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(bp.GoFiles))
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("FromGoPackage: %v", err)
+		}
+		files = append(files, f)
+	}
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {}, // keep going on type errors
+	}
+	pkg, _ := conf.Check(bp.Name, fset, files, nil)
+	g := &goGenerator{pkg: pkg, options: options}
+	g.cd, _ = NewClassDiagram()
+	// source order of the type declarations
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				g.addType(spec.(*ast.TypeSpec).Name.Name)
+			}
+		}
+	}
+	for _, name := range g.names {
+		g.fillClass(name)
+	}
+	g.addRealizations()
+	return g.cd, nil
+}
+
+////////// goGenerator /////////////////////////////////////////////////////////
+
+// State of a single FromGoPackage run.
+type goGenerator struct {
+	pkg     *types.Package
+	options *GoOptions
+	cd      *ClassDiagram
+	names   []*types.TypeName  // included types in source order
+	seen    map[[2]*Class]bool // dependencies already added
+	classes map[*types.TypeName]*Class
+}
+
+// Helperfunction to create the (empty) Class for the type name if included.
+func (g *goGenerator) addType(name string) {
+	tn, ok := g.pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok || !g.include(name) {
+		return
+	}
+	c, err := g.cd.AddClass(name)
+	if err != nil {
+		return
+	}
+	if g.classes == nil {
+		g.classes = make(map[*types.TypeName]*Class)
+	}
+	g.classes[tn] = c
+	g.names = append(g.names, tn)
+	if named, ok := tn.Type().(*types.Named); ok {
+		params := named.TypeParams()
+		names := make([]string, params.Len())
+		for i := range names {
+			names[i] = params.At(i).Obj().Name()
+		}
+		c.Generic = strings.Join(names, ", ")
+	}
+}
+
+// Helperfunction to check whether an identifier is included by the options.
+func (g *goGenerator) include(name string) bool {
+	return g.options.Unexported || ast.IsExported(name)
+}
+
+// Helperfunction to get the visibility of an identifier.
+func (g *goGenerator) visibility(name string) visibility {
+	if ast.IsExported(name) {
+		return VisibilityPublic
+	}
+	return VisibilityPackage
+}
+
+// Helperfunction to add the members, methods and relationships of a type.
+func (g *goGenerator) fillClass(tn *types.TypeName) {
+	c := g.classes[tn]
+	switch u := tn.Type().Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			g.addField(c, u.Field(i))
+		}
+	case *types.Interface:
+		c.AddAnnotations(AnnotationInterface)
+		for i := 0; i < u.NumEmbeddeds(); i++ {
+			if to := g.classOf(u.EmbeddedType(i)); to != nil {
+				r, _ := g.cd.AddRelationship(c, to, RelationInheritance)
+				r.Label = "embeds"
+			}
+		}
+		for i := 0; i < u.NumExplicitMethods(); i++ {
+			g.addMethod(c, u.ExplicitMethod(i))
+		}
+		return
+	default:
+		c.AddAnnotations(classAnnotation(g.typeString(u)))
+	}
+	if named, ok := tn.Type().(*types.Named); ok {
+		for i := 0; i < named.NumMethods(); i++ {
+			g.addMethod(c, named.Method(i))
+		}
+	}
+}
+
+// Helperfunction to add a struct field as Member or Relationship.
+func (g *goGenerator) addField(c *Class, field *types.Var) {
+	if field.Embedded() {
+		relation := RelationComposition
+		t := field.Type()
+		if p, ok := t.(*types.Pointer); ok {
+			relation, t = RelationAggregation, p.Elem()
+		}
+		if to := g.classOf(t); to != nil {
+			r, _ := g.cd.AddRelationship(c, to, relation)
+			r.Label = "embeds"
+			return
+		}
+		// embedded foreign type is rendered as member named by its type
+		m := c.AddMember(g.typeString(field.Type()), "")
+		m.Visibility = g.visibility(field.Name())
+		return
+	}
+	if !g.include(field.Name()) {
+		return
+	}
+	m := c.AddMember(field.Name(), g.typeString(field.Type()))
+	m.Visibility = g.visibility(field.Name())
+	var relation relationType
+	var cardinality string
+	t := field.Type()
+	switch v := t.(type) {
+	case *types.Pointer:
+		relation, t = RelationAssociation, v.Elem()
+	case *types.Slice:
+		relation, t, cardinality = RelationAggregation, v.Elem(), CardinalityMany
+	case *types.Array:
+		relation, t, cardinality = RelationAggregation, v.Elem(), CardinalityMany
+	case *types.Map:
+		relation, t, cardinality = RelationAggregation, v.Elem(), CardinalityMany
+	default:
+		relation = RelationComposition
+	}
+	if p, ok := t.(*types.Pointer); ok && cardinality != "" {
+		t = p.Elem()
+	}
+	if to := g.classOf(t); to != nil {
+		r, _ := g.cd.AddRelationship(c, to, relation)
+		r.Label = field.Name()
+		r.ToCardinality = cardinality
+	}
+}
+
+// Helperfunction to add a method (or interface method) to the Class.
+func (g *goGenerator) addMethod(c *Class, fn *types.Func) {
+	if !g.include(fn.Name()) {
+		return
+	}
+	sig := fn.Type().(*types.Signature)
+	params := make([]string, sig.Params().Len())
+	for i := range params {
+		p := sig.Params().At(i)
+		t := g.typeString(p.Type())
+		if sig.Variadic() && i == len(params)-1 {
+			t = "..." + strings.TrimPrefix(t, "[]")
+		}
+		params[i] = strings.TrimSpace(p.Name() + " " + t)
+		g.addDependency(c, p.Type())
+	}
+	results := make([]string, sig.Results().Len())
+	for i := range results {
+		results[i] = g.typeString(sig.Results().At(i).Type())
+		g.addDependency(c, sig.Results().At(i).Type())
+	}
+	// mermaid can't handle parentheses around multiple results
+	returnType := strings.Join(results, ", ")
+	m := c.AddMethod(fn.Name(), append([]string{returnType}, params...)...)
+	m.Visibility = g.visibility(fn.Name())
+}
+
+// Helperfunction to add a RelationDependency once per pair of Classes if
+// enabled by the options.
+func (g *goGenerator) addDependency(c *Class, t types.Type) {
+	if !g.options.Dependencies {
+		return
+	}
+	for {
+		switch v := t.(type) {
+		case *types.Pointer:
+			t = v.Elem()
+			continue
+		case *types.Slice:
+			t = v.Elem()
+			continue
+		case *types.Array:
+			t = v.Elem()
+			continue
+		case *types.Map:
+			t = v.Elem()
+			continue
+		}
+		break
+	}
+	to := g.classOf(t)
+	if to == nil || to == c {
+		return
+	}
+	if g.seen == nil {
+		g.seen = make(map[[2]*Class]bool)
+	}
+	if !g.seen[[2]*Class{c, to}] {
+		g.seen[[2]*Class{c, to}] = true
+		g.cd.AddRelationship(c, to, RelationDependency)
+	}
+}
+
+// Helperfunction to add a RelationRealization for each pair of a non interface
+// type and a non empty interface it satisfies (by value or pointer).
+func (g *goGenerator) addRealizations() {
+	for _, itn := range g.names {
+		iface, ok := itn.Type().Underlying().(*types.Interface)
+		if !ok || iface.NumMethods() == 0 || isGeneric(itn.Type()) {
+			continue
+		}
+		for _, tn := range g.names {
+			t := tn.Type()
+			if types.IsInterface(t) || isGeneric(t) {
+				continue
+			}
+			if types.Implements(t, iface) ||
+				types.Implements(types.NewPointer(t), iface) {
+				g.cd.AddRelationship(g.classes[tn], g.classes[itn],
+					RelationRealization)
+			}
+		}
+	}
+}
+
+// Helperfunction to check whether a type has type parameters.
+func isGeneric(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.TypeParams().Len() > 0
+}
+
+// Helperfunction to look up the Class of a named type of the package.
+// Instances of generic types resolve to the generic Class.
+func (g *goGenerator) classOf(t types.Type) *Class {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return nil
+	}
+	return g.classes[named.Origin().Obj()]
+}
+
+// Helperfunction to render a type the way mermaid expects it: generics are
+// written as Name~Args~ and anonymous structs and interfaces are shortened,
+// because braces are not allowed in class bodies. Types of the package itself
+// are unqualified.
+func (g *goGenerator) typeString(t types.Type) string {
+	switch v := t.(type) {
+	case *types.Named:
+		s := v.Obj().Name()
+		if pkg := v.Obj().Pkg(); pkg != nil && pkg != g.pkg {
+			s = pkg.Name() + "." + s
+		}
+		if args := v.TypeArgs(); args.Len() > 0 {
+			list := make([]string, args.Len())
+			for i := range list {
+				list[i] = g.typeString(args.At(i))
+			}
+			s += "~" + strings.Join(list, ", ") + "~"
+		}
+		return s
+	case *types.TypeParam:
+		return v.Obj().Name()
+	case *types.Pointer:
+		return "*" + g.typeString(v.Elem())
+	case *types.Slice:
+		return "[]" + g.typeString(v.Elem())
+	case *types.Array:
+		return fmt.Sprintf("[%d]%s", v.Len(), g.typeString(v.Elem()))
+	case *types.Map:
+		return "map[" + g.typeString(v.Key()) + "]" + g.typeString(v.Elem())
+	case *types.Chan:
+		switch v.Dir() {
+		case types.SendOnly:
+			return "chan<- " + g.typeString(v.Elem())
+		case types.RecvOnly:
+			return "<-chan " + g.typeString(v.Elem())
+		}
+		return "chan " + g.typeString(v.Elem())
+	case *types.Signature:
+		return "func"
+	case *types.Struct:
+		return "struct"
+	case *types.Interface:
+		if v.Empty() {
+			return "any"
+		}
+		return "interface"
+	}
+	return types.TypeString(t, types.RelativeTo(g.pkg))
+}
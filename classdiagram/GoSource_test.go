@@ -0,0 +1,133 @@
+package classdiagram_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen/classdiagram"
+)
+
+// Helperfunction to write a Go package to a temporary directory.
+func writePackage(files map[string]string) (dir string) {
+	dir, _ = ioutil.TempDir("", "classdiagram")
+	for name, content := range files {
+		ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+	}
+	return
+}
+
+const shapesSource = `package shapes
+
+import "io"
+
+// Shape is anything with an area.
+type Shape interface {
+	Area() float64
+}
+
+type Point struct {
+	X, Y int
+}
+
+type Circle struct {
+	Point
+	Radius float64
+	label  string
+}
+
+func (c Circle) Area() float64 { return 3 * c.Radius * c.Radius }
+
+type Group[T Shape] struct {
+	Items  []T
+	Parent *Group[T]
+	io.Writer
+}
+
+type Canvas struct {
+	Shapes map[string]Shape
+	Origin Point
+}
+
+func (c *Canvas) Draw(s ...Shape) error { return nil }
+`
+
+// Generating a class diagram from the source code of a Go package
+func ExampleFromGoPackage() {
+	dir := writePackage(map[string]string{"shapes.go": shapesSource})
+	defer os.RemoveAll(dir)
+	cd, _ := classdiagram.FromGoPackage(dir, nil)
+	fmt.Print(cd)
+	//Output:
+	//classDiagram
+	//direction TB
+	//class Shape {
+	//   <<interface>>
+	//   +Area() float64
+	//}
+	//class Point {
+	//   +int X
+	//   +int Y
+	//}
+	//class Circle {
+	//   +float64 Radius
+	//   +Area() float64
+	//}
+	//class Group~T~ {
+	//   +[]T Items
+	//   +*Group~T~ Parent
+	//   +io.Writer
+	//}
+	//class Canvas {
+	//   +map[string]Shape Shapes
+	//   +Point Origin
+	//   +Draw(s ...Shape) error
+	//}
+	//Circle *-- Point : embeds
+	//Group --> Group : Parent
+	//Canvas o-- "*" Shape : Shapes
+	//Canvas *-- Point : Origin
+	//Circle ..|> Shape
+}
+
+func TestFromGoPackage(t *testing.T) {
+	dir := writePackage(map[string]string{
+		"shapes.go":      shapesSource,
+		"shapes_test.go": "package shapes\n\ntype Tested struct{}\n",
+		"writer.go": "package shapes\n\nimport \"io\"\n\n" +
+			"func (c *Canvas) WriteTo(w io.Writer) (int64, error) { return 0, nil }\n",
+		"ignored.go": "//go:build ignore\n\npackage shapes\n\ntype Ignored struct{}\n",
+	})
+	defer os.RemoveAll(dir)
+	cd, err := classdiagram.FromGoPackage(dir, &classdiagram.GoOptions{
+		Unexported: true, Dependencies: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := cd.String()
+	for _, expected := range []string{
+		"  ~string label\n",
+		"Canvas ..> Shape\n",
+		"Group~T~",
+		"  +WriteTo(w io.Writer) int64, error\n",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("%q missing in:\n%s", expected, code)
+		}
+	}
+	if cd.GetClass("Tested") != nil || cd.GetClass("Ignored") != nil {
+		t.Error("test files or ignored files were loaded")
+	}
+	if _, err := classdiagram.FromGoPackage(filepath.Join(dir, "missing"),
+		nil); err == nil {
+		t.Error("missing directory not detected")
+	}
+	broken := writePackage(map[string]string{"broken.go": "package broken\n\ntype {\n"})
+	defer os.RemoveAll(broken)
+	if _, err := classdiagram.FromGoPackage(broken, nil); err == nil {
+		t.Error("syntax error not detected")
+	}
+}
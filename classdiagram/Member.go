@@ -0,0 +1,68 @@
+package classdiagram
+
+import (
+	"strings"
+)
+
+type visibility string
+
+// Visibility definitions for Members and Methods as described at
+// https://mermaid.js.org/syntax/classDiagram.html#visibility.
+// Members and Methods get VisibilityPublic as the default.
+const (
+	VisibilityNone      visibility = ``
+	VisibilityPublic    visibility = `+`
+	VisibilityPrivate   visibility = `-`
+	VisibilityProtected visibility = `#`
+	VisibilityPackage   visibility = `~`
+)
+
+// Member represents an attribute of a Class. It renders to a line like
+// "+string Name". Create an instance of Member via Class's AddMember method.
+type Member struct {
+	Name       string     // The name of the attribute.
+	Type       string     // Optional type, generics are written like List~int~.
+	Visibility visibility // The visibility marker.
+	Static     bool       // Renders the $ classifier.
+}
+
+// String renders this diagram element to a member line.
+func (m *Member) String() (renderedElement string) {
+	renderedElement = string(m.Visibility)
+	if m.Type != "" {
+		renderedElement += m.Type + " "
+	}
+	renderedElement += m.Name
+	if m.Static {
+		renderedElement += "$"
+	}
+	return
+}
+
+// Method represents an operation of a Class. It renders to a line like
+// "+Add(id string) *Node". Create an instance of Method via Class's AddMethod
+// method.
+type Method struct {
+	Name       string     // The name of the operation.
+	Parameters []string   // Optional parameters, e.g. "id string".
+	ReturnType string     // Optional return type.
+	Visibility visibility // The visibility marker.
+	Static     bool       // Renders the $ classifier.
+	Abstract   bool       // Renders the * classifier.
+}
+
+// String renders this diagram element to a method line.
+func (m *Method) String() (renderedElement string) {
+	renderedElement = string(m.Visibility) + m.Name + "(" +
+		strings.Join(m.Parameters, ", ") + ")"
+	if m.ReturnType != "" {
+		renderedElement += " " + m.ReturnType
+	}
+	switch {
+	case m.Abstract:
+		renderedElement += "*"
+	case m.Static:
+		renderedElement += "$"
+	}
+	return
+}
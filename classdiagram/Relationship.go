@@ -0,0 +1,64 @@
+package classdiagram
+
+import (
+	"fmt"
+)
+
+type relationType string
+
+// Relationship definitions as described at
+// https://mermaid.js.org/syntax/classDiagram.html#defining-relationship.
+// They are written from the perspective of Relationship's From, e.g.
+// "From inherits To" or "From is composed of To".
+const (
+	RelationInheritance relationType = `--|>`
+	RelationComposition relationType = `*--`
+	RelationAggregation relationType = `o--`
+	RelationAssociation relationType = `-->`
+	RelationDependency  relationType = `..>`
+	RelationRealization relationType = `..|>`
+	RelationLink        relationType = `--`
+	RelationDashedLink  relationType = `..`
+)
+
+// Cardinality definitions for Relationships as described at
+// https://mermaid.js.org/syntax/classDiagram.html#cardinality-multiplicity-on-relations.
+// Any other text like "2..5" can be used as cardinality, too.
+const (
+	CardinalityOne       = `1`
+	CardinalityZeroOrOne = `0..1`
+	CardinalityOneOrMore = `1..*`
+	CardinalityMany      = `*`
+	CardinalityN         = `n`
+	CardinalityZeroToN   = `0..n`
+	CardinalityOneToN    = `1..n`
+)
+
+// Relationship represents a connection between 2 Classes. Create an instance
+// of Relationship via ClassDiagram's AddRelationship method, do not create
+// instances directly.
+type Relationship struct {
+	From            *Class       // Pointer to the Class the relation starts.
+	To              *Class       // Pointer to the Class the relation ends.
+	Type            relationType // The kind of relationship.
+	Label           string       // Optional text along the relationship.
+	FromCardinality string       // Optional cardinality on From's side.
+	ToCardinality   string       // Optional cardinality on To's side.
+}
+
+// String renders this diagram element to a relationship line.
+func (r *Relationship) String() (renderedElement string) {
+	renderedElement = r.From.id + " "
+	if r.FromCardinality != "" {
+		renderedElement += fmt.Sprintf(`"%s" `, r.FromCardinality)
+	}
+	renderedElement += string(r.Type)
+	if r.ToCardinality != "" {
+		renderedElement += fmt.Sprintf(` "%s"`, r.ToCardinality)
+	}
+	renderedElement += " " + r.To.id
+	if r.Label != "" {
+		renderedElement += " : " + r.Label
+	}
+	return renderedElement + "\n"
+}
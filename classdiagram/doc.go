@@ -0,0 +1,37 @@
+/*
+Package classdiagram is an object oriented approach to define mermaid class
+diagrams as defined at https://mermaid.js.org/syntax/classDiagram.html and
+render them to mermaid code.
+
+You use the constructor NewClassDiagram to create a new ClassDiagram object.
+
+	cd, _ := classdiagram.NewClassDiagram()
+
+This object is used to add Classes with Members, Methods and annotations and
+Relationships between them.
+
+	animal, _ := cd.AddClass("Animal")
+	animal.AddMember("Name", "string")
+	animal.AddMethod("Speak", "string")
+	dog, _ := cd.AddClass("Dog")
+	r, _ := cd.AddRelationship(dog, animal, classdiagram.RelationInheritance)
+
+Once the diagram is completely defined, it can be "rendered" to mermaid code by
+stringifying the ClassDiagram object or streamed via WriteTo.
+
+	classDiagram
+	direction TB
+	class Animal {
+	  +string Name
+	  +Speak() string
+	}
+	class Dog
+	Dog --|> Animal
+
+Instead of defining it manually, a ClassDiagram can be generated from the
+source code of a Go package via FromGoPackage, showing struct fields, methods,
+embedding and which types satisfy which interfaces.
+
+	cd, err := classdiagram.FromGoPackage("./mypackage", nil)
+*/
+package classdiagram
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/flowchart"
+	"github.com/Heiko-san/mermaidgen/gantt"
+)
+
+////////// convert /////////////////////////////////////////////////////////////
+
+// Helperfunction for the convert command.
+func runConvert(c *cli, flags *flag.FlagSet, args []string) (err error) {
+	from := flags.String("from", "", "input format: json, yaml, csv, dot or mermaid")
+	to := flags.String("to", "mermaid", "output format: mermaid, json or dot")
+	output := flags.String("o", "", "write to this file instead of stdout")
+	if err = parseFlags(flags, args, 0, 1); err != nil {
+		return err
+	}
+	d, err := c.load(flags.Arg(0), *from)
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	switch *to {
+	case "mermaid":
+		err = d.Render(&b)
+	case "json":
+		var data []byte
+		if data, err = marshalIndent(d); err == nil {
+			b.Write(data)
+			b.WriteString("\n")
+		}
+	case "dot":
+		fc, ok := d.(*flowchart.Flowchart)
+		if !ok {
+			return fmt.Errorf("%s diagrams can't be converted to dot", d.Kind())
+		}
+		err = fc.DOT(&b)
+	default:
+		return usageError(fmt.Sprintf("unknown output format %q", *to))
+	}
+	if err != nil {
+		return err
+	}
+	return c.write(*output, b.Bytes())
+}
+
+////////// url /////////////////////////////////////////////////////////////////
+
+// Helperfunction for the url command.
+func runURL(c *cli, flags *flag.FlagSet, args []string) (err error) {
+	from := flags.String("from", "", "input format: json, yaml, csv, dot or mermaid")
+	target := flags.String("target", string(mermaidgen.TargetLiveView),
+		"live/view, live/edit, ink/img, ink/svg, ink/pdf, kroki/svg, "+
+			"kroki/png or kroki/pdf")
+	base := flags.String("base", "", "base URL of a self-hosted instance")
+	if err = parseFlags(flags, args, 0, 1); err != nil {
+		return err
+	}
+	d, err := c.load(flags.Arg(0), *from)
+	if err != nil {
+		return err
+	}
+	builder, _ := mermaidgen.NewURLBuilder(*target, *base)
+	url, err := builder.URL(d)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(c.stdout, url)
+	return
+}
+
+////////// decode //////////////////////////////////////////////////////////////
+
+// Helperfunction for the decode command.
+func runDecode(c *cli, flags *flag.FlagSet, args []string) (err error) {
+	output := flags.String("o", "", "write to this file instead of stdout")
+	if err = parseFlags(flags, args, 1, 1); err != nil {
+		return err
+	}
+	code, config, err := mermaidgen.DecodeLiveURL(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	// the live editor always stores a config, only non-default ones are kept
+	_, existing, _ := mermaidgen.SplitConfig(code)
+	if existing == nil && string(config.JSON()) !=
+		string((*mermaidgen.Config)(nil).JSON()) {
+		code = config.String() + code
+	}
+	if !strings.HasSuffix(code, "\n") {
+		code += "\n"
+	}
+	return c.write(*output, []byte(code))
+}
+
+////////// validate ////////////////////////////////////////////////////////////
+
+// Helperfunction for the validate command, it checks all files and reports
+// every invalid one.
+func runValidate(c *cli, flags *flag.FlagSet, args []string) (err error) {
+	if err = parseFlags(flags, args, 0, -1); err != nil {
+		return err
+	}
+	files := flags.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	invalid := 0
+	for _, file := range files {
+		data, err := c.read(file)
+		if err == nil {
+			_, err = parseMermaid(string(data))
+		}
+		if err != nil {
+			fmt.Fprintf(c.stderr, "%s: %s\n", displayName(file), err)
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d files invalid", invalid, len(files))
+	}
+	return nil
+}
+
+////////// fmt /////////////////////////////////////////////////////////////////
+
+// Helperfunction for the fmt command. Like gofmt, it prints the normalized
+// code unless -l or -w are given.
+func runFmt(c *cli, flags *flag.FlagSet, args []string) (err error) {
+	list := flags.Bool("l", false, "list files whose formatting differs")
+	write := flags.Bool("w", false, "write the result to the files")
+	indent := flags.Int("indent", 0, "indent nested lines by this many spaces")
+	tabs := flags.Bool("tabs", false, "indent nested lines by tabs")
+	grouped := flags.Bool("grouped", false,
+		"group flowchart lines by purpose (nodes, edges, styles, interactions)")
+	if err = parseFlags(flags, args, 0, -1); err != nil {
+		return err
+	}
+	if *indent < 0 {
+		return usageError("-indent must not be negative")
+	}
+	indentation := strings.Repeat(" ", *indent)
+	if *tabs {
+		indentation = "\t"
+	}
+	files := flags.Args()
+	if len(files) == 0 {
+		if *write {
+			return usageError("-w needs files")
+		}
+		files = []string{"-"}
+	}
+	for _, file := range files {
+		data, err := c.read(file)
+		if err != nil {
+			return err
+		}
+		d, err := parseMermaid(string(data))
+		if err != nil {
+			return fmt.Errorf("%s: %s", displayName(file), err)
+		}
+		switch v := d.(type) {
+		case *flowchart.Flowchart:
+			v.Indent, v.Grouped = indentation, *grouped
+		case *gantt.Gantt:
+			v.Indent = indentation
+		}
+		code, _ := mermaidgen.RenderString(d)
+		changed := code != string(data)
+		if *list && changed {
+			fmt.Fprintln(c.stdout, displayName(file))
+		}
+		if *write && changed {
+			if err = ioutil.WriteFile(file, []byte(code), 0666); err != nil {
+				return err
+			}
+		}
+		if !*list && !*write {
+			io.WriteString(c.stdout, code)
+		}
+	}
+	return nil
+}
+
+////////// render //////////////////////////////////////////////////////////////
+
+// Helperfunction for the render command.
+func runRender(c *cli, flags *flag.FlagSet, args []string) (err error) {
+	defaultRenderer := c.getenv("MERMAIDGEN_RENDERER")
+	if defaultRenderer == "" {
+		defaultRenderer = "mmdc"
+	}
+	defaultBinary := c.getenv("MERMAIDGEN_MMDC")
+	if defaultBinary == "" {
+		defaultBinary = "mmdc"
+	}
+	from := flags.String("from", "", "input format: json, yaml, csv, dot or mermaid")
+	name := flags.String("renderer", defaultRenderer,
+		"mmdc, browser, svg (flowcharts only) or text")
+	format := flags.String("format", string(mermaidgen.FormatSVG),
+		"image format of mmdc: svg, png or pdf")
+	binary := flags.String("mmdc", defaultBinary, "path of the mmdc binary")
+	width := flags.Int("width", 0, "width in pixels (mmdc) or characters (text)")
+	ascii := flags.Bool("ascii", false, "text: use ASCII characters only")
+	color := flags.Bool("color", false, "text: use ANSI colors")
+	output := flags.String("o", "", "write to this file instead of stdout")
+	if err = parseFlags(flags, args, 0, 1); err != nil {
+		return err
+	}
+	d, err := c.load(flags.Arg(0), *from)
+	if err != nil {
+		return err
+	}
+	var r mermaidgen.Renderer
+	switch *name {
+	case "mmdc":
+		mmdc, _ := mermaidgen.NewMMDCRenderer(*format, *binary)
+		mmdc.Width = *width
+		r = mmdc
+	case "browser":
+		r = &mermaidgen.BrowserRenderer{}
+	case "svg":
+		r = &flowchart.SVGRenderer{}
+	case "text":
+		if d.Kind() == mermaidgen.KindGantt {
+			r = &gantt.TextRenderer{Width: *width, ASCII: *ascii, Color: *color}
+		} else {
+			r = &flowchart.TextRenderer{Width: *width, ASCII: *ascii,
+				Color: *color}
+		}
+	default:
+		return usageError(fmt.Sprintf("unknown renderer %q", *name))
+	}
+	var b bytes.Buffer
+	if err = r.RenderDiagram(context.Background(), d, &b); err != nil {
+		return err
+	}
+	if *name == "browser" {
+		b.WriteString("\n")
+	}
+	return c.write(*output, b.Bytes())
+}
+
+////////// I/O /////////////////////////////////////////////////////////////////
+
+// Helperfunction to read a file, "-" or "" means stdin.
+func (c *cli) read(file string) (data []byte, err error) {
+	if file == "" || file == "-" {
+		return ioutil.ReadAll(c.stdin)
+	}
+	return ioutil.ReadFile(file)
+}
+
+// Helperfunction to write the output to a file, "" or "-" means stdout.
+func (c *cli) write(file string, data []byte) (err error) {
+	if file == "" || file == "-" {
+		_, err = c.stdout.Write(data)
+		return
+	}
+	return ioutil.WriteFile(file, data, 0666)
+}
+
+// Helperfunction to name files in messages.
+func displayName(file string) (name string) {
+	if file == "" || file == "-" {
+		return "<stdin>"
+	}
+	return file
+}
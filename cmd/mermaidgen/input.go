@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/flowchart"
+	"github.com/Heiko-san/mermaidgen/gantt"
+)
+
+// regular expressions to guess input formats
+var (
+	dotHeader    = regexp.MustCompile(`^(?i:strict\s+)?(?i:di)?(?i:graph)\s*("[^"]*"|[\w.]+)?\s*\{`)
+	yamlHeader   = regexp.MustCompile(`(?m)^(?:version|kind)\s*:`)
+	daysDuration = regexp.MustCompile(`^(\d+(?:\.\d+)?)d$`)
+)
+
+// Input formats by file extension.
+var extensions = map[string]string{".json": "json", ".yaml": "yaml",
+	".yml": "yaml", ".csv": "csv", ".dot": "dot", ".gv": "dot",
+	".mmd": "mermaid", ".mermaid": "mermaid"}
+
+// Helperfunction to read a diagram from a file in the given format, an empty
+// format is derived from the extension or guessed from the content.
+func (c *cli) load(file, format string) (d mermaidgen.Diagram, err error) {
+	data, err := c.read(file)
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = extensions[strings.ToLower(filepath.Ext(file))]
+	}
+	if format == "" {
+		format = guessFormat(data)
+	}
+	switch format {
+	case "json":
+		d, err = unmarshalDiagram(data)
+	case "yaml":
+		d, err = unmarshalYAMLDiagram(data)
+	case "csv":
+		d, err = csvPlan(data)
+	case "dot":
+		d, err = flowchart.FromDOT(bytes.NewReader(data))
+	case "mermaid":
+		d, err = parseMermaid(string(data))
+	default:
+		return nil, usageError(fmt.Sprintf("unknown input format %q", format))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", displayName(file), err)
+	}
+	return d, nil
+}
+
+// Helperfunction to guess the format of data without file extension.
+func guessFormat(data []byte) (format string) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return "json"
+	case dotHeader.Match(trimmed):
+		return "dot"
+	case yamlHeader.Match(trimmed):
+		return "yaml"
+	}
+	return "mermaid"
+}
+
+// A diagram with a JSON and YAML schema.
+type specDiagram interface {
+	mermaidgen.Diagram
+	json.Unmarshaler
+	UnmarshalYAML(unmarshal func(interface{}) error) (err error)
+}
+
+// Helperfunction to create an empty diagram of the given kind.
+func newSpecDiagram(kind mermaidgen.DiagramKind) (d specDiagram, err error) {
+	switch kind {
+	case mermaidgen.KindFlowchart:
+		return &flowchart.Flowchart{}, nil
+	case mermaidgen.KindGantt:
+		return &gantt.Gantt{}, nil
+	}
+	return nil, fmt.Errorf("unsupported kind %q", kind)
+}
+
+// Helperfunction to decode a JSON spec, the kind field selects the type.
+func unmarshalDiagram(data []byte) (d mermaidgen.Diagram, err error) {
+	var header struct {
+		Kind mermaidgen.DiagramKind `json:"kind"`
+	}
+	if err = json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+	spec, err := newSpecDiagram(header.Kind)
+	if err != nil {
+		return nil, err
+	}
+	if err = spec.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Helperfunction to decode a YAML spec, the kind field selects the type.
+// The tree is adjusted to the schema, see conformYAML.
+func unmarshalYAMLDiagram(data []byte) (d mermaidgen.Diagram, err error) {
+	tree, err := decodeYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	m, _ := tree.(map[string]interface{})
+	kind, _ := m["kind"].(string)
+	spec, err := newSpecDiagram(mermaidgen.DiagramKind(kind))
+	if err != nil {
+		return nil, err
+	}
+	err = spec.UnmarshalYAML(func(target interface{}) (err error) {
+		encoded, err := json.Marshal(conformYAML(tree, reflect.TypeOf(target)))
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(encoded, target)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// Helperfunction to encode a diagram as indented JSON spec.
+func marshalIndent(d mermaidgen.Diagram) (data []byte, err error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// Helperfunction to parse mermaid code with the parser of its diagram type.
+func parseMermaid(code string) (d mermaidgen.Diagram, err error) {
+	body, _, err := mermaidgen.SplitConfig(code)
+	if err != nil {
+		return nil, err
+	}
+	keyword := ""
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "%%") {
+			continue
+		}
+		keyword = strings.Fields(line)[0]
+		break
+	}
+	switch keyword {
+	case "graph", "flowchart":
+		return flowchart.Parse(code)
+	case "gantt":
+		return gantt.Parse(code)
+	case "":
+		return nil, fmt.Errorf("no diagram found")
+	}
+	return nil, fmt.Errorf("unsupported diagram type %q", keyword)
+}
+
+////////// CSV plans ///////////////////////////////////////////////////////////
+
+// Helperfunction to convert a CSV plan to a Gantt diagram, see the package
+// documentation for the columns.
+func csvPlan(data []byte) (g *gantt.Gantt, err error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.TrimLeadingSpace = true
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no header row found")
+	}
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "id", "title", "section", "start", "end", "duration", "after",
+			"critical", "active", "done":
+			columns[name] = i
+		default:
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+	}
+	g, _ = gantt.NewGantt()
+	after := make(map[*gantt.Task]string)
+	var order []*gantt.Task
+	for n, row := range rows[1:] {
+		line := n + 2
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		id := field("id")
+		if id == "" {
+			id = fmt.Sprintf("t%d", n+1)
+		}
+		var t *gantt.Task
+		if name := field("section"); name != "" {
+			s := g.GetSection(name)
+			if s == nil {
+				s, _ = g.AddSection(name)
+			}
+			t, err = s.AddTask(id, field("title"))
+		} else {
+			t, err = g.AddTask(id, field("title"))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: task %q: %s", line, id, err)
+		}
+		if start := field("start"); start != "" {
+			s, err := parseTime(start)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", line, err)
+			}
+			t.Start = &s
+		}
+		if duration := field("duration"); duration != "" {
+			d, err := parseDuration(duration)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", line, err)
+			}
+			t.Duration = &d
+		} else if end := field("end"); end != "" {
+			e, err := parseTime(end)
+			if err == nil {
+				err = t.SetDuration(e)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", line, err)
+			}
+		}
+		for name, flag := range map[string]*bool{"critical": &t.Critical,
+			"active": &t.Active, "done": &t.Done} {
+			if *flag, err = parseBool(field(name)); err != nil {
+				return nil, fmt.Errorf("line %d: %s: %s", line, name, err)
+			}
+		}
+		if a := field("after"); a != "" {
+			after[t] = a
+		}
+		order = append(order, t)
+	}
+	// after may reference tasks of later rows
+	for _, t := range order {
+		if id, ok := after[t]; ok {
+			if t.After = g.GetTask(id); t.After == nil {
+				return nil, fmt.Errorf("task %q starts after unknown task %q",
+					t.ID(), id)
+			}
+		}
+	}
+	return g, nil
+}
+
+// Helperfunction to parse RFC3339 times and dates.
+func parseTime(value string) (t time.Time, err error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05",
+		"2006-01-02 15:04", "2006-01-02"} {
+		if t, err = time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return t, fmt.Errorf("invalid time %q", value)
+}
+
+// Helperfunction to parse Go durations and days like 3d.
+func parseDuration(value string) (d time.Duration, err error) {
+	if m := daysDuration.FindStringSubmatch(value); m != nil {
+		days, _ := strconv.ParseFloat(m[1], 64)
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	if d, err = time.ParseDuration(value); err != nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
+
+// Helperfunction to parse flag columns, empty means false.
+func parseBool(value string) (b bool, err error) {
+	switch strings.ToLower(value) {
+	case "", "0", "false", "no", "n", "-":
+		return false, nil
+	case "1", "true", "yes", "y", "x":
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid flag %q", value)
+}
@@ -0,0 +1,176 @@
+/*
+Command mermaidgen makes the features of the mermaidgen packages available to
+shell scripts and non-Go tooling.
+
+Usage:
+
+	mermaidgen <command> [flags] [arguments]
+
+The commands are:
+
+	convert   convert JSON/YAML specs, CSV plans, DOT files or mermaid code
+	url       print the live editor, mermaid.ink or Kroki URL of a diagram
+	decode    decode a live editor URL to mermaid code
+	validate  check mermaid files with the parsers
+	fmt       normalize mermaid files
+	render    render a diagram via mmdc, the browser, as SVG or as text
+
+Inputs are read from the file given as argument or from stdin if it is omitted
+or "-". The input format is derived from the file extension (.json, .yaml,
+.yml, .csv, .dot, .gv, .mmd, .mermaid) or guessed from the content, it can be
+set explicitly via the -from flag of the commands.
+
+JSON and YAML specs use the schemas of flowchart.Flowchart and gantt.Gantt, see
+their MarshalJSON methods. YAML specs may use the block style subset written by
+YAML libraries (mappings, sequences, scalars and comments). Plain scalars like
+1 or true are read as strings where the schema expects one, so ids don't have to
+be quoted.
+
+CSV plans are converted to gantt diagrams. The first row names the columns id,
+title, section, start, end, duration, after, critical, active and done, all of
+them are optional. Times are RFC3339 or dates like 2019-06-20, durations are Go
+durations or days like 3d. Tasks without id get generated ones.
+
+The default renderer of the render command can be configured via the
+environment variable MERMAIDGEN_RENDERER (mmdc, browser, svg or text), the mmdc
+binary via MERMAIDGEN_MMDC.
+
+Run "mermaidgen <command> -h" for the flags of a command.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Exit codes of the command.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+// A subcommand with its usage line and description.
+type command struct {
+	usage string
+	short string
+	run   func(c *cli, flags *flag.FlagSet, args []string) (err error)
+}
+
+// Registry of all subcommands by name.
+var commands = map[string]command{
+	"convert": {"convert [-from format] [-to mermaid|json|dot] [-o file] [file]",
+		"convert JSON/YAML specs, CSV plans, DOT files or mermaid code",
+		runConvert},
+	"url": {"url [-from format] [-target target] [-base url] [file]",
+		"print the live editor, mermaid.ink or Kroki URL of a diagram",
+		runURL},
+	"decode": {"decode [-o file] url",
+		"decode a live editor URL to mermaid code", runDecode},
+	"validate": {"validate [file...]",
+		"check mermaid files with the parsers", runValidate},
+	"fmt": {"fmt [-l] [-w] [-indent n] [-tabs] [-grouped] [file...]",
+		"normalize mermaid files", runFmt},
+	"render": {"render [-from format] [-renderer name] [-format svg|png|pdf] " +
+		"[-width n] [-o file] [file]",
+		"render a diagram via mmdc, the browser, as SVG or as text",
+		runRender},
+}
+
+// The environment of a single run, replaced in tests.
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(key string) (value string)
+}
+
+func main() {
+	c := &cli{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr,
+		getenv: os.Getenv}
+	os.Exit(c.run(os.Args[1:]))
+}
+
+// Helperfunction to dispatch the command line to the subcommands, returns the
+// exit code.
+func (c *cli) run(args []string) (code int) {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" ||
+		args[0] == "--help" {
+		c.usage()
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(c.stderr, "mermaidgen: unknown command %q\n", args[0])
+		c.usage()
+		return exitUsage
+	}
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(c.stderr, "usage: mermaidgen %s\n\n%s\n", cmd.usage,
+			cmd.short)
+		flags.PrintDefaults()
+	}
+	if err := cmd.run(c, flags, args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		if _, isUsage := err.(usageError); isUsage {
+			if err.Error() != "" {
+				// the flag package already reported its errors
+				fmt.Fprintf(c.stderr, "mermaidgen %s: %s\n", args[0], err)
+			}
+			return exitUsage
+		}
+		fmt.Fprintf(c.stderr, "mermaidgen %s: %s\n", args[0], err)
+		return exitError
+	}
+	return exitOK
+}
+
+// Helperfunction to print the list of commands.
+func (c *cli) usage() {
+	fmt.Fprintf(c.stderr, "usage: mermaidgen <command> [flags] [arguments]\n\n"+
+		"The commands are:\n\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(c.stderr, "\t%-9s %s\n", name, commands[name].short)
+	}
+	fmt.Fprintf(c.stderr, "\nRun \"mermaidgen <command> -h\" for the flags "+
+		"of a command.\n")
+}
+
+// usageError marks errors caused by wrong arguments, they yield exitUsage.
+type usageError string
+
+// Error implements error.
+func (e usageError) Error() (message string) {
+	return string(e)
+}
+
+// Helperfunction to parse the flags and check the number of arguments.
+func parseFlags(flags *flag.FlagSet, args []string, min, max int) (err error) {
+	if err = flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return err
+		}
+		return usageError("")
+	}
+	if n := flags.NArg(); n < min || max >= 0 && n > max {
+		return usageError(fmt.Sprintf("unexpected number of arguments: %d",
+			n))
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/flowchart"
+)
+
+// Helperfunction to run the command line with the given stdin and environment.
+func runCLI(args []string, stdin string, env map[string]string) (code int,
+	stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	c := &cli{stdin: strings.NewReader(stdin), stdout: &out, stderr: &errOut,
+		getenv: func(key string) string { return env[key] }}
+	code = c.run(args)
+	return code, out.String(), errOut.String()
+}
+
+func TestConvert(t *testing.T) {
+	for _, test := range []struct {
+		args  []string
+		stdin string
+		want  string
+	}{
+		{[]string{"convert", "-from", "yaml"}, `
+# a comment
+version: 1
+kind: flowchart
+direction: LR
+items:
+- type: node
+  id: a
+  text: [first, second]
+- {type: node, id: b, shape: circle}
+edges:
+  - from: a
+    to: b
+    shape: dottedArrow
+`, "graph LR\na[\"first<br/>second\"]\nb((\"b\"))\na -.-> b\n"},
+		{[]string{"convert", "-from", "csv"}, `id,title,section,start,duration,after,critical
+plan,Planning,,2019-06-20,2d,,
+code,Coding,Work,,36h,plan,x
+,Testing,Work,,1d,code,
+`, "gantt\ndateFormat YYYY-MM-DDTHH:mm:ssZ\n" +
+			"Planning : plan, 2019-06-20T00:00:00Z, 172800s\n" +
+			"section Work\nCoding : crit, code, after plan, 129600s\n" +
+			"Testing : t3, after code, 86400s\n"},
+		{[]string{"convert"}, `digraph { rankdir=LR; a -> b }`,
+			"graph LR\na[\"a\"]\nb[\"b\"]\na --> b\n"},
+		{[]string{"convert", "-to", "dot"}, "graph TB\na-->b",
+			"digraph {\n\trankdir=TB\n\tnode [shape=box]\n\ta\n\tb\n\ta -> b\n}\n"},
+	} {
+		code, stdout, stderr := runCLI(test.args, test.stdin, nil)
+		if code != exitOK || stdout != test.want {
+			t.Errorf("%v: exit %d %s\ngot:\n%s\nwant:\n%s", test.args, code,
+				stderr, stdout, test.want)
+		}
+	}
+	// JSON specs round trip
+	_, spec, _ := runCLI([]string{"convert", "-to", "json"},
+		"graph RL\nsubgraph s\na((A))\nend\na==>b", nil)
+	_, code, _ := runCLI([]string{"convert"}, spec, nil)
+	if code != "graph RL\nsubgraph s [\"s\"]\na((\"A\"))\nend\nb[\"b\"]\n"+
+		"a ==> b\n" {
+		t.Errorf("unexpected code for JSON spec:\n%s\n%s", spec, code)
+	}
+	// errors
+	for _, args := range [][]string{
+		{"convert", "-from", "xml"},
+		{"convert", "-to", "png"},
+		{"convert", "a", "b"},
+		{"convert", "-x"},
+		{"unknown"},
+		{},
+	} {
+		if code, _, _ := runCLI(args, "graph TB", nil); code != exitUsage {
+			t.Errorf("%v: expected exit code %d, got %d", args, exitUsage, code)
+		}
+	}
+	if code, _, stderr := runCLI([]string{"convert", "-from", "csv"},
+		"id,owner\n", nil); code != exitError ||
+		!strings.Contains(stderr, `unknown column "owner"`) {
+		t.Errorf("expected an error for the unknown column, got %s", stderr)
+	}
+}
+
+func TestURLAndDecode(t *testing.T) {
+	fc, _ := flowchart.Parse("graph LR\na-->b\n")
+	code, url, _ := runCLI([]string{"url"}, "graph LR\na-->b\n", nil)
+	if code != exitOK || url != fc.LiveURL()+"\n" {
+		t.Errorf("unexpected URL %s", url)
+	}
+	code, decoded, stderr := runCLI([]string{"decode",
+		strings.TrimSpace(url)}, "", nil)
+	if code != exitOK || decoded != fc.String() {
+		t.Errorf("unexpected code (%s)\n%s", stderr, decoded)
+	}
+	dark, _ := mermaidgen.NewConfig(mermaidgen.ThemeDark)
+	_, decoded, _ = runCLI([]string{"decode", "https://mermaid.live/edit#" +
+		mermaidgen.PakoEncode("graph LR\na-->b", dark)}, "", nil)
+	if decoded != dark.String()+"graph LR\na-->b\n" {
+		t.Errorf("unexpected code with Config\n%s", decoded)
+	}
+	_, kroki, _ := runCLI([]string{"url", "-target", "kroki/svg"},
+		"graph LR\na-->b\n", nil)
+	if !strings.HasPrefix(kroki, "https://kroki.io/mermaid/svg/") {
+		t.Errorf("unexpected Kroki URL %s", kroki)
+	}
+	if code, _, _ := runCLI([]string{"decode", "https://example.com"}, "",
+		nil); code != exitError {
+		t.Errorf("expected an error for an URL without diagram")
+	}
+}
+
+func TestValidateAndFmt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mermaidgen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"ok.mmd":       "graph TB\n  a --> b\n",
+		"gantt.mmd":    "gantt\ntitle Plan\nTask : t1, 2019-06-20, 1d\n",
+		"broken.mmd":   "graph TB\n  a -->\n",
+		"sequence.mmd": "sequenceDiagram\n  a->>b: hi\n",
+	}
+	for name, content := range files {
+		ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0666)
+	}
+	path := func(name string) string { return filepath.Join(dir, name) }
+	code, _, stderr := runCLI([]string{"validate", path("ok.mmd"),
+		path("gantt.mmd")}, "", nil)
+	if code != exitOK {
+		t.Errorf("expected valid files: %s", stderr)
+	}
+	code, _, stderr = runCLI([]string{"validate", path("ok.mmd"),
+		path("broken.mmd"), path("sequence.mmd")}, "", nil)
+	if code != exitError || !strings.Contains(stderr, "broken.mmd: Parse: line 2") ||
+		!strings.Contains(stderr, `unsupported diagram type "sequenceDiagram"`) ||
+		!strings.Contains(stderr, "2 of 3 files invalid") {
+		t.Errorf("unexpected validation result %d:\n%s", code, stderr)
+	}
+	code, stdout, _ := runCLI([]string{"fmt", "-l", path("ok.mmd")}, "", nil)
+	if code != exitOK || stdout != path("ok.mmd")+"\n" {
+		t.Errorf("expected ok.mmd to be listed, got %q", stdout)
+	}
+	runCLI([]string{"fmt", "-w", path("ok.mmd")}, "", nil)
+	formatted, _ := ioutil.ReadFile(path("ok.mmd"))
+	if string(formatted) != "graph TB\na[\"a\"]\nb[\"b\"]\na --> b\n" {
+		t.Errorf("unexpected formatted file:\n%s", formatted)
+	}
+	if _, stdout, _ = runCLI([]string{"fmt", "-l", path("ok.mmd")}, "",
+		nil); stdout != "" {
+		t.Errorf("expected no changes, got %q", stdout)
+	}
+	_, stdout, _ = runCLI([]string{"fmt", "-tabs", "-grouped"},
+		"graph TB\nsubgraph s\n%% the edge\na-->b\nend\n", nil)
+	if stdout != "graph TB\n%% nodes\nsubgraph s [\"s\"]\n\ta[\"a\"]\n"+
+		"\tb[\"b\"]\nend\n%% edges\n%% the edge\na --> b\n" {
+		t.Errorf("unexpected pretty-printed code:\n%s", stdout)
+	}
+}
+
+func TestRender(t *testing.T) {
+	env := map[string]string{"MERMAIDGEN_RENDERER": "text"}
+	code, stdout, stderr := runCLI([]string{"render", "-ascii"},
+		"graph LR\na-->b\n", env)
+	if code != exitOK || stdout != "+---+      +---+\n| a |----->| b |\n+---+      +---+\n" {
+		t.Errorf("unexpected text rendering %d %s\n%s", code, stderr, stdout)
+	}
+	code, stdout, _ = runCLI([]string{"render", "-renderer", "svg"},
+		"graph LR\na-->b\n", env)
+	if code != exitOK || !strings.HasPrefix(stdout, "<svg") {
+		t.Errorf("expected SVG, got %.40s", stdout)
+	}
+	code, _, stderr = runCLI([]string{"render", "-renderer", "svg"},
+		"gantt\nTask : 1d\n", env)
+	if code != exitError || !strings.Contains(stderr, "not supported") {
+		t.Errorf("expected an error for SVG gantt diagrams: %s", stderr)
+	}
+	env["MERMAIDGEN_MMDC"] = filepath.Join(os.TempDir(), "no-such-mmdc")
+	code, _, stderr = runCLI([]string{"render", "-renderer", "mmdc"},
+		"graph LR\na-->b\n", env)
+	if code != exitError || !strings.Contains(stderr, "no-such-mmdc") {
+		t.Errorf("expected the configured mmdc to fail: %s", stderr)
+	}
+}
+
+func TestConvert_yamlNumbers(t *testing.T) {
+	for stdin, want := range map[string]string{`
+version: 1
+kind: flowchart
+direction: TB
+items:
+  - type: node
+    id: 1
+    text: [2.50, true]
+  - {type: node, id: 007}
+edges:
+  - from: 1
+    to: 007
+`: "graph TB\n1[\"2.50<br/>true\"]\n007[\"007\"]\n1 --> 007\n", `
+version: 1
+kind: gantt
+config:
+  gantt: {barHeight: 30}
+tasks:
+  - {id: 1, title: 2019, duration: 24h}
+  - {id: 2, after: 1, duration: 1h}
+`: "---\nconfig:\n  gantt:\n    barHeight: 30\n---\ngantt\n" +
+		"dateFormat YYYY-MM-DDTHH:mm:ssZ\n2019 : 86400s\n" +
+		"2 : 2, after 1, 3600s\n"} {
+		code, stdout, stderr := runCLI([]string{"convert", "-from", "yaml"},
+			stdin, nil)
+		if code != exitOK || stdout != want {
+			t.Errorf("exit %d %s\ngot:\n%s\nwant:\n%s", code, stderr, stdout,
+				want)
+		}
+	}
+}
+
+func TestDecodeYAML(t *testing.T) {
+	tree, err := decodeYAML([]byte(`
+a: 1
+b:
+  - x: "quoted # no comment"
+    y: 'it''s'
+  -
+    - nested
+c: {d: true, e: ~}
+f: [1.5, "two"]
+g:
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"a": yamlNumber{"1", int64(1)},
+		"b": []interface{}{
+			map[string]interface{}{"x": "quoted # no comment", "y": "it's"},
+			[]interface{}{"nested"}},
+		"c": map[string]interface{}{"d": true, "e": nil},
+		"f": []interface{}{yamlNumber{"1.5", 1.5}, "two"}, "g": nil}
+	if !reflect.DeepEqual(tree, want) {
+		got, _ := json.Marshal(tree)
+		t.Errorf("unexpected tree %s", got)
+	}
+	for _, doc := range []string{"", "a: |\n  text", "a: 1\na: 2",
+		"a:\n  b: 1\n c: 2", "a: &anchor 1"} {
+		if _, err := decodeYAML([]byte(doc)); err == nil {
+			t.Errorf("%q: expected an error", doc)
+		}
+	}
+}
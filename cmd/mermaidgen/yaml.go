@@ -0,0 +1,333 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// A YAML line without indentation and comment.
+type yamlLine struct {
+	number  int
+	indent  int
+	content string
+}
+
+// Helperfunction to decode the block style YAML subset written by YAML
+// libraries for the diagram schemas: nested mappings and sequences, plain and
+// quoted scalars, flow collections of scalars and comments. Anchors,
+// tags, multi-line scalars and multiple documents are not supported.
+func decodeYAML(data []byte) (tree interface{}, err error) {
+	var lines []yamlLine
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(stripYAMLComment(line), " \t\r")
+		content := strings.TrimLeft(line, " ")
+		if content == "" || content == "---" || content == "..." {
+			continue
+		}
+		if strings.HasPrefix(content, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for "+
+				"indentation", i+1)
+		}
+		lines = append(lines, yamlLine{i + 1, len(line) - len(content), content})
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("empty YAML document")
+	}
+	d := &yamlDecoder{lines: lines}
+	if tree, err = d.node(lines[0].indent); err != nil {
+		return nil, err
+	}
+	if d.pos < len(d.lines) {
+		return nil, fmt.Errorf("line %d: unexpected indentation",
+			d.lines[d.pos].number)
+	}
+	return tree, nil
+}
+
+// Helperfunction to remove comments, # starts a comment at the beginning of
+// the line or after whitespace outside of quotes.
+func stripYAMLComment(line string) (stripped string) {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' {
+				i++
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || strings.IndexByte(" :-[{,", line[i-1]) >= 0 {
+				quote = c
+			}
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// State of the YAML decoder.
+type yamlDecoder struct {
+	lines []yamlLine
+	pos   int
+}
+
+// Helperfunction to decode the node starting at the current line, which has
+// the given indentation.
+func (d *yamlDecoder) node(indent int) (value interface{}, err error) {
+	line := d.lines[d.pos]
+	switch {
+	case isYAMLSequenceItem(line.content):
+		return d.sequence(indent)
+	case yamlKey(line.content) >= 0:
+		return d.mapping(indent)
+	}
+	d.pos++
+	return yamlScalar(line.content, line.number)
+}
+
+// Helperfunction to check for "- item" lines.
+func isYAMLSequenceItem(content string) (ok bool) {
+	return content == "-" || strings.HasPrefix(content, "- ")
+}
+
+// Helperfunction to find the colon separating a mapping key from its value,
+// -1 if the line is no mapping entry.
+func yamlKey(content string) (colon int) {
+	var quote byte
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case i == 0 && (c == '"' || c == '\''):
+			quote = c
+		case i == 0 && (c == '[' || c == '{'):
+			return -1
+		case c == ':' && (i+1 == len(content) || content[i+1] == ' '):
+			return i
+		}
+	}
+	return -1
+}
+
+// Helperfunction to decode a sequence with the given indentation.
+func (d *yamlDecoder) sequence(indent int) (items []interface{}, err error) {
+	items = []interface{}{}
+	for d.pos < len(d.lines) && d.lines[d.pos].indent == indent &&
+		isYAMLSequenceItem(d.lines[d.pos].content) {
+		line := d.lines[d.pos]
+		rest := strings.TrimLeft(strings.TrimPrefix(line.content, "-"), " ")
+		var item interface{}
+		if rest == "" {
+			d.pos++
+			if d.pos < len(d.lines) && d.lines[d.pos].indent > indent {
+				if item, err = d.node(d.lines[d.pos].indent); err != nil {
+					return nil, err
+				}
+			}
+		} else {
+			// the item's content continues at the column after "- "
+			inner := indent + len(line.content) - len(rest)
+			d.lines[d.pos] = yamlLine{line.number, inner, rest}
+			if item, err = d.node(inner); err != nil {
+				return nil, err
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Helperfunction to decode a mapping with the given indentation.
+func (d *yamlDecoder) mapping(indent int) (m map[string]interface{}, err error) {
+	m = make(map[string]interface{})
+	for d.pos < len(d.lines) && d.lines[d.pos].indent == indent {
+		line := d.lines[d.pos]
+		colon := yamlKey(line.content)
+		if colon < 0 {
+			return nil, fmt.Errorf("line %d: expected key: value", line.number)
+		}
+		key, err := yamlScalar(strings.TrimSpace(line.content[:colon]),
+			line.number)
+		if err != nil {
+			return nil, err
+		}
+		name := fmt.Sprint(key)
+		if _, duplicate := m[name]; duplicate {
+			return nil, fmt.Errorf("line %d: duplicate key %q", line.number,
+				name)
+		}
+		rest := strings.TrimSpace(line.content[colon+1:])
+		d.pos++
+		var value interface{}
+		switch {
+		case rest != "":
+			if value, err = yamlScalar(rest, line.number); err != nil {
+				return nil, err
+			}
+		case d.pos < len(d.lines) && (d.lines[d.pos].indent > indent ||
+			d.lines[d.pos].indent == indent &&
+				isYAMLSequenceItem(d.lines[d.pos].content)):
+			// sequences may have the same indentation as their key
+			if value, err = d.node(d.lines[d.pos].indent); err != nil {
+				return nil, err
+			}
+		}
+		m[name] = value
+	}
+	return m, nil
+}
+
+// Helperfunction to decode a scalar or a flow collection of scalars.
+func yamlScalar(content string, number int) (value interface{}, err error) {
+	switch {
+	case content == "~" || content == "null":
+		return nil, nil
+	case content == "true" || content == "false":
+		return content == "true", nil
+	case content == "|" || content == ">" || strings.HasPrefix(content, "|") ||
+		strings.HasPrefix(content, ">") || strings.HasPrefix(content, "&") ||
+		strings.HasPrefix(content, "*") || strings.HasPrefix(content, "!"):
+		return nil, fmt.Errorf("line %d: unsupported YAML syntax %q", number,
+			content)
+	case strings.HasPrefix(content, `"`):
+		var s string
+		if err = json.Unmarshal([]byte(content), &s); err != nil {
+			return nil, fmt.Errorf("line %d: invalid string %s", number,
+				content)
+		}
+		return s, nil
+	case strings.HasPrefix(content, "'"):
+		if len(content) < 2 || !strings.HasSuffix(content, "'") {
+			return nil, fmt.Errorf("line %d: invalid string %s", number,
+				content)
+		}
+		return strings.Replace(content[1:len(content)-1], "''", "'", -1), nil
+	case strings.HasPrefix(content, "[") && strings.HasSuffix(content, "]"):
+		items := []interface{}{}
+		inner := strings.TrimSpace(content[1 : len(content)-1])
+		if inner == "" {
+			return items, nil
+		}
+		for _, item := range strings.Split(inner, ",") {
+			v, err := yamlScalar(strings.TrimSpace(item), number)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+		}
+		return items, nil
+	case strings.HasPrefix(content, "{") && strings.HasSuffix(content, "}"):
+		m := map[string]interface{}{}
+		inner := strings.TrimSpace(content[1 : len(content)-1])
+		if inner == "" {
+			return m, nil
+		}
+		for _, entry := range strings.Split(inner, ",") {
+			entry = strings.TrimSpace(entry)
+			colon := yamlKey(entry)
+			if colon < 0 {
+				return nil, fmt.Errorf("line %d: expected key: value in %s",
+					number, content)
+			}
+			key, err := yamlScalar(strings.TrimSpace(entry[:colon]), number)
+			if err != nil {
+				return nil, err
+			}
+			v, err := yamlScalar(strings.TrimSpace(entry[colon+1:]), number)
+			if err != nil {
+				return nil, err
+			}
+			m[fmt.Sprint(key)] = v
+		}
+		return m, nil
+	}
+	if i, err := strconv.ParseInt(content, 10, 64); err == nil {
+		return yamlNumber{content, i}, nil
+	}
+	if f, err := strconv.ParseFloat(content, 64); err == nil {
+		return yamlNumber{content, f}, nil
+	}
+	return content, nil
+}
+
+// A number-like plain scalar, which keeps its text for string fields.
+type yamlNumber struct {
+	text  string
+	value interface{} // int64 or float64
+}
+
+// MarshalJSON writes the number's value. Implements json.Marshaler.
+func (n yamlNumber) MarshalJSON() (data []byte, err error) {
+	return json.Marshal(n.value)
+}
+
+// Helperfunction to adjust a decoded YAML tree to the type it is unmarshaled
+// into: number-like and boolean plain scalars become strings where the type
+// expects a string, so ids like 1 don't have to be quoted. Fields are matched
+// by their JSON names.
+func conformYAML(tree interface{}, t reflect.Type) (conformed interface{}) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch v := tree.(type) {
+	case yamlNumber:
+		if t.Kind() == reflect.String {
+			return v.text
+		}
+	case bool:
+		if t.Kind() == reflect.String {
+			return strconv.FormatBool(v)
+		}
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return tree
+		}
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = conformYAML(item, t.Elem())
+		}
+		return items
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[key] = value
+			switch t.Kind() {
+			case reflect.Map:
+				m[key] = conformYAML(value, t.Elem())
+			case reflect.Struct:
+				if field, ok := jsonField(t, key); ok {
+					m[key] = conformYAML(value, field.Type)
+				}
+			}
+		}
+		return m
+	}
+	return tree
+}
+
+// Helperfunction to find the struct field encoding/json would decode the key
+// into.
+func jsonField(t reflect.Type, key string) (field reflect.StructField, ok bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field = t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if field.PkgPath != "" || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return field, false
+}
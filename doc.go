@@ -0,0 +1,8 @@
+/*
+Package mermaidgen is an object oriented approach to define mermaid graphics
+from Go code and render them to mermaid code.
+
+The diagram types are implemented in the subpackages, e.g. flowchart and gantt.
+This package holds the types shared by all of them, such as Color.
+*/
+package mermaidgen
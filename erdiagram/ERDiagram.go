@@ -0,0 +1,193 @@
+package erdiagram
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/internal/counting"
+)
+
+// IsValidID is used to check if Entity IDs are valid: IsValidID(string) bool.
+// Use the Entity's Label to display other names.
+var IsValidID = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`).MatchString
+
+////////// ERDiagram ///////////////////////////////////////////////////////////
+
+// ERDiagram objects are the entrypoints to this package, the whole diagram is
+// constructed around an ERDiagram object. Create an instance of ERDiagram via
+// ERDiagram's constructor NewERDiagram, do not create instances directly.
+type ERDiagram struct {
+	entitiesMap   map[string]*Entity // lookup table for existing Entities
+	entities      []*Entity          // Entities for ordered rendering
+	relationships []*Relationship    // Relationships for ordered rendering
+	Config        *mermaidgen.Config // Optional theme and configuration
+	Indent        string             // Indentation of the Attributes
+}
+
+// NewERDiagram is the constructor used to create a new ERDiagram object.
+// This object is the entrypoint for any further interactions with your diagram.
+// Always use the constructor, don't create ERDiagram objects directly.
+func NewERDiagram() (newERDiagram *ERDiagram) {
+	ed := &ERDiagram{Indent: "  "}
+	ed.entitiesMap = make(map[string]*Entity)
+	return ed
+}
+
+// String recursively renders the whole diagram to mermaid code lines.
+// It is a shorthand for WriteTo with a strings.Builder.
+func (ed *ERDiagram) String() (renderedElement string) {
+	var s strings.Builder
+	ed.WriteTo(&s)
+	return s.String()
+}
+
+// WriteTo renders the Entities and Relationships to mermaid code lines and
+// writes them to w. The Attributes of Entities are indented by Indent.
+// Implements io.WriterTo.
+func (ed *ERDiagram) WriteTo(w io.Writer) (n int64, err error) {
+	cw := &counting.Writer{W: w}
+	b := bufio.NewWriter(cw)
+	b.WriteString(ed.Config.String())
+	b.WriteString("erDiagram\n")
+	for _, e := range ed.entities {
+		e.writeEntity(b, ed.Indent)
+	}
+	for _, r := range ed.relationships {
+		b.WriteString(r.String())
+	}
+	err = b.Flush()
+	return cw.N, err
+}
+
+// Render writes the mermaid code of the whole diagram to w.
+// Implements mermaidgen.Diagram.
+func (ed *ERDiagram) Render(w io.Writer) (err error) {
+	_, err = ed.WriteTo(w)
+	return
+}
+
+// Kind returns mermaidgen.KindERDiagram. Implements mermaidgen.Diagram.
+func (ed *ERDiagram) Kind() (kind mermaidgen.DiagramKind) {
+	return mermaidgen.KindERDiagram
+}
+
+// GetConfig returns the ERDiagram's Config, which may be nil.
+// Implements mermaidgen.Diagram.
+func (ed *ERDiagram) GetConfig() (config *mermaidgen.Config) {
+	return ed.Config
+}
+
+// SetConfig replaces the ERDiagram's Config, nil removes it.
+// Implements mermaidgen.Diagram.
+func (ed *ERDiagram) SetConfig(config *mermaidgen.Config) {
+	ed.Config = config
+}
+
+// LiveURL renders the ERDiagram and generates a view URL for
+// https://mermaid.live from it, see mermaidgen.LiveURL for details.
+func (ed *ERDiagram) LiveURL() (url string) {
+	url, _ = mermaidgen.LiveURL(ed)
+	return
+}
+
+// ViewInBrowser uses the URL generated by ERDiagram's LiveURL method and
+// opens that URL in the OS's default browser via mermaidgen.ViewInBrowser. It
+// eventually returns any error occured.
+func (ed *ERDiagram) ViewInBrowser() (err error) {
+	return mermaidgen.ViewInBrowser(ed)
+}
+
+////////// add Items ///////////////////////////////////////////////////////////
+
+// AddEntity is used to add a new Entity to the ERDiagram. If the provided ID
+// already exists or is invalid, no new Entity is created and an error is
+// returned. The ID can later be used to look up the created Entity using
+// ERDiagram's GetEntity method.
+func (ed *ERDiagram) AddEntity(id string) (newEntity *Entity, err error) {
+	if !IsValidID(id) {
+		return nil, fmt.Errorf("invalid id")
+	}
+	if _, alreadyExists := ed.entitiesMap[id]; alreadyExists {
+		return nil, fmt.Errorf("id already exists")
+	}
+	newEntity = &Entity{id: id, diagram: ed}
+	ed.entitiesMap[id] = newEntity
+	ed.entities = append(ed.entities, newEntity)
+	return
+}
+
+// AddRelationship is used to add a new Relationship between two Entities of
+// this ERDiagram. New Relationships are non-identifying one-to-many
+// relationships, from being the "one" side. Optional initializer parameters
+// can be given in the order Label, FromCardinality, ToCardinality,
+// Identifying. An error is returned if an Entity doesn't belong to the
+// ERDiagram.
+func (ed *ERDiagram) AddRelationship(from, to *Entity, init ...interface{}) (
+	newRelationship *Relationship, err error) {
+	if from == nil || to == nil || ed.entitiesMap[from.id] != from ||
+		ed.entitiesMap[to.id] != to {
+		return nil, fmt.Errorf("Entity doesn't belong to the ERDiagram")
+	}
+	r := &Relationship{From: from, To: to,
+		FromCardinality: CardinalityExactlyOne,
+		ToCardinality:   CardinalityZeroOrMore}
+	switch l, ok := len(init), false; {
+	case l > 3:
+		r.Identifying, ok = init[3].(bool)
+		if !ok {
+			return nil, fmt.Errorf("value for Identifying was no bool")
+		}
+		fallthrough
+	case l > 2:
+		r.ToCardinality, ok = init[2].(cardinality)
+		if !ok {
+			return nil, fmt.Errorf("value for ToCardinality was no cardinality")
+		}
+		fallthrough
+	case l > 1:
+		r.FromCardinality, ok = init[1].(cardinality)
+		if !ok {
+			return nil, fmt.Errorf("value for FromCardinality was no cardinality")
+		}
+		fallthrough
+	case l > 0:
+		r.Label, ok = init[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("value for Label was no string")
+		}
+	}
+	ed.relationships = append(ed.relationships, r)
+	return r, nil
+}
+
+////////// get Items ///////////////////////////////////////////////////////////
+
+// GetEntity looks up a previously defined Entity by its ID.
+// If this ID doesn't exist, nil is returned.
+// Use ERDiagram's AddEntity to create new Entities.
+func (ed *ERDiagram) GetEntity(id string) (existingEntity *Entity) {
+	// if not found -> nil
+	return ed.entitiesMap[id]
+}
+
+////////// list Items //////////////////////////////////////////////////////////
+
+// ListEntities returns a slice of all Entities previously added to this
+// ERDiagram in the order they were defined.
+func (ed *ERDiagram) ListEntities() (allEntities []*Entity) {
+	allEntities = make([]*Entity, len(ed.entities))
+	copy(allEntities, ed.entities)
+	return
+}
+
+// ListRelationships returns a slice of all Relationships previously added to
+// this ERDiagram in the order they were defined.
+func (ed *ERDiagram) ListRelationships() (allRelationships []*Relationship) {
+	allRelationships = make([]*Relationship, len(ed.relationships))
+	copy(allRelationships, ed.relationships)
+	return
+}
@@ -0,0 +1,89 @@
+package erdiagram_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen/erdiagram"
+)
+
+// Defining and rendering an entity relationship diagram
+func ExampleERDiagram() {
+	ed := erdiagram.NewERDiagram()
+	customer, _ := ed.AddEntity("CUSTOMER")
+	customer.AddAttribute("int", "id", erdiagram.KeyPrimary)
+	customer.AddAttribute("string", "email", erdiagram.KeyUnique).Comment =
+		"login"
+	order, _ := ed.AddEntity("ORDER")
+	order.Label = "Purchase order"
+	order.AddAttribute("int", "id", erdiagram.KeyPrimary)
+	order.AddAttribute("int", "customer_id", erdiagram.KeyForeign)
+	item, _ := ed.AddEntity("LINE_ITEM")
+	// non-identifying one-to-many is the default
+	ed.AddRelationship(customer, order, "places")
+	// an identifying relationship with explicit cardinalities
+	ed.AddRelationship(order, item, "contains",
+		erdiagram.CardinalityExactlyOne, erdiagram.CardinalityOneOrMore, true)
+	fmt.Print(ed)
+	//Output:
+	//erDiagram
+	//CUSTOMER {
+	//   int id PK
+	//   string email UK "login"
+	//}
+	//ORDER["Purchase order"] {
+	//   int id PK
+	//   int customer_id FK
+	//}
+	//LINE_ITEM
+	//CUSTOMER ||..o{ ORDER : places
+	//ORDER ||--|{ LINE_ITEM : contains
+}
+
+func TestERDiagram_errors(t *testing.T) {
+	ed := erdiagram.NewERDiagram()
+	a, _ := ed.AddEntity("A")
+	if _, err := ed.AddEntity("A"); err == nil {
+		t.Error("duplicate id not detected")
+	}
+	if _, err := ed.AddEntity("A B"); err == nil {
+		t.Error("invalid id not detected")
+	}
+	other := erdiagram.NewERDiagram()
+	b, _ := other.AddEntity("B")
+	if _, err := ed.AddRelationship(a, b); err == nil {
+		t.Error("foreign Entity not detected")
+	}
+	one := erdiagram.CardinalityExactlyOne
+	for _, init := range [][]interface{}{
+		{1}, {"", 1}, {"", one, 1}, {"", one, one, 1},
+	} {
+		if _, err := ed.AddRelationship(a, a, init...); err == nil {
+			t.Errorf("invalid initializer %v not detected", init)
+		}
+	}
+	r, _ := ed.AddRelationship(a, a, "has parent",
+		erdiagram.CardinalityZeroOrMore, erdiagram.CardinalityZeroOrOne)
+	if r.String() != "A }o..o| A : \"has parent\"\n" {
+		t.Errorf("unexpected %q", r.String())
+	}
+}
+
+func TestERDiagram_WriteTo(t *testing.T) {
+	ed := erdiagram.NewERDiagram()
+	ed.Indent = "\t"
+	a, _ := ed.AddEntity("A")
+	a.AddAttribute("int", "x").AddKey(erdiagram.KeyPrimary)
+	a.GetAttribute("x").AddKey(erdiagram.KeyPrimary)
+	var b strings.Builder
+	n, err := ed.WriteTo(&b)
+	expected := "erDiagram\nA {\n\tint x PK\n}\n"
+	if err != nil || b.String() != expected || n != int64(len(expected)) {
+		t.Errorf("unexpected %d %v %q", n, err, b.String())
+	}
+	if a.GetAttribute("y") != nil || len(ed.ListEntities()) != 1 ||
+		len(ed.ListRelationships()) != 0 {
+		t.Error("unexpected lookup result")
+	}
+}
@@ -0,0 +1,130 @@
+package erdiagram
+
+import (
+	"bufio"
+	"strings"
+)
+
+type keyType string
+
+// Key definitions for Attributes as described at
+// https://mermaid.js.org/syntax/entityRelationshipDiagram.html#attribute-keys-and-comments.
+const (
+	KeyPrimary keyType = `PK`
+	KeyForeign keyType = `FK`
+	KeyUnique  keyType = `UK`
+)
+
+// Entity represents a single, unique entity (e.g. a table) of the ERDiagram.
+// Create an instance of Entity via ERDiagram's AddEntity method, do not create
+// instances directly. Already defined IDs can be looked up via ERDiagram's
+// GetEntity method or iterated over via its ListEntities method.
+type Entity struct {
+	id         string
+	diagram    *ERDiagram   // top lvl pointer
+	Label      string       // Optional alias displayed instead of ID
+	Attributes []*Attribute // The Attributes in the order of definition
+}
+
+// ID provides access to the Entity's readonly field id.
+func (e *Entity) ID() (id string) {
+	return e.id
+}
+
+// ERDiagram provides access to the top level ERDiagram to be able to access
+// Adder, Getter and Lister methods.
+func (e *Entity) ERDiagram() (topLevel *ERDiagram) {
+	return e.diagram
+}
+
+// AddAttribute is used to add a new Attribute with the given type and name to
+// the Entity. Optional keys can be given, e.g. KeyPrimary.
+func (e *Entity) AddAttribute(attributeType, name string,
+	keys ...keyType) (newAttribute *Attribute) {
+	newAttribute = &Attribute{Type: attributeType, Name: name, Keys: keys}
+	e.Attributes = append(e.Attributes, newAttribute)
+	return
+}
+
+// GetAttribute looks up a previously defined Attribute by its name.
+// If this name doesn't exist, nil is returned.
+func (e *Entity) GetAttribute(name string) (existingAttribute *Attribute) {
+	for _, a := range e.Attributes {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// String renders this diagram element to an entity definition, followed by a
+// block with the Attributes if there are any.
+func (e *Entity) String() (renderedElement string) {
+	var s strings.Builder
+	b := bufio.NewWriter(&s)
+	e.writeEntity(b, e.diagram.Indent)
+	b.Flush()
+	return s.String()
+}
+
+// Helperfunction for String and ERDiagram's WriteTo, renders the Entity with
+// its Attributes indented by indent.
+func (e *Entity) writeEntity(w *bufio.Writer, indent string) {
+	w.WriteString(e.id)
+	if e.Label != "" {
+		w.WriteString(`["` + e.Label + `"]`)
+	}
+	if len(e.Attributes) == 0 {
+		w.WriteString("\n")
+		return
+	}
+	w.WriteString(" {\n")
+	for _, a := range e.Attributes {
+		w.WriteString(indent + a.String() + "\n")
+	}
+	w.WriteString("}\n")
+}
+
+////////// Attribute ///////////////////////////////////////////////////////////
+
+// Attribute represents a typed attribute (e.g. a column) of an Entity. Create
+// an instance of Attribute via Entity's AddAttribute method.
+type Attribute struct {
+	Type    string    // The type, e.g. "varchar(255)"
+	Name    string    // The name of the Attribute
+	Keys    []keyType // Optional keys, e.g. KeyPrimary
+	Comment string    // Optional comment displayed with the Attribute
+}
+
+// HasKey checks whether the Attribute has the given key.
+func (a *Attribute) HasKey(key keyType) (hasKey bool) {
+	for _, k := range a.Keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// AddKey adds the key to the Attribute unless it already has it.
+func (a *Attribute) AddKey(key keyType) {
+	if !a.HasKey(key) {
+		a.Keys = append(a.Keys, key)
+	}
+}
+
+// String renders this diagram element to an attribute line.
+func (a *Attribute) String() (renderedElement string) {
+	renderedElement = a.Type + " " + a.Name
+	if len(a.Keys) > 0 {
+		keys := make([]string, len(a.Keys))
+		for i, k := range a.Keys {
+			keys[i] = string(k)
+		}
+		renderedElement += " " + strings.Join(keys, ", ")
+	}
+	if a.Comment != "" {
+		renderedElement += ` "` + strings.Replace(a.Comment, `"`, `'`, -1) + `"`
+	}
+	return
+}
@@ -0,0 +1,57 @@
+package erdiagram
+
+import (
+	"regexp"
+	"strings"
+)
+
+type cardinality string
+
+// Cardinality definitions (crow's foot notation) for Relationships as
+// described at
+// https://mermaid.js.org/syntax/entityRelationshipDiagram.html#relationship-syntax.
+// The values are the markers of the left side, they are mirrored for the
+// right side automatically.
+const (
+	CardinalityZeroOrOne  cardinality = `|o`
+	CardinalityExactlyOne cardinality = `||`
+	CardinalityZeroOrMore cardinality = `}o`
+	CardinalityOneOrMore  cardinality = `}|`
+)
+
+// Labels that can be rendered without quotes.
+var isSimpleLabel = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`).MatchString
+
+// Relationship represents a connection between 2 Entities. Create an instance
+// of Relationship via ERDiagram's AddRelationship method, do not create
+// instances directly.
+type Relationship struct {
+	From            *Entity     // Pointer to the Entity on the left side
+	To              *Entity     // Pointer to the Entity on the right side
+	FromCardinality cardinality // How many From's relate to one To
+	ToCardinality   cardinality // How many To's relate to one From
+	Identifying     bool        // Solid line if true, dashed otherwise
+	Label           string      // Text describing the Relationship
+}
+
+// String renders this diagram element to a relationship line.
+func (r *Relationship) String() (renderedElement string) {
+	line := ".."
+	if r.Identifying {
+		line = "--"
+	}
+	label := r.Label
+	if !isSimpleLabel(label) {
+		label = `"` + strings.Replace(label, `"`, `'`, -1) + `"`
+	}
+	return r.From.id + " " + string(r.FromCardinality) + line +
+		mirror(r.ToCardinality) + " " + r.To.id + " : " + label + "\n"
+}
+
+// Helperfunction to mirror a left side cardinality marker to the right side.
+func mirror(c cardinality) string {
+	if len(c) != 2 {
+		return string(c)
+	}
+	return strings.NewReplacer("}", "{").Replace(string(c[1]) + string(c[0]))
+}
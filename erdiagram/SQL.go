@@ -0,0 +1,1010 @@
+package erdiagram
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Matches all characters not allowed in Entity IDs and Attribute names.
+var sqlInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// Matches the tags of dollar quoted strings like $body$.
+var sqlDollarTag = regexp.MustCompile(`^[a-zA-Z_]*$`).MatchString
+
+// Keywords that end the data type of a column definition.
+var sqlColumnKeywords = map[string]bool{"NOT": true, "NULL": true,
+	"DEFAULT": true, "PRIMARY": true, "UNIQUE": true, "REFERENCES": true,
+	"CHECK": true, "CONSTRAINT": true, "COMMENT": true, "COLLATE": true,
+	"AUTO_INCREMENT": true, "AUTOINCREMENT": true, "GENERATED": true,
+	"IDENTITY": true, "CHARSET": true, "ON": true, "AS": true}
+
+////////// SQL import //////////////////////////////////////////////////////////
+
+// A token of SQL source code.
+type sqlToken struct {
+	kind byte // 'w' for words, 'i' quoted identifiers, 's' strings, 'p' others
+	text string
+	line int
+}
+
+// A table as defined by the SQL statements read so far.
+type sqlTable struct {
+	name        string
+	columns     []*sqlColumn
+	foreignKeys []*sqlForeignKey
+}
+
+// A column of a sqlTable.
+type sqlColumn struct {
+	name, dataType, comment  string
+	notNull, primary, unique bool
+}
+
+// A foreign key constraint of a sqlTable.
+type sqlForeignKey struct {
+	name    string // constraint name, may be empty
+	columns []string
+	table   string
+}
+
+// Internal state of the SQL parser.
+type sqlParser struct {
+	prefix string // prefix of error messages
+	tokens []sqlToken
+	pos    int
+	tables map[string]*sqlTable // tables by lower case name
+	order  []*sqlTable          // tables in creation order
+}
+
+// FromSQL creates an ERDiagram from SQL DDL statements. It understands the
+// following subset of SQL, all other statements are ignored:
+//
+//	CREATE TABLE name (column type constraints..., table constraints...)
+//	ALTER TABLE name ADD [COLUMN] column type constraints...
+//	ALTER TABLE name ADD [CONSTRAINT name] FOREIGN KEY (columns) REFERENCES ...
+//	ALTER TABLE name DROP [COLUMN] column
+//	ALTER TABLE name DROP {CONSTRAINT name | FOREIGN KEY name | PRIMARY KEY}
+//	ALTER TABLE name RENAME [COLUMN] column TO new_name
+//	ALTER TABLE name RENAME TO new_name
+//	RENAME TABLE name TO new_name, ...
+//	DROP TABLE name, ...
+//	COMMENT ON COLUMN table.column IS 'comment'
+//
+// The statements are applied in order, so a sequence of migrations results in
+// the current schema. Dropping a table or column drops the foreign keys
+// involving it too.
+//
+// Each table becomes an Entity, each column an Attribute with the data type as
+// Type. PRIMARY KEY, FOREIGN KEY/REFERENCES and UNIQUE constraints become the
+// keys KeyPrimary, KeyForeign and KeyUnique, COMMENT clauses become comments.
+// Schema prefixes are dropped, table names that aren't valid IDs are sanitized
+// and used as Label, column names are sanitized.
+//
+// Each foreign key becomes a Relationship from the referenced Entity to the
+// referencing one, labeled with the foreign key columns. It is identifying if
+// the columns are part of the primary key. FromCardinality is
+// CardinalityExactlyOne if the columns are NOT NULL, CardinalityZeroOrOne
+// otherwise. ToCardinality is CardinalityZeroOrOne if the columns are unique,
+// CardinalityZeroOrMore otherwise. Referenced tables that aren't defined
+// become Entities without Attributes.
+func FromSQL(r io.Reader) (newERDiagram *ERDiagram, err error) {
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("FromSQL: %s", err)
+	}
+	p := &sqlParser{tables: make(map[string]*sqlTable)}
+	if err = p.parse(string(src), "FromSQL: "); err != nil {
+		return nil, err
+	}
+	return p.diagram(), nil
+}
+
+// FromSQLFiles creates an ERDiagram from the SQL DDL statements of the given
+// files in the given order, see FromSQL for details. A directory, e.g. a
+// migration folder, stands for all its *.sql files sorted by name, down
+// migrations (*.down.sql) are skipped.
+func FromSQLFiles(paths ...string) (newERDiagram *ERDiagram, err error) {
+	p := &sqlParser{tables: make(map[string]*sqlTable)}
+	for _, path := range paths {
+		files := []string{path}
+		if info, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("FromSQLFiles: %s", err)
+		} else if info.IsDir() {
+			files, _ = filepath.Glob(filepath.Join(path, "*.sql"))
+			sort.Strings(files)
+		}
+		for _, file := range files {
+			if strings.HasSuffix(file, ".down.sql") {
+				continue
+			}
+			src, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("FromSQLFiles: %s", err)
+			}
+			err = p.parse(string(src), "FromSQLFiles: "+file+": ")
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p.diagram(), nil
+}
+
+// Helperfunction to parse the statements of one source.
+func (p *sqlParser) parse(src, prefix string) (err error) {
+	p.prefix = prefix
+	if p.tokens, err = p.tokenize(src); err != nil {
+		return err
+	}
+	for p.pos = 0; p.pos < len(p.tokens); {
+		if err = p.statement(); err != nil {
+			return err
+		}
+		// skip the rest of the statement
+		for p.pos < len(p.tokens) && !p.is(";") {
+			p.pos++
+		}
+		p.pos++
+	}
+	return nil
+}
+
+// Helperfunction to create an error message for the current token.
+func (p *sqlParser) errorf(format string, args ...interface{}) error {
+	line := 0
+	if p.pos < len(p.tokens) {
+		line = p.tokens[p.pos].line
+	} else if len(p.tokens) > 0 {
+		line = p.tokens[len(p.tokens)-1].line
+	}
+	return fmt.Errorf("%sline %d: %s", p.prefix, line,
+		fmt.Sprintf(format, args...))
+}
+
+// Helperfunction to split SQL source code into tokens.
+func (p *sqlParser) tokenize(src string) (tokens []sqlToken, err error) {
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		start := i
+		switch {
+		case c == '\n':
+			line++
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+			continue
+		case strings.HasPrefix(src[i:], "--") || c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("%sline %d: comment not closed",
+					p.prefix, line)
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+			continue
+		case c == '\'' || c == '"' || c == '`':
+			var text strings.Builder
+			for i++; ; i++ {
+				if i >= len(src) {
+					return nil, fmt.Errorf("%sline %d: %c not closed",
+						p.prefix, line, c)
+				}
+				if src[i] == c {
+					// doubled quotes are escaped quotes
+					if i+1 < len(src) && src[i+1] == c {
+						i++
+					} else {
+						break
+					}
+				}
+				text.WriteByte(src[i])
+			}
+			i++
+			kind := byte('i')
+			if c == '\'' {
+				kind = 's'
+			}
+			tokens = append(tokens, sqlToken{kind, text.String(), line})
+			line += strings.Count(src[start:i], "\n")
+			continue
+		case c == '$':
+			// dollar quoted strings like $body$ ... $body$
+			end := strings.IndexByte(src[i+1:], '$')
+			if end >= 0 && sqlDollarTag(src[i+1:i+1+end]) {
+				tag := src[i : i+end+2]
+				end = strings.Index(src[i+len(tag):], tag)
+				if end < 0 {
+					return nil, fmt.Errorf("%sline %d: %s not closed",
+						p.prefix, line, tag)
+				}
+				i += len(tag) + end + len(tag)
+				tokens = append(tokens, sqlToken{'s',
+					src[start+len(tag) : i-len(tag)], line})
+				line += strings.Count(src[start:i], "\n")
+				continue
+			}
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' ||
+			c >= '0' && c <= '9' || c >= 0x80:
+			for i < len(src) && (src[i] == '_' || src[i] >= 'a' &&
+				src[i] <= 'z' || src[i] >= 'A' && src[i] <= 'Z' ||
+				src[i] >= '0' && src[i] <= '9' || src[i] >= 0x80 ||
+				src[i] == '$') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{'w', src[start:i], line})
+			continue
+		}
+		tokens = append(tokens, sqlToken{'p', string(c), line})
+		i++
+	}
+	return tokens, nil
+}
+
+////////// parser helpers //////////////////////////////////////////////////////
+
+// Helperfunction to check whether the current token is the given keyword or
+// punctuation character.
+func (p *sqlParser) is(text string) bool {
+	if p.pos >= len(p.tokens) {
+		return false
+	}
+	t := p.tokens[p.pos]
+	return (t.kind == 'w' || t.kind == 'p') && strings.EqualFold(t.text, text)
+}
+
+// Helperfunction to consume the given sequence of keywords if present.
+func (p *sqlParser) accept(words ...string) bool {
+	for i, w := range words {
+		if p.pos+i >= len(p.tokens) {
+			return false
+		}
+		t := p.tokens[p.pos+i]
+		if (t.kind != 'w' && t.kind != 'p') || !strings.EqualFold(t.text, w) {
+			return false
+		}
+	}
+	p.pos += len(words)
+	return true
+}
+
+// Helperfunction to consume the given keyword or return an error.
+func (p *sqlParser) expect(text string) error {
+	if !p.accept(text) {
+		return p.errorf("%s expected", text)
+	}
+	return nil
+}
+
+// Helperfunction to check whether the current token ends a definition.
+func (p *sqlParser) atEnd() bool {
+	return p.pos >= len(p.tokens) || p.is(",") || p.is(")") || p.is(";")
+}
+
+// Helperfunction to consume a name, which may be qualified by a schema.
+// Only the last part is returned.
+func (p *sqlParser) name() (name string, err error) {
+	names, err := p.qualifiedName()
+	if err != nil {
+		return "", err
+	}
+	return names[len(names)-1], nil
+}
+
+// Helperfunction to consume a name and return all its dot separated parts.
+func (p *sqlParser) qualifiedName() (names []string, err error) {
+	for {
+		if p.pos >= len(p.tokens) || (p.tokens[p.pos].kind != 'w' &&
+			p.tokens[p.pos].kind != 'i') {
+			return nil, p.errorf("name expected")
+		}
+		names = append(names, p.tokens[p.pos].text)
+		p.pos++
+		if !p.accept(".") {
+			return
+		}
+	}
+}
+
+// Helperfunction to consume a parenthesized list of names.
+func (p *sqlParser) nameList() (names []string, err error) {
+	if err = p.expect("("); err != nil {
+		return nil, err
+	}
+	for {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		// MySQL prefix lengths and sort orders
+		for !p.atEnd() {
+			p.skip()
+		}
+		names = append(names, name)
+		if p.accept(")") {
+			return names, nil
+		}
+		if err = p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Helperfunction to skip a token, parenthesized groups are skipped as a whole.
+func (p *sqlParser) skip() {
+	depth := 0
+	for p.pos < len(p.tokens) {
+		switch {
+		case p.is("("):
+			depth++
+		case p.is(")"):
+			depth--
+		}
+		p.pos++
+		if depth <= 0 {
+			return
+		}
+	}
+}
+
+// Helperfunction to get a table defined before.
+func (p *sqlParser) table(name string) (table *sqlTable, err error) {
+	table = p.tables[strings.ToLower(name)]
+	if table == nil {
+		return nil, p.errorf("unknown table %s", name)
+	}
+	return table, nil
+}
+
+// Helperfunction to add a foreign key to a table. Keys with the same columns
+// and referenced table as an existing one, e.g. an inline REFERENCES clause
+// repeated as FOREIGN KEY constraint, are merged into it.
+func (t *sqlTable) addForeignKey(fk *sqlForeignKey) {
+	for _, existing := range t.foreignKeys {
+		if !strings.EqualFold(existing.table, fk.table) ||
+			len(existing.columns) != len(fk.columns) {
+			continue
+		}
+		same := true
+		for i, c := range fk.columns {
+			same = same && strings.EqualFold(existing.columns[i], c)
+		}
+		if same {
+			if existing.name == "" {
+				existing.name = fk.name
+			}
+			return
+		}
+	}
+	t.foreignKeys = append(t.foreignKeys, fk)
+}
+
+// Helperfunction to remove the foreign keys of a table matching drop.
+func (t *sqlTable) dropForeignKeys(drop func(fk *sqlForeignKey) bool) {
+	keep := t.foreignKeys[:0]
+	for _, fk := range t.foreignKeys {
+		if !drop(fk) {
+			keep = append(keep, fk)
+		}
+	}
+	t.foreignKeys = keep
+}
+
+// Helperfunction to get a column of a table, nil if it doesn't exist.
+func (t *sqlTable) column(name string) (column *sqlColumn) {
+	for _, c := range t.columns {
+		if strings.EqualFold(c.name, name) {
+			return c
+		}
+	}
+	return nil
+}
+
+////////// statements //////////////////////////////////////////////////////////
+
+// Helperfunction to parse a statement, unsupported ones are ignored.
+func (p *sqlParser) statement() (err error) {
+	switch {
+	case p.accept("CREATE"):
+		p.accept("OR", "REPLACE")
+		for p.accept("GLOBAL") || p.accept("LOCAL") || p.accept("TEMP") ||
+			p.accept("TEMPORARY") || p.accept("UNLOGGED") {
+		}
+		if p.accept("TABLE") {
+			return p.createTable()
+		}
+	case p.accept("ALTER", "TABLE"):
+		return p.alterTable()
+	case p.accept("DROP", "TABLE"):
+		return p.dropTable()
+	case p.accept("RENAME", "TABLE"):
+		return p.renameTable()
+	case p.accept("COMMENT", "ON", "COLUMN"):
+		return p.commentOn()
+	}
+	return nil
+}
+
+// Helperfunction to parse the rest of a CREATE TABLE statement.
+func (p *sqlParser) createTable() (err error) {
+	p.accept("IF", "NOT", "EXISTS")
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	if !p.is("(") {
+		// CREATE TABLE ... AS SELECT and the like
+		return nil
+	}
+	if p.tables[strings.ToLower(name)] != nil {
+		return p.errorf("table %s already exists", name)
+	}
+	table := &sqlTable{name: name}
+	p.tables[strings.ToLower(name)] = table
+	p.order = append(p.order, table)
+	p.pos++
+	for {
+		if err = p.definition(table); err != nil {
+			return err
+		}
+		if p.accept(")") {
+			return nil
+		}
+		if err = p.expect(","); err != nil {
+			return err
+		}
+	}
+}
+
+// Helperfunction to parse the rest of an ALTER TABLE statement.
+func (p *sqlParser) alterTable() (err error) {
+	p.accept("ONLY")
+	p.accept("IF", "EXISTS")
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	table, err := p.table(name)
+	if err != nil {
+		return err
+	}
+	for {
+		switch {
+		case p.accept("ADD"):
+			p.accept("COLUMN")
+			p.accept("IF", "NOT", "EXISTS")
+			err = p.definition(table)
+		case p.accept("DROP"):
+			err = p.drop(table)
+		case p.accept("RENAME"):
+			err = p.rename(table)
+		}
+		if err != nil {
+			return err
+		}
+		// skip unsupported actions
+		for !p.atEnd() {
+			p.skip()
+		}
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
+
+// Helperfunction to parse the DROP action of an ALTER TABLE statement.
+func (p *sqlParser) drop(table *sqlTable) (err error) {
+	switch {
+	case p.accept("COLUMN"):
+	case p.accept("CONSTRAINT") || p.accept("FOREIGN", "KEY"):
+		p.accept("IF", "EXISTS")
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		table.dropForeignKeys(func(fk *sqlForeignKey) bool {
+			return strings.EqualFold(fk.name, name)
+		})
+		return nil
+	case p.accept("PRIMARY", "KEY"):
+		for _, c := range table.columns {
+			c.primary = false
+		}
+		return nil
+	case p.is("INDEX") || p.is("KEY") || p.is("CHECK"):
+		// not part of the diagram
+		return nil
+	}
+	ifExists := p.accept("IF", "EXISTS")
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	column := table.column(name)
+	if column == nil {
+		if ifExists {
+			return nil
+		}
+		return p.errorf("unknown column %s", name)
+	}
+	for i, c := range table.columns {
+		if c == column {
+			table.columns = append(table.columns[:i], table.columns[i+1:]...)
+			break
+		}
+	}
+	table.dropForeignKeys(func(fk *sqlForeignKey) bool {
+		for _, c := range fk.columns {
+			if strings.EqualFold(c, name) {
+				return true
+			}
+		}
+		return false
+	})
+	return nil
+}
+
+// Helperfunction to parse the RENAME action of an ALTER TABLE statement.
+func (p *sqlParser) rename(table *sqlTable) (err error) {
+	if p.accept("TO") || p.accept("AS") {
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		return p.renameTo(table, name)
+	}
+	if p.accept("INDEX") || p.accept("KEY") {
+		// not part of the diagram
+		return nil
+	}
+	constraint := p.accept("CONSTRAINT")
+	p.accept("COLUMN")
+	from, err := p.name()
+	if err != nil {
+		return err
+	}
+	if err = p.expect("TO"); err != nil {
+		return err
+	}
+	to, err := p.name()
+	if err != nil {
+		return err
+	}
+	if constraint {
+		for _, fk := range table.foreignKeys {
+			if strings.EqualFold(fk.name, from) {
+				fk.name = to
+			}
+		}
+		return nil
+	}
+	column := table.column(from)
+	if column == nil {
+		return p.errorf("unknown column %s", from)
+	}
+	if !strings.EqualFold(from, to) && table.column(to) != nil {
+		return p.errorf("column %s already exists", to)
+	}
+	column.name = to
+	for _, fk := range table.foreignKeys {
+		for i, c := range fk.columns {
+			if strings.EqualFold(c, from) {
+				fk.columns[i] = to
+			}
+		}
+	}
+	return nil
+}
+
+// Helperfunction to parse the rest of a DROP TABLE statement.
+func (p *sqlParser) dropTable() (err error) {
+	ifExists := p.accept("IF", "EXISTS")
+	for {
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		table := p.tables[strings.ToLower(name)]
+		if table == nil && !ifExists {
+			return p.errorf("unknown table %s", name)
+		}
+		if table != nil {
+			delete(p.tables, strings.ToLower(name))
+			for i, t := range p.order {
+				if t == table {
+					p.order = append(p.order[:i], p.order[i+1:]...)
+					break
+				}
+			}
+			for _, t := range p.order {
+				t.dropForeignKeys(func(fk *sqlForeignKey) bool {
+					return strings.EqualFold(fk.table, name)
+				})
+			}
+		}
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
+
+// Helperfunction to parse the rest of a RENAME TABLE statement.
+func (p *sqlParser) renameTable() (err error) {
+	for {
+		from, err := p.name()
+		if err != nil {
+			return err
+		}
+		table, err := p.table(from)
+		if err != nil {
+			return err
+		}
+		if err = p.expect("TO"); err != nil {
+			return err
+		}
+		to, err := p.name()
+		if err != nil {
+			return err
+		}
+		if err = p.renameTo(table, to); err != nil {
+			return err
+		}
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
+
+// Helperfunction to rename a table, foreign keys referencing it are updated.
+func (p *sqlParser) renameTo(table *sqlTable, name string) (err error) {
+	if t := p.tables[strings.ToLower(name)]; t != nil && t != table {
+		return p.errorf("table %s already exists", name)
+	}
+	for _, t := range p.order {
+		for _, fk := range t.foreignKeys {
+			if strings.EqualFold(fk.table, table.name) {
+				fk.table = name
+			}
+		}
+	}
+	delete(p.tables, strings.ToLower(table.name))
+	p.tables[strings.ToLower(name)] = table
+	table.name = name
+	return nil
+}
+
+// Helperfunction to parse the rest of a COMMENT ON COLUMN statement.
+func (p *sqlParser) commentOn() (err error) {
+	names, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if len(names) < 2 {
+		return p.errorf("table.column expected")
+	}
+	table, err := p.table(names[len(names)-2])
+	if err != nil {
+		return err
+	}
+	column := table.column(names[len(names)-1])
+	if column == nil {
+		return p.errorf("unknown column %s", names[len(names)-1])
+	}
+	if err = p.expect("IS"); err != nil {
+		return err
+	}
+	if p.pos < len(p.tokens) && p.tokens[p.pos].kind == 's' {
+		column.comment = p.tokens[p.pos].text
+	} else if p.accept("NULL") {
+		column.comment = ""
+	} else {
+		return p.errorf("comment expected")
+	}
+	return nil
+}
+
+// Helperfunction to parse a column definition or a table constraint.
+func (p *sqlParser) definition(table *sqlTable) (err error) {
+	if p.isTableConstraint() {
+		return p.tableConstraint(table)
+	}
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	if table.column(name) != nil {
+		return p.errorf("column %s already exists", name)
+	}
+	column := &sqlColumn{name: name}
+	table.columns = append(table.columns, column)
+	// data type: words, arguments and array brackets
+	var dataType []string
+	for !p.atEnd() && p.tokens[p.pos].kind != 's' {
+		t := p.tokens[p.pos]
+		upper := strings.ToUpper(t.text)
+		if t.kind == 'w' && (sqlColumnKeywords[upper] ||
+			upper == "CHARACTER" && len(dataType) > 0 &&
+				p.pos+1 < len(p.tokens) &&
+				strings.EqualFold(p.tokens[p.pos+1].text, "SET")) {
+			break
+		}
+		switch {
+		case p.is("("):
+			start := p.pos + 1
+			p.skip()
+			var args []string
+			for _, a := range p.tokens[start : p.pos-1] {
+				if a.text != "," {
+					args = append(args, a.text)
+				}
+			}
+			dataType = append(dataType, "("+strings.Join(args, "-")+")")
+		case p.is("[") || p.is("]"):
+			dataType = append(dataType, t.text)
+			p.pos++
+		default:
+			if len(dataType) > 0 && t.kind == 'w' {
+				dataType = append(dataType, "_")
+			}
+			dataType = append(dataType, t.text)
+			p.pos++
+		}
+	}
+	column.dataType = strings.Join(dataType, "")
+	// column constraints
+	constraint := ""
+	for !p.atEnd() {
+		if p.accept("CONSTRAINT") {
+			if constraint, err = p.name(); err != nil {
+				return err
+			}
+			continue
+		}
+		switch {
+		case p.accept("PRIMARY", "KEY"):
+			column.primary, column.notNull = true, true
+		case p.accept("UNIQUE"):
+			column.unique = true
+		case p.accept("NOT", "NULL"):
+			column.notNull = true
+		case p.accept("REFERENCES"):
+			parent, err := p.name()
+			if err != nil {
+				return err
+			}
+			table.addForeignKey(&sqlForeignKey{name: constraint,
+				columns: []string{name}, table: parent})
+		case p.accept("COMMENT"):
+			if p.pos < len(p.tokens) && p.tokens[p.pos].kind == 's' {
+				column.comment = p.tokens[p.pos].text
+			}
+		default:
+			p.skip()
+		}
+		constraint = ""
+	}
+	return nil
+}
+
+// Helperfunction to check whether a table constraint starts at the current
+// token. Keywords like KEY or CHECK are only taken as such if followed by
+// constraint syntax, they may be column names too.
+func (p *sqlParser) isTableConstraint() bool {
+	// upper case keyword or punctuation i tokens ahead, "" for other tokens
+	peek := func(i int) string {
+		if p.pos+i >= len(p.tokens) || p.tokens[p.pos+i].kind == 's' ||
+			p.tokens[p.pos+i].kind == 'i' {
+			return ""
+		}
+		return strings.ToUpper(p.tokens[p.pos+i].text)
+	}
+	// whether the token i tokens ahead is a name
+	isName := func(i int) bool {
+		if p.pos+i >= len(p.tokens) {
+			return false
+		}
+		t := p.tokens[p.pos+i]
+		return t.kind == 'i' || t.kind == 'w' && (t.text[0] < '0' || t.text[0] > '9')
+	}
+	// whether an optionally named index column list follows, not to be mixed
+	// up with data types like varchar(10)
+	indexColumns := func(i int) bool {
+		return peek(i) == "(" || isName(i) && (peek(i+1) == "USING" ||
+			peek(i+1) == "(" && isName(i+2))
+	}
+	switch peek(0) {
+	case "CONSTRAINT", "UNIQUE":
+		return true
+	case "PRIMARY", "FOREIGN":
+		return peek(1) == "KEY"
+	case "CHECK":
+		return peek(1) == "("
+	case "KEY", "INDEX":
+		return indexColumns(1)
+	case "FULLTEXT", "SPATIAL":
+		return peek(1) == "KEY" || peek(1) == "INDEX" || indexColumns(1)
+	case "EXCLUDE":
+		return peek(1) == "USING" || peek(1) == "("
+	case "LIKE":
+		i := 1
+		for isName(i) && peek(i+1) == "." {
+			i += 2
+		}
+		return isName(i) && (p.pos+i+1 >= len(p.tokens) || peek(i+1) == "," ||
+			peek(i+1) == ")" || peek(i+1) == "INCLUDING" ||
+			peek(i+1) == "EXCLUDING")
+	case "PERIOD":
+		return peek(1) == "FOR"
+	}
+	return false
+}
+
+// Helperfunction to parse a table constraint, unsupported ones are ignored.
+func (p *sqlParser) tableConstraint(table *sqlTable) (err error) {
+	var constraint string
+	if p.accept("CONSTRAINT") {
+		if constraint, err = p.name(); err != nil {
+			return err
+		}
+	}
+	var columns []string
+	switch {
+	case p.accept("PRIMARY", "KEY"):
+		if columns, err = p.nameList(); err != nil {
+			return err
+		}
+		for _, name := range columns {
+			if c := table.column(name); c != nil {
+				c.primary, c.notNull = true, true
+			}
+		}
+	case p.accept("UNIQUE"):
+		_ = p.accept("KEY") || p.accept("INDEX")
+		if !p.is("(") {
+			// optional index name
+			if _, err = p.name(); err != nil {
+				return err
+			}
+		}
+		if columns, err = p.nameList(); err != nil {
+			return err
+		}
+		for _, name := range columns {
+			if c := table.column(name); c != nil {
+				c.unique = true
+			}
+		}
+	case p.accept("FOREIGN", "KEY"):
+		if !p.is("(") {
+			// optional index name
+			if _, err = p.name(); err != nil {
+				return err
+			}
+		}
+		if columns, err = p.nameList(); err != nil {
+			return err
+		}
+		if err = p.expect("REFERENCES"); err != nil {
+			return err
+		}
+		parent, err := p.name()
+		if err != nil {
+			return err
+		}
+		table.addForeignKey(&sqlForeignKey{name: constraint,
+			columns: columns, table: parent})
+	}
+	// skip the rest, e.g. referenced columns and ON DELETE clauses
+	for !p.atEnd() {
+		p.skip()
+	}
+	return nil
+}
+
+////////// diagram creation ////////////////////////////////////////////////////
+
+// Helperfunction to sanitize a name so it can be used as ID.
+func sqlID(name string) (id string) {
+	id = sqlInvalidChars.ReplaceAllString(name, "_")
+	if !IsValidID(id) {
+		// empty or starting with a digit
+		id = "_" + id
+	}
+	return
+}
+
+// Helperfunction to create the ERDiagram from the tables read.
+func (p *sqlParser) diagram() (ed *ERDiagram) {
+	ed = NewERDiagram()
+	entities := make(map[string]*Entity)
+	entity := func(name string) *Entity {
+		if e := entities[strings.ToLower(name)]; e != nil {
+			return e
+		}
+		id := sqlID(name)
+		for i := 2; ed.GetEntity(id) != nil; i++ {
+			id = fmt.Sprintf("%s_%d", sqlID(name), i)
+		}
+		e, _ := ed.AddEntity(id)
+		if id != name {
+			e.Label = name
+		}
+		entities[strings.ToLower(name)] = e
+		return e
+	}
+	for _, table := range p.order {
+		e := entity(table.name)
+		foreign := make(map[*sqlColumn]bool)
+		for _, fk := range table.foreignKeys {
+			for _, name := range fk.columns {
+				foreign[table.column(name)] = true
+			}
+		}
+		for _, c := range table.columns {
+			a := e.AddAttribute(c.dataType, sqlID(c.name))
+			if c.primary {
+				a.AddKey(KeyPrimary)
+			}
+			if foreign[c] {
+				a.AddKey(KeyForeign)
+			}
+			if c.unique {
+				a.AddKey(KeyUnique)
+			}
+			a.Comment = c.comment
+		}
+	}
+	for _, table := range p.order {
+		for _, fk := range table.foreignKeys {
+			p.relationship(ed, entity(fk.table), entity(table.name), table, fk)
+		}
+	}
+	return ed
+}
+
+// Helperfunction to add the Relationship for a foreign key.
+func (p *sqlParser) relationship(ed *ERDiagram, parent, child *Entity,
+	table *sqlTable, fk *sqlForeignKey) {
+	notNull, identifying := true, true
+	primaryKey := 0
+	for _, c := range table.columns {
+		if c.primary {
+			primaryKey++
+		}
+	}
+	for _, name := range fk.columns {
+		c := table.column(name)
+		if c == nil {
+			notNull, identifying = false, false
+			continue
+		}
+		notNull = notNull && c.notNull
+		identifying = identifying && c.primary
+	}
+	unique := identifying && primaryKey == len(fk.columns)
+	if len(fk.columns) == 1 {
+		if c := table.column(fk.columns[0]); c != nil && c.unique {
+			unique = true
+		}
+	}
+	r, _ := ed.AddRelationship(parent, child, strings.Join(fk.columns, ", "))
+	r.Identifying = identifying
+	if !notNull {
+		r.FromCardinality = CardinalityZeroOrOne
+	}
+	if unique {
+		r.ToCardinality = CardinalityZeroOrOne
+	}
+}
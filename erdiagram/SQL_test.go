@@ -0,0 +1,217 @@
+package erdiagram_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen/erdiagram"
+)
+
+// Generating an entity relationship diagram from SQL DDL
+func ExampleFromSQL() {
+	ddl := `
+CREATE TABLE users (
+  id serial PRIMARY KEY,
+  email varchar(255) NOT NULL UNIQUE,
+  price numeric(10, 2) DEFAULT 0
+);
+CREATE TABLE profiles (
+  user_id integer PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+  bio text
+);
+CREATE TABLE orders (id bigint NOT NULL, user_id int);
+ALTER TABLE orders
+  ADD CONSTRAINT pk_orders PRIMARY KEY (id),
+  ADD FOREIGN KEY (user_id) REFERENCES users (id);
+CREATE TABLE "order items" (
+  order_id bigint NOT NULL,
+  line int NOT NULL,
+  PRIMARY KEY (order_id, line),
+  CONSTRAINT fk_order FOREIGN KEY (order_id) REFERENCES orders (id)
+);
+COMMENT ON COLUMN public.orders.user_id IS 'who ordered';
+`
+	ed, _ := erdiagram.FromSQL(strings.NewReader(ddl))
+	fmt.Print(ed)
+	//Output:
+	//erDiagram
+	//users {
+	//   serial id PK
+	//   varchar(255) email UK
+	//   numeric(10-2) price
+	//}
+	//profiles {
+	//   integer user_id PK, FK
+	//   text bio
+	//}
+	//orders {
+	//   bigint id PK
+	//   int user_id FK "who ordered"
+	//}
+	//order_items["order items"] {
+	//   bigint order_id PK, FK
+	//   int line PK
+	//}
+	//users ||--o| profiles : user_id
+	//users |o..o{ orders : user_id
+	//orders ||--o{ order_items : order_id
+}
+
+func TestFromSQLFiles(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "erdiagram")
+	defer os.RemoveAll(dir)
+	files := map[string]string{
+		"001_users.up.sql":   "CREATE TABLE users (id int PRIMARY KEY);",
+		"001_users.down.sql": "DROP TABLE users;",
+		"002_posts.up.sql": "/* posts */ CREATE TABLE posts (id int, " +
+			"`author` int NOT NULL COMMENT 'it''s the author'," +
+			" CONSTRAINT fk FOREIGN KEY (author) REFERENCES users(id));",
+		"003_func.up.sql": "CREATE FUNCTION f() RETURNS trigger AS " +
+			"$body$ BEGIN; END; $body$ LANGUAGE plpgsql;\n" +
+			"ALTER TABLE posts ADD COLUMN title text UNIQUE;",
+		"notes.txt": "CREATE TABLE ignored (id int);",
+	}
+	for name, content := range files {
+		ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+	}
+	ed, err := erdiagram.FromSQLFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `erDiagram
+users {
+  int id PK
+}
+posts {
+  int id
+  int author FK "it's the author"
+  text title UK
+}
+users ||..o{ posts : author
+`
+	if ed.String() != expected {
+		t.Errorf("unexpected diagram:\n%s", ed)
+	}
+	if _, err = erdiagram.FromSQLFiles(filepath.Join(dir, "missing")); err == nil {
+		t.Error("missing file not detected")
+	}
+}
+
+func TestFromSQL_duplicateForeignKeys(t *testing.T) {
+	ed, err := erdiagram.FromSQL(strings.NewReader(
+		"CREATE TABLE users (id int PRIMARY KEY);\n" +
+			"CREATE TABLE orders (id int, user_id int REFERENCES users(id), " +
+			"CONSTRAINT fk_user FOREIGN KEY (user_id) REFERENCES users (id));\n" +
+			"ALTER TABLE orders ADD FOREIGN KEY (USER_ID) REFERENCES Users;"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `erDiagram
+users {
+  int id PK
+}
+orders {
+  int id
+  int user_id FK
+}
+users |o..o{ orders : user_id
+`
+	if ed.String() != expected {
+		t.Errorf("unexpected diagram:\n%s", ed)
+	}
+}
+
+func TestFromSQL_keywordColumns(t *testing.T) {
+	ed, err := erdiagram.FromSQL(strings.NewReader(
+		"CREATE TABLE settings (key text PRIMARY KEY, value text, " +
+			"index int, period varchar(10), check bool, KEY idx (value), " +
+			"INDEX (index), CHECK (index > 0), UNIQUE KEY u (period));"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `erDiagram
+settings {
+  text key PK
+  text value
+  int index
+  varchar(10) period UK
+  bool check
+}
+`
+	if ed.String() != expected {
+		t.Errorf("unexpected diagram:\n%s", ed)
+	}
+}
+
+func TestFromSQL_dropAndRename(t *testing.T) {
+	ed, err := erdiagram.FromSQL(strings.NewReader(`
+CREATE TABLE a (id int PRIMARY KEY, x int);
+CREATE TABLE b (id int, a_id int CONSTRAINT fk_a REFERENCES a, old text);
+DROP TABLE a;
+CREATE TABLE a (id int PRIMARY KEY, b_id int REFERENCES b);
+CREATE TABLE c (id int, b_id int NOT NULL, tmp int REFERENCES a,
+  CONSTRAINT fk_b FOREIGN KEY (b_id) REFERENCES b);
+ALTER TABLE b DROP COLUMN a_id, RENAME COLUMN old TO new;
+ALTER TABLE b RENAME TO bb;
+RENAME TABLE c TO cc;
+ALTER TABLE cc DROP tmp, DROP COLUMN IF EXISTS missing, DROP INDEX idx;
+ALTER TABLE a DROP CONSTRAINT IF EXISTS none, DROP PRIMARY KEY;
+DROP TABLE IF EXISTS missing;`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `erDiagram
+bb {
+  int id
+  text new
+}
+a {
+  int id
+  int b_id FK
+}
+cc {
+  int id
+  int b_id FK
+}
+bb |o..o{ a : b_id
+bb ||..o{ cc : b_id
+`
+	if ed.String() != expected {
+		t.Errorf("unexpected diagram:\n%s", ed)
+	}
+	ed, err = erdiagram.FromSQL(strings.NewReader(
+		"CREATE TABLE a (id int);\nCREATE TABLE b (a_id int CONSTRAINT " +
+			"fk REFERENCES a);\nALTER TABLE b DROP CONSTRAINT fk;"))
+	if err != nil || strings.Contains(ed.String(), "||") ||
+		strings.Contains(ed.String(), "|o") {
+		t.Errorf("foreign key not dropped:\n%s", ed)
+	}
+}
+
+func TestFromSQL_errors(t *testing.T) {
+	for ddl, expected := range map[string]string{
+		"CREATE TABLE a (id int);\nCREATE TABLE a (id int);":                       "line 2: table a already exists",
+		"CREATE TABLE a (id int, id int);":                                         "line 1: column id already exists",
+		"ALTER TABLE a ADD COLUMN x int;":                                          "line 1: unknown table a",
+		"CREATE TABLE a (id int\n\n":                                               "line 1: , expected",
+		"CREATE TABLE a (id int, PRIMARY KEY id);":                                 "line 1: ( expected",
+		"COMMENT ON COLUMN x IS 'y';":                                              "line 1: table.column expected",
+		"CREATE TABLE a (id int);\nCOMMENT ON COLUMN a.x IS 'y';":                  "line 2: unknown column x",
+		"CREATE TABLE a (id 'int);":                                                "line 1: ' not closed",
+		"DROP TABLE a;":                                                            "line 1: unknown table a",
+		"CREATE TABLE a (id int);\nALTER TABLE a DROP x;":                          "line 2: unknown column x",
+		"CREATE TABLE a (id int);\nALTER TABLE a RENAME x TO y;":                   "line 2: unknown column x",
+		"CREATE TABLE a (id int, x int);\nALTER TABLE a RENAME x TO id;":           "line 2: column id already exists",
+		"CREATE TABLE a (id int);\nCREATE TABLE b (id int);\nRENAME TABLE a TO b;": "line 3: table b already exists",
+		"/* CREATE TABLE a (id int);":                                              "line 1: comment not closed",
+	} {
+		_, err := erdiagram.FromSQL(strings.NewReader(ddl))
+		if err == nil || err.Error() != "FromSQL: "+expected {
+			t.Errorf("%q: unexpected error %v", ddl, err)
+		}
+	}
+}
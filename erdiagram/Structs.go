@@ -0,0 +1,352 @@
+package erdiagram
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+////////// struct import ///////////////////////////////////////////////////////
+
+// Implemented by models with a custom table name, as known from gorm.
+type tabler interface {
+	TableName() string
+}
+
+// A model passed to FromStructs.
+type structModel struct {
+	typ       reflect.Type
+	name      string          // table name
+	entity    *Entity         // created in the second pass
+	columns   []*structColumn // columns in field order
+	relations []reflect.StructField
+}
+
+// A column of a structModel.
+type structColumn struct {
+	goName, name, dataType, comment string
+	primary, unique, nullable       bool
+	foreign                         bool
+}
+
+// Internal state of the struct importer.
+type structImporter struct {
+	models []*structModel
+	byType map[reflect.Type]*structModel
+	ed     *ERDiagram
+	seen   map[string]bool // relationships already added
+}
+
+// FromStructs creates an ERDiagram from Go structs (or pointers to them) by
+// inspecting their fields via reflection, e.g. the models of an ORM. Each
+// struct becomes an Entity named by its TableName method if it has one,
+// otherwise by its type name. Each exported field becomes an Attribute, the
+// fields of embedded structs (e.g. gorm.Model) are inlined.
+//
+// The tags db (sqlx), gorm and sql (gorm v1, go-pg) are evaluated:
+//
+//	db:"name"                   column name
+//	gorm:"column:name"          column name
+//	gorm:"type:varchar(100)"    data type, Go type name if not set
+//	gorm:"primaryKey"           KeyPrimary, field ID if no key is tagged
+//	gorm:"unique"               KeyUnique, also uniqueIndex
+//	gorm:"not null"             not nullable, otherwise pointers and
+//	                            sql.Null* types are nullable
+//	gorm:"comment:text"         comment of the Attribute
+//	gorm:"foreignKey:UserID"    foreign key field of a relation
+//	gorm:"many2many:table"      many-to-many relation via join table
+//	sql:"name,pk,unique,type:x" the same for the sql tag
+//	db:"-", gorm:"-", sql:"-"   ignore the field
+//
+// Columns get the snake case field name if no name is tagged.
+//
+// Fields with another struct of models as type define relations, as known
+// from gorm: a struct field Author belongs to its model if there is a foreign
+// key field AuthorID (or as tagged), otherwise the model has one of it if that
+// has a field named by the model's type plus ID. A slice field means the model
+// has many of them, using the same foreign key field. Additionally, columns
+// like user_id reference the model user (or users) if there is one. Foreign
+// keys get KeyForeign, relations become Relationships as described for
+// FromSQL.
+func FromStructs(models ...interface{}) (newERDiagram *ERDiagram, err error) {
+	si := &structImporter{byType: make(map[reflect.Type]*structModel),
+		ed: NewERDiagram(), seen: make(map[string]bool)}
+	for _, model := range models {
+		t := reflect.TypeOf(model)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("FromStructs: %T is no struct", model)
+		}
+		if si.byType[t] != nil {
+			return nil, fmt.Errorf("FromStructs: %s passed twice", t)
+		}
+		m := &structModel{typ: t, name: t.Name()}
+		if tn, ok := reflect.New(t).Interface().(tabler); ok {
+			m.name = tn.TableName()
+		}
+		si.byType[t] = m
+		si.models = append(si.models, m)
+	}
+	// columns need all models to tell them from relations
+	for _, m := range si.models {
+		si.fields(m, m.typ, "")
+		primary := false
+		for _, c := range m.columns {
+			primary = primary || c.primary
+		}
+		if !primary {
+			for _, c := range m.columns {
+				c.primary = c.primary || c.goName == "ID"
+			}
+		}
+		id := sqlID(m.name)
+		if m.entity, err = si.ed.AddEntity(id); err != nil {
+			return nil, fmt.Errorf("FromStructs: %s: %v", m.name, err)
+		}
+		if id != m.name {
+			m.entity.Label = m.name
+		}
+	}
+	for _, m := range si.models {
+		for _, f := range m.relations {
+			si.relation(m, f)
+		}
+	}
+	for _, m := range si.models {
+		si.namedForeignKeys(m)
+	}
+	for _, m := range si.models {
+		for _, c := range m.columns {
+			a := m.entity.AddAttribute(c.dataType, sqlID(c.name))
+			if c.primary {
+				a.AddKey(KeyPrimary)
+			}
+			if c.foreign {
+				a.AddKey(KeyForeign)
+			}
+			if c.unique {
+				a.AddKey(KeyUnique)
+			}
+			a.Comment = c.comment
+		}
+	}
+	return si.ed, nil
+}
+
+// Helperfunction to get the options of all tags of a field, keys are lower
+// case, flags have empty values. The column name is stored as "column".
+func structTags(f reflect.StructField) (options map[string]string) {
+	options = make(map[string]string)
+	if db, ok := f.Tag.Lookup("db"); ok {
+		options["column"] = strings.Split(db, ",")[0]
+	}
+	for _, tag := range []string{"sql", "gorm"} {
+		value, ok := f.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
+		// sql uses , as separator, gorm uses ;
+		separator := ";"
+		if tag == "sql" {
+			separator = ","
+		}
+		for i, option := range strings.Split(value, separator) {
+			option = strings.TrimSpace(option)
+			kv := strings.SplitN(option, ":", 2)
+			key := strings.ToLower(strings.Replace(kv[0], "_", "", -1))
+			switch {
+			case option == "":
+			case option == "-":
+				options["column"] = "-"
+			case len(kv) == 2:
+				options[key] = kv[1]
+			case tag == "sql" && i == 0:
+				options["column"] = option
+			default:
+				options[key] = ""
+			}
+		}
+	}
+	return
+}
+
+// Helperfunction to check whether a tag option is set.
+func hasOption(options map[string]string, keys ...string) bool {
+	for _, key := range keys {
+		if _, ok := options[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Helperfunction to get the model of a type, pointers and slices of models
+// included. many is true for slices.
+func (si *structImporter) modelOf(t reflect.Type) (m *structModel, many bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		many, t = true, t.Elem()
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	return si.byType[t], many
+}
+
+// Helperfunction to collect the columns and relations of a struct type,
+// embedded structs are inlined with the given column name prefix.
+func (si *structImporter) fields(m *structModel, t reflect.Type,
+	prefix string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		options := structTags(f)
+		if options["column"] == "-" || f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+		if related, _ := si.modelOf(f.Type); related != nil &&
+			!f.Anonymous && !hasOption(options, "embedded") {
+			m.relations = append(m.relations, f)
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && (f.Anonymous ||
+			hasOption(options, "embedded")) {
+			si.fields(m, ft, prefix+options["embeddedprefix"])
+			continue
+		}
+		c := &structColumn{goName: f.Name, name: options["column"],
+			dataType: options["type"], comment: options["comment"]}
+		if c.name == "" {
+			c.name = snakeCase(f.Name)
+		}
+		c.name = prefix + c.name
+		if c.dataType == "" {
+			c.dataType = ft.Name()
+			if c.dataType == "" {
+				c.dataType = ft.Kind().String()
+			}
+		}
+		c.dataType = strings.Replace(strings.Replace(strings.TrimSpace(
+			c.dataType), " ", "_", -1), ",", "-", -1)
+		c.primary = hasOption(options, "primarykey", "pk")
+		c.unique = hasOption(options, "unique", "uniqueindex")
+		c.nullable = (f.Type.Kind() == reflect.Ptr ||
+			strings.HasPrefix(ft.Name(), "Null")) &&
+			!hasOption(options, "not null", "notnull") && !c.primary
+		m.columns = append(m.columns, c)
+	}
+}
+
+// Helperfunction to look up a column by its Go field name.
+func (m *structModel) column(goName string) (column *structColumn) {
+	for _, c := range m.columns {
+		if c.goName == goName {
+			return c
+		}
+	}
+	return nil
+}
+
+// Helperfunction to add the Relationship for a relation field of a model.
+func (si *structImporter) relation(m *structModel, f reflect.StructField) {
+	options := structTags(f)
+	related, many := si.modelOf(f.Type)
+	if table, ok := options["many2many"]; ok {
+		if !si.seen["many2many "+table] {
+			si.seen["many2many "+table] = true
+			si.ed.AddRelationship(m.entity, related.entity, table,
+				CardinalityZeroOrMore, CardinalityZeroOrMore)
+		}
+		return
+	}
+	foreignKey := options["foreignkey"]
+	if !many {
+		// belongs to: the foreign key is part of this model
+		name := foreignKey
+		if name == "" {
+			name = f.Name + "ID"
+		}
+		if c := m.column(name); c != nil {
+			si.foreignKey(related, m, c, CardinalityZeroOrMore)
+			return
+		}
+	}
+	// has one or has many: the foreign key is part of the related model
+	if foreignKey == "" {
+		foreignKey = m.typ.Name() + "ID"
+	}
+	if c := related.column(foreignKey); c != nil {
+		cardinality := CardinalityZeroOrOne
+		if many {
+			cardinality = CardinalityZeroOrMore
+		}
+		si.foreignKey(m, related, c, cardinality)
+	}
+}
+
+// Helperfunction to mark a foreign key column and add its Relationship once.
+func (si *structImporter) foreignKey(parent, child *structModel,
+	c *structColumn, toCardinality cardinality) {
+	c.foreign = true
+	key := child.name + "." + c.name
+	if si.seen[key] {
+		return
+	}
+	si.seen[key] = true
+	r, _ := si.ed.AddRelationship(parent.entity, child.entity, c.name)
+	r.Identifying = c.primary
+	if c.nullable {
+		r.FromCardinality = CardinalityZeroOrOne
+	}
+	if c.unique && toCardinality == CardinalityZeroOrMore {
+		toCardinality = CardinalityZeroOrOne
+	}
+	r.ToCardinality = toCardinality
+}
+
+// Helperfunction to detect foreign keys by name, e.g. user_id references the
+// model user or users.
+func (si *structImporter) namedForeignKeys(m *structModel) {
+	for _, c := range m.columns {
+		if c.foreign || !strings.HasSuffix(c.name, "_id") {
+			continue
+		}
+		prefix := strings.TrimSuffix(c.name, "_id")
+		for _, parent := range si.models {
+			name := strings.ToLower(parent.name)
+			if name == prefix || name == prefix+"s" || name == prefix+"es" ||
+				snakeCase(parent.typ.Name()) == prefix {
+				si.foreignKey(parent, m, c, CardinalityZeroOrMore)
+				break
+			}
+		}
+	}
+}
+
+// Helperfunction to convert a Go name to snake case, e.g. UserID to user_id.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// a new word starts after a lower case letter or at the last upper
+			// case letter of an abbreviation followed by lower case letters
+			if i > 0 && (unicode.IsLower(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1]) &&
+					unicode.IsUpper(runes[i-1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
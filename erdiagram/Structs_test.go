@@ -0,0 +1,111 @@
+package erdiagram_test
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Heiko-san/mermaidgen/erdiagram"
+)
+
+// Model is the base of the example models, like gorm.Model.
+type Model struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+}
+
+type User struct {
+	Model
+	Email   string  `gorm:"type:varchar(255);unique;comment:login name"`
+	Profile Profile // has one
+	Posts   []Post  `gorm:"foreignKey:AuthorID"` // has many
+}
+
+type Profile struct {
+	UserID uint `gorm:"primaryKey"`
+	Bio    sql.NullString
+}
+
+type Post struct {
+	Model
+	AuthorID *uint
+	Author   *User // belongs to
+	Tags     []Tag `gorm:"many2many:post_tags"`
+	internal string
+}
+
+type Tag struct {
+	Name string `gorm:"primaryKey"`
+}
+
+// Comment is an sqlx model without relation fields.
+type Comment struct {
+	ID     int64  `db:"id"`
+	PostID int64  `db:"post_id"`
+	Text   string `db:"body"`
+	Secret string `db:"-"`
+}
+
+func (Comment) TableName() string { return "comments" }
+
+// Generating an entity relationship diagram from ORM models
+func ExampleFromStructs() {
+	ed, _ := erdiagram.FromStructs(User{}, &Profile{}, Post{}, Tag{},
+		Comment{})
+	fmt.Print(ed)
+	//Output:
+	//erDiagram
+	//User {
+	//   uint id PK
+	//   Time created_at
+	//   varchar(255) email UK "login name"
+	//}
+	//Profile {
+	//   uint user_id PK, FK
+	//   NullString bio
+	//}
+	//Post {
+	//   uint id PK
+	//   Time created_at
+	//   uint author_id FK
+	//}
+	//Tag {
+	//   string name PK
+	//}
+	//comments {
+	//   int64 id PK
+	//   int64 post_id FK
+	//   string body
+	//}
+	//User ||--o| Profile : user_id
+	//User |o..o{ Post : author_id
+	//Post }o..o{ Tag : post_tags
+	//Post ||..o{ comments : post_id
+}
+
+func TestFromStructs_errors(t *testing.T) {
+	if _, err := erdiagram.FromStructs(1); err == nil {
+		t.Error("non struct not detected")
+	}
+	if _, err := erdiagram.FromStructs(Tag{}, &Tag{}); err == nil {
+		t.Error("duplicate model not detected")
+	}
+}
+
+func TestFromStructs_tags(t *testing.T) {
+	type Account struct {
+		Key   string  `sql:"account_key,pk,type:char(8)"`
+		Owner *string `gorm:"column:owner_name;not null"`
+		Plain int
+	}
+	ed, err := erdiagram.FromStructs(Account{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "erDiagram\nAccount {\n  char(8) account_key PK\n" +
+		"  string owner_name\n  int plain\n}\n"
+	if ed.String() != expected {
+		t.Errorf("unexpected diagram:\n%s", ed)
+	}
+}
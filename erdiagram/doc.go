@@ -0,0 +1,39 @@
+/*
+Package erdiagram is an object oriented approach to define mermaid entity
+relationship diagrams as defined at
+https://mermaid.js.org/syntax/entityRelationshipDiagram.html and render them to
+mermaid code.
+
+You use the constructor NewERDiagram to create a new ERDiagram object.
+
+	ed := erdiagram.NewERDiagram()
+
+This object is used to add Entities with typed Attributes and Relationships
+between them.
+
+	customer, _ := ed.AddEntity("CUSTOMER")
+	customer.AddAttribute("int", "id", erdiagram.KeyPrimary)
+	order, _ := ed.AddEntity("ORDER")
+	ed.AddRelationship(customer, order, "places")
+
+Once the diagram is completely defined, it can be "rendered" to mermaid code by
+stringifying the ERDiagram object or streamed via WriteTo.
+
+	erDiagram
+	CUSTOMER {
+	  int id PK
+	}
+	ORDER
+	CUSTOMER ||..o{ ORDER : places
+
+Instead of defining it manually, an ERDiagram can be generated from SQL DDL
+via FromSQL or from a folder of migration files via FromSQLFiles.
+
+	ed, err := erdiagram.FromSQLFiles("db/migrations")
+
+Models that only exist as Go structs are converted via FromStructs, which
+evaluates the db, gorm and sql tags and the relations between the structs.
+
+	ed, err := erdiagram.FromStructs(User{}, Post{})
+*/
+package erdiagram
@@ -0,0 +1,18 @@
+package flowchart
+
+import (
+	"github.com/Heiko-san/mermaidgen"
+)
+
+// Color definitions for use with NodeStyles and EdgeStyles.
+// See mermaidgen.Color for constructors of arbitrary Colors and helpers.
+const (
+	ColorBlack   = mermaidgen.ColorBlack
+	ColorBlue    = mermaidgen.ColorBlue
+	ColorGreen   = mermaidgen.ColorGreen
+	ColorCyan    = mermaidgen.ColorCyan
+	ColorRed     = mermaidgen.ColorRed
+	ColorMagenta = mermaidgen.ColorMagenta
+	ColorYellow  = mermaidgen.ColorYellow
+	ColorWhite   = mermaidgen.ColorWhite
+)
@@ -0,0 +1,82 @@
+package flowchart
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
+)
+
+type fontWeight string
+
+// Font weight definitions for use with NodeStyles and EdgeStyles.
+// The default is no font-weight statement which results in FontWeightNormal.
+const (
+	FontWeightNormal  fontWeight = `normal`
+	FontWeightBold    fontWeight = `bold`
+	FontWeightBolder  fontWeight = `bolder`
+	FontWeightLighter fontWeight = `lighter`
+	FontWeight100     fontWeight = `100`
+	FontWeight200     fontWeight = `200`
+	FontWeight300     fontWeight = `300`
+	FontWeight400     fontWeight = `400`
+	FontWeight500     fontWeight = `500`
+	FontWeight600     fontWeight = `600`
+	FontWeight700     fontWeight = `700`
+	FontWeight800     fontWeight = `800`
+	FontWeight900     fontWeight = `900`
+)
+
+type strokeLinecap string
+
+// Line cap definitions for use with NodeStyles and EdgeStyles.
+// The default is no stroke-linecap statement which results in LinecapButt.
+const (
+	LinecapButt   strokeLinecap = `butt`
+	LinecapRound  strokeLinecap = `round`
+	LinecapSquare strokeLinecap = `square`
+)
+
+// Helperfunction to deduplicate code, validates a Color value.
+func validateColor(field string, color mermaidgen.Color) (err error) {
+	if color.Validate() != nil {
+		return fmt.Errorf("invalid %s %q", field, color)
+	}
+	return nil
+}
+
+// Helperfunction to escape the commas of property values like font family
+// lists, commas separate the properties in mermaid style definitions.
+func styleValue(value string) (escaped string) {
+	return strings.Replace(value, ",", `\,`, -1)
+}
+
+// Helperfunction to deduplicate code, validates the font and misc. properties
+// shared by NodeStyles and EdgeStyles.
+func validateText(weight fontWeight, family string, opacity *float64,
+	linecap strokeLinecap, more string) (err error) {
+	switch weight {
+	case "", FontWeightNormal, FontWeightBold, FontWeightBolder,
+		FontWeightLighter, FontWeight100, FontWeight200, FontWeight300,
+		FontWeight400, FontWeight500, FontWeight600, FontWeight700,
+		FontWeight800, FontWeight900:
+	default:
+		return fmt.Errorf("invalid FontWeight %q", weight)
+	}
+	// commas of font family lists are escaped, see styleValue
+	if strings.ContainsAny(family, ":;\n") {
+		return fmt.Errorf("invalid FontFamily %q", family)
+	}
+	if opacity != nil && (*opacity < 0 || *opacity > 1) {
+		return fmt.Errorf("Opacity %g out of range 0.0 - 1.0", *opacity)
+	}
+	switch linecap {
+	case "", LinecapButt, LinecapRound, LinecapSquare:
+	default:
+		return fmt.Errorf("invalid StrokeLinecap %q", linecap)
+	}
+	if strings.ContainsAny(more, ";\n") {
+		return fmt.Errorf("invalid More %q", more)
+	}
+	return nil
+}
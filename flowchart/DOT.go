@@ -0,0 +1,982 @@
+package flowchart
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
+)
+
+// regular expressions used for DOT import and export
+var (
+	dotBareID  = regexp.MustCompile(`^(?:[A-Za-z_\x{80}-\x{10ffff}][A-Za-z0-9_\x{80}-\x{10ffff}]*|-?(?:\.[0-9]+|[0-9]+(?:\.[0-9]*)?))$`)
+	dotHSV     = regexp.MustCompile(`^([0-9.]+)[,\s]+([0-9.]+)[,\s]+([0-9.]+)$`)
+	dotHTMLTag = regexp.MustCompile(`<[^>]*>`)
+	dotHTMLBr  = regexp.MustCompile(`(?i)<br\s*/?>`)
+	dotInvalid = regexp.MustCompile(`[^\pL\pN_]+`)
+)
+
+// Keywords of the DOT language, IDs matching them need to be quoted.
+var dotKeywords = map[string]bool{"node": true, "edge": true, "graph": true,
+	"digraph": true, "subgraph": true, "strict": true}
+
+// Keywords of mermaid flowcharts, IDs matching them break the parser.
+var dotReserved = map[string]bool{"end": true, "graph": true,
+	"flowchart": true, "subgraph": true, "style": true, "linkStyle": true,
+	"classDef": true, "class": true, "click": true, "direction": true,
+	"default": true}
+
+// Graphviz node shapes and the Node shapes they are mapped to.
+var dotShapes = map[string]nodeShape{
+	"box": NShapeRect, "rect": NShapeRect, "rectangle": NShapeRect,
+	"square": NShapeRect, "record": NShapeRect, "Mrecord": NShapeRoundRect,
+	"Msquare": NShapeRect, "plain": NShapeRect, "plaintext": NShapeRect,
+	"plain text": NShapeRect, "none": NShapeRect, "note": NShapeRect,
+	"tab": NShapeRect, "folder": NShapeRect, "box3d": NShapeRect,
+	"component": NShapeRect, "ellipse": NShapeRoundRect,
+	"oval": NShapeRoundRect, "egg": NShapeRoundRect, "circle": NShapeCircle,
+	"doublecircle": NShapeCircle, "point": NShapeCircle,
+	"diamond": NShapeRhombus, "Mdiamond": NShapeRhombus, "cds": NShapeFlagLeft,
+	"rarrow": NShapeFlagLeft, "larrow": NShapeFlagLeft,
+	"rpromoter": NShapeFlagLeft, "lpromoter": NShapeFlagLeft,
+}
+
+////////// DOT import //////////////////////////////////////////////////////////
+
+// A token of DOT source code.
+type dotToken struct {
+	kind byte // 'i' for IDs, 'q' quoted strings, 'h' HTML strings, 'p' others
+	text string
+	line int
+}
+
+// Default attributes and the Subgraph of a DOT graph or subgraph.
+type dotScope struct {
+	node    map[string]string
+	edge    map[string]string
+	cluster *Subgraph // nil for the top level
+}
+
+// Internal state of the DOT parser.
+type dotParser struct {
+	fc       *Flowchart
+	tokens   []dotToken
+	pos      int
+	directed bool
+	nodes    map[string]*Node             // Nodes by DOT name
+	names    []string                     // DOT names in creation order
+	attrs    map[string]map[string]string // attributes by DOT name
+	clusters map[string]*Subgraph         // Subgraphs by DOT name
+}
+
+// FromDOT creates a Flowchart from a Graphviz DOT graph as emitted by tools
+// like go mod graph or terraform graph. The mapping is lossy:
+//
+// Nodes get their DOT name as ID if it is a valid mermaid ID, otherwise a
+// sanitized ID and the name as text. The label attribute becomes the text,
+// HTML labels are reduced to plain text. Shapes are mapped to the closest
+// Node shape, Nodes without or with unknown shapes get NShapeRect, ellipses
+// get NShapeRoundRect. Subgraphs named cluster* become Subgraphs with the
+// "cluster" prefix removed from the ID and the label as Title, other subgraphs
+// only group statements. rankdir becomes Direction.
+//
+// The attributes color, fillcolor (if style is filled), fontcolor, penwidth,
+// fontsize and fontname are mapped to inline NodeStyles and EdgeStyles. Edge
+// styles dashed and dotted yield dotted Edges, bold yields thick Edges, Edges
+// of undirected graphs and with dir=none or arrowhead=none have no arrowhead.
+// URL, href and tooltip become click links. Ports, color lists, HTML tables,
+// graph labels and all other attributes are ignored.
+func FromDOT(r io.Reader) (newFlowchart *Flowchart, err error) {
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("FromDOT: %s", err)
+	}
+	p := &dotParser{fc: NewFlowchart(), nodes: make(map[string]*Node),
+		attrs:    make(map[string]map[string]string),
+		clusters: make(map[string]*Subgraph)}
+	if p.tokens, err = dotTokens(string(src)); err != nil {
+		return nil, err
+	}
+	if err = p.graph(); err != nil {
+		return nil, err
+	}
+	for _, name := range p.names {
+		p.applyNodeAttributes(p.nodes[name], name, p.attrs[name])
+	}
+	return p.fc, nil
+}
+
+// Helperfunction to split DOT source code into tokens.
+func dotTokens(src string) (tokens []dotToken, err error) {
+	line := 1
+	errorf := func(format string, args ...interface{}) error {
+		return fmt.Errorf("FromDOT: line %d: %s", line,
+			fmt.Sprintf(format, args...))
+	}
+	lineStart := true
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			lineStart = true
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+			continue
+		case c == '#' && lineStart, strings.HasPrefix(src[i:], "//"):
+			// preprocessor output and line comments
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return nil, errorf("comment not closed")
+			}
+			line += strings.Count(src[i:i+2+end], "\n")
+			i += end + 4
+			continue
+		}
+		lineStart = false
+		switch {
+		case c == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(src) && src[j] != '"'; j++ {
+				switch {
+				case src[j] == '\\' && j+1 < len(src) && src[j+1] == '"':
+					b.WriteByte('"')
+					j++
+				case src[j] == '\\' && j+1 < len(src) && src[j+1] == '\n':
+					// line continuation
+					line++
+					j++
+				default:
+					if src[j] == '\n' {
+						line++
+					}
+					b.WriteByte(src[j])
+				}
+			}
+			if j == len(src) {
+				return nil, errorf("string not closed")
+			}
+			text := b.String()
+			// "a" + "b" concatenation
+			if n := len(tokens); n > 1 && tokens[n-1].text == "+" &&
+				tokens[n-1].kind == 'p' && tokens[n-2].kind == 'q' {
+				tokens = tokens[:n-1]
+				tokens[n-2].text += text
+			} else {
+				tokens = append(tokens, dotToken{'q', text, line})
+			}
+			i = j + 1
+		case c == '<':
+			depth, j := 0, i
+			for ; j < len(src); j++ {
+				if src[j] == '<' {
+					depth++
+				} else if src[j] == '>' {
+					depth--
+					if depth == 0 {
+						break
+					}
+				} else if src[j] == '\n' {
+					line++
+				}
+			}
+			if j == len(src) {
+				return nil, errorf("HTML string not closed")
+			}
+			tokens = append(tokens, dotToken{'h', src[i+1 : j], line})
+			i = j + 1
+		case strings.HasPrefix(src[i:], "->"), strings.HasPrefix(src[i:], "--"):
+			tokens = append(tokens, dotToken{'p', src[i : i+2], line})
+			i += 2
+		case strings.IndexByte("{}[]=;,:+", c) >= 0:
+			tokens = append(tokens, dotToken{'p', string(c), line})
+			i++
+		default:
+			j := i
+			if c == '-' {
+				j++
+			}
+			for j < len(src) && (src[j] == '_' || src[j] == '.' ||
+				src[j] >= '0' && src[j] <= '9' || src[j] >= 'a' && src[j] <= 'z' ||
+				src[j] >= 'A' && src[j] <= 'Z' || src[j] >= 0x80) {
+				j++
+			}
+			if j == i || j == i+1 && c == '-' {
+				return nil, errorf("unexpected character %q", c)
+			}
+			tokens = append(tokens, dotToken{'i', src[i:j], line})
+			i = j
+		}
+	}
+	return
+}
+
+// Helperfunction to prefix errors with the line number of the current token.
+func (p *dotParser) errorf(format string, args ...interface{}) (err error) {
+	line := 0
+	if p.pos < len(p.tokens) {
+		line = p.tokens[p.pos].line
+	} else if len(p.tokens) > 0 {
+		line = p.tokens[len(p.tokens)-1].line
+	}
+	return fmt.Errorf("FromDOT: line %d: %s", line,
+		fmt.Sprintf(format, args...))
+}
+
+// Helperfunction to check whether the current token is the given punctuation.
+func (p *dotParser) is(punctuation string) (is bool) {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == 'p' &&
+		p.tokens[p.pos].text == punctuation
+}
+
+// Helperfunction to check whether the current token is the given keyword.
+func (p *dotParser) isKeyword(keyword string) (is bool) {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == 'i' &&
+		strings.ToLower(p.tokens[p.pos].text) == keyword
+}
+
+// Helperfunction to consume the given punctuation.
+func (p *dotParser) expect(punctuation string) (err error) {
+	if !p.is(punctuation) {
+		return p.errorf("expected %q", punctuation)
+	}
+	p.pos++
+	return nil
+}
+
+// Helperfunction to consume an ID, which may be quoted.
+func (p *dotParser) id() (id string, err error) {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind == 'p' {
+		return "", p.errorf("expected ID")
+	}
+	t := p.tokens[p.pos]
+	p.pos++
+	if t.kind == 'h' {
+		return "<" + t.text + ">", nil
+	}
+	return t.text, nil
+}
+
+// Helperfunction to parse the whole graph.
+func (p *dotParser) graph() (err error) {
+	if p.isKeyword("strict") {
+		p.pos++
+	}
+	switch {
+	case p.isKeyword("digraph"):
+		p.directed = true
+	case p.isKeyword("graph"):
+	default:
+		return p.errorf("expected graph or digraph")
+	}
+	p.pos++
+	if !p.is("{") {
+		if _, err = p.id(); err != nil {
+			return err
+		}
+	}
+	if err = p.expect("{"); err != nil {
+		return err
+	}
+	scope := &dotScope{node: map[string]string{}, edge: map[string]string{}}
+	if _, err = p.statements(scope); err != nil {
+		return err
+	}
+	if err = p.expect("}"); err != nil {
+		return err
+	}
+	if p.pos < len(p.tokens) {
+		return p.errorf("unexpected content after the graph")
+	}
+	return nil
+}
+
+// Helperfunction to parse statements up to the closing brace, it returns the
+// Nodes used in the statements.
+func (p *dotParser) statements(scope *dotScope) (nodes []*Node, err error) {
+	for !p.is("}") {
+		if p.pos >= len(p.tokens) {
+			return nil, p.errorf("expected \"}\"")
+		}
+		used, err := p.statement(scope)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, used...)
+		if p.is(";") {
+			p.pos++
+		}
+	}
+	return nodes, nil
+}
+
+// Helperfunction to parse a single statement.
+func (p *dotParser) statement(scope *dotScope) (nodes []*Node, err error) {
+	for _, kind := range []string{"graph", "node", "edge"} {
+		if p.isKeyword(kind) && p.pos+1 < len(p.tokens) &&
+			p.tokens[p.pos+1].kind == 'p' && p.tokens[p.pos+1].text == "[" {
+			p.pos++
+			attrs, err := p.attributes()
+			if err != nil {
+				return nil, err
+			}
+			switch kind {
+			case "graph":
+				p.applyGraphAttributes(scope, attrs)
+			case "node":
+				mergeAttributes(scope.node, attrs)
+			case "edge":
+				mergeAttributes(scope.edge, attrs)
+			}
+			return nil, nil
+		}
+	}
+	if p.pos+1 < len(p.tokens) && p.tokens[p.pos].kind != 'p' &&
+		p.tokens[p.pos+1].kind == 'p' && p.tokens[p.pos+1].text == "=" {
+		// ID = ID graph attribute
+		key, _ := p.id()
+		p.pos++
+		value, err := p.id()
+		if err != nil {
+			return nil, err
+		}
+		p.applyGraphAttributes(scope, map[string]string{key: value})
+		return nil, nil
+	}
+	nodes, isNode, err := p.operand(scope)
+	if err != nil {
+		return nil, err
+	}
+	if p.is("->") || p.is("--") {
+		return p.edges(scope, nodes)
+	}
+	if isNode {
+		attrs, err := p.attributes()
+		if err != nil {
+			return nil, err
+		}
+		mergeAttributes(p.attrs[p.nameOf(nodes[0])], attrs)
+	}
+	return nodes, nil
+}
+
+// Helperfunction to parse a Node ID (with optional port) or a subgraph.
+func (p *dotParser) operand(scope *dotScope) (nodes []*Node, isNode bool,
+	err error) {
+	if p.is("{") || p.isKeyword("subgraph") {
+		nodes, err = p.subgraph(scope)
+		return nodes, false, err
+	}
+	name, err := p.id()
+	if err != nil {
+		return nil, false, err
+	}
+	if p.is(":") {
+		// ports are ignored
+		p.pos++
+		if _, err = p.id(); err != nil {
+			return nil, false, err
+		}
+		if p.is(":") {
+			p.pos++
+			if _, err = p.id(); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+	return []*Node{p.node(scope, name)}, true, nil
+}
+
+// Helperfunction to parse a subgraph, clusters become Subgraphs.
+func (p *dotParser) subgraph(scope *dotScope) (nodes []*Node, err error) {
+	name := ""
+	if p.isKeyword("subgraph") {
+		p.pos++
+		if !p.is("{") {
+			if name, err = p.id(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	inner := &dotScope{node: copyAttributes(scope.node),
+		edge: copyAttributes(scope.edge), cluster: scope.cluster}
+	if strings.HasPrefix(name, "cluster") {
+		sg := p.clusters[name]
+		if sg == nil {
+			id := strings.TrimLeft(strings.TrimPrefix(name, "cluster"), "_-")
+			if id == "" {
+				id = name
+			}
+			id = p.uniqueID(id, func(id string) bool {
+				return p.fc.GetSubgraph(id) != nil
+			})
+			if scope.cluster != nil {
+				sg = scope.cluster.AddSubgraph(id)
+			} else {
+				sg = p.fc.AddSubgraph(id)
+			}
+			p.clusters[name] = sg
+		}
+		inner.cluster = sg
+	}
+	if err = p.expect("{"); err != nil {
+		return nil, err
+	}
+	if nodes, err = p.statements(inner); err != nil {
+		return nil, err
+	}
+	return nodes, p.expect("}")
+}
+
+// Helperfunction to parse the rest of an edge statement, starting with the
+// edge operator after the first operand.
+func (p *dotParser) edges(scope *dotScope, first []*Node) (nodes []*Node,
+	err error) {
+	chain := [][]*Node{first}
+	nodes = append(nodes, first...)
+	for p.is("->") || p.is("--") {
+		p.pos++
+		operand, _, err := p.operand(scope)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, operand)
+		nodes = append(nodes, operand...)
+	}
+	attrs, err := p.attributes()
+	if err != nil {
+		return nil, err
+	}
+	merged := copyAttributes(scope.edge)
+	mergeAttributes(merged, attrs)
+	for i := 1; i < len(chain); i++ {
+		for _, from := range chain[i-1] {
+			for _, to := range chain[i] {
+				p.addEdge(from, to, merged)
+			}
+		}
+	}
+	return nodes, nil
+}
+
+// Helperfunction to parse optional attribute lists, [a=b, c=d][e=f].
+func (p *dotParser) attributes() (attrs map[string]string, err error) {
+	attrs = make(map[string]string)
+	for p.is("[") {
+		p.pos++
+		for !p.is("]") {
+			key, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+			value := "true"
+			if p.is("=") {
+				p.pos++
+				if value, err = p.id(); err != nil {
+					return nil, err
+				}
+			}
+			attrs[key] = value
+			if p.is(",") || p.is(";") {
+				p.pos++
+			}
+		}
+		p.pos++
+	}
+	return attrs, nil
+}
+
+// Helperfunction to merge attributes, later ones win.
+func mergeAttributes(target, attrs map[string]string) {
+	for k, v := range attrs {
+		target[k] = v
+	}
+}
+
+// Helperfunction to copy attributes.
+func copyAttributes(attrs map[string]string) (copied map[string]string) {
+	copied = make(map[string]string, len(attrs))
+	mergeAttributes(copied, attrs)
+	return
+}
+
+// Helperfunction to find the DOT name of a Node.
+func (p *dotParser) nameOf(n *Node) (name string) {
+	for _, name := range p.names {
+		if p.nodes[name] == n {
+			return name
+		}
+	}
+	return ""
+}
+
+// Helperfunction to make a valid and unused ID from a DOT name.
+func (p *dotParser) uniqueID(name string, used func(string) bool) (id string) {
+	id = name
+	if m := parseID.FindString(name); m != name {
+		id = strings.Trim(dotInvalid.ReplaceAllString(name, "_"), "_")
+		if id == "" {
+			id = "n"
+		}
+	}
+	if dotReserved[id] {
+		id += "_"
+	}
+	for i, base := 2, id; used(id); i++ {
+		id = fmt.Sprintf("%s_%d", base, i)
+	}
+	return id
+}
+
+// Helperfunction to look up or create the Node for a DOT name. Nodes used in
+// a cluster are moved there if they are still at the top level.
+func (p *dotParser) node(scope *dotScope, name string) (n *Node) {
+	if n = p.nodes[name]; n != nil {
+		if scope.cluster != nil && n.subgraph == nil {
+			p.fc.MoveNode(n.id, scope.cluster)
+		}
+		return n
+	}
+	id := p.uniqueID(name, func(id string) bool {
+		return p.fc.GetNode(id) != nil
+	})
+	if scope.cluster != nil {
+		n = scope.cluster.AddNode(id)
+	} else {
+		n = p.fc.AddNode(id)
+	}
+	p.nodes[name] = n
+	p.names = append(p.names, name)
+	p.attrs[name] = copyAttributes(scope.node)
+	return n
+}
+
+// Helperfunction to apply graph attributes to the Flowchart or cluster.
+func (p *dotParser) applyGraphAttributes(scope *dotScope,
+	attrs map[string]string) {
+	if sg := scope.cluster; sg != nil {
+		if label, ok := attrs["label"]; ok {
+			sg.Title = strings.Join(dotLines(label, ""), " ")
+		}
+		var fill, stroke string
+		styles := dotStyles(attrs["style"])
+		if styles["filled"] {
+			fill = attrs["fillcolor"]
+			if fill == "" {
+				fill = attrs["color"]
+			}
+		}
+		if c, ok := attrs["pencolor"]; ok {
+			stroke = c
+		} else if c, ok := attrs["color"]; ok {
+			stroke = c
+		}
+		if c, ok := dotColor(fill); ok {
+			sg.InlineStyle().Fill = c
+		}
+		if c, ok := dotColor(stroke); ok {
+			sg.InlineStyle().Stroke = c
+		}
+		return
+	}
+	switch strings.ToUpper(attrs["rankdir"]) {
+	case "LR":
+		p.fc.Direction = DirectionLeftRight
+	case "RL":
+		p.fc.Direction = DirectionRightLeft
+	case "BT":
+		p.fc.Direction = DirectionBottomUp
+	case "TB":
+		p.fc.Direction = DirectionTopDown
+	}
+}
+
+// Helperfunction to apply the collected attributes to a Node.
+func (p *dotParser) applyNodeAttributes(n *Node, name string,
+	attrs map[string]string) {
+	if label, ok := attrs["label"]; ok {
+		n.Text = dotLines(label, name)
+	} else if n.id != name {
+		n.Text = []string{name}
+	}
+	styles := dotStyles(attrs["style"])
+	if shape, ok := dotShapes[attrs["shape"]]; ok {
+		n.Shape = shape
+	}
+	if styles["rounded"] && n.Shape == NShapeRect {
+		n.Shape = NShapeRoundRect
+	}
+	fill := ""
+	if styles["filled"] {
+		fill = attrs["fillcolor"]
+		if fill == "" {
+			fill = attrs["color"]
+		}
+		if fill == "" {
+			fill = "lightgrey"
+		}
+	}
+	if c, ok := dotColor(fill); ok {
+		n.InlineStyle().Fill = c
+	}
+	if c, ok := dotColor(attrs["color"]); ok {
+		n.InlineStyle().Stroke = c
+	}
+	if c, ok := dotColor(attrs["fontcolor"]); ok {
+		n.InlineStyle().Color = c
+	}
+	if w, ok := dotSize(attrs["penwidth"]); ok {
+		n.InlineStyle().StrokeWidth = w
+	}
+	if styles["bold"] {
+		n.InlineStyle().StrokeWidth = 2
+	}
+	if styles["dashed"] || styles["dotted"] {
+		n.InlineStyle().StrokeDash = 5
+	}
+	if s, ok := dotSize(attrs["fontsize"]); ok {
+		n.InlineStyle().FontSize = s
+	}
+	if f := attrs["fontname"]; f != "" {
+		n.InlineStyle().FontFamily = f
+	}
+	if link, ok := attrs["URL"]; ok {
+		n.Link = link
+	} else if link, ok := attrs["href"]; ok {
+		n.Link = link
+	}
+	if n.Link != "" {
+		n.LinkText = attrs["tooltip"]
+	}
+}
+
+// Helperfunction to create an Edge with the given attributes.
+func (p *dotParser) addEdge(from, to *Node, attrs map[string]string) {
+	dir := attrs["dir"]
+	if dir == "back" {
+		from, to = to, from
+	}
+	e := p.fc.AddEdge(from, to)
+	if label, ok := attrs["label"]; ok {
+		e.Text = dotLines(label, "")
+	}
+	styles := dotStyles(attrs["style"])
+	arrow := p.directed && dir != "none" || !p.directed &&
+		(dir == "forward" || dir == "back" || dir == "both")
+	if attrs["arrowhead"] == "none" && dir != "back" {
+		arrow = false
+	}
+	switch {
+	case styles["dashed"] || styles["dotted"]:
+		e.Shape = EShapeDottedLine
+		if arrow {
+			e.Shape = EShapeDottedArrow
+		}
+	case styles["bold"]:
+		e.Shape = EShapeThickLine
+		if arrow {
+			e.Shape = EShapeThickArrow
+		}
+	case !arrow:
+		e.Shape = EShapeLine
+	}
+	style := func() *EdgeStyle {
+		if e.Style == nil {
+			e.Style = p.fc.EdgeStyle(fmt.Sprintf("dot%d", e.ID()))
+		}
+		return e.Style
+	}
+	if c, ok := dotColor(attrs["color"]); ok {
+		style().Stroke = c
+	}
+	if c, ok := dotColor(attrs["fontcolor"]); ok {
+		style().Color = c
+	}
+	if w, ok := dotSize(attrs["penwidth"]); ok {
+		style().StrokeWidth = w
+	}
+	if s, ok := dotSize(attrs["fontsize"]); ok {
+		style().FontSize = s
+	}
+	if e.Style != nil && (styles["dashed"] || styles["dotted"]) {
+		// EdgeStyles override the shape
+		e.Style.StrokeDash = 5
+	}
+}
+
+// Helperfunction to split a DOT style list, e.g. "filled,rounded".
+func dotStyles(style string) (styles map[string]bool) {
+	styles = make(map[string]bool)
+	for _, s := range strings.Split(style, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			styles[s] = true
+		}
+	}
+	return
+}
+
+// Helperfunction to convert a DOT label to text lines. Escaped line breaks
+// split lines, \N is replaced by the Node's name, HTML labels are reduced to
+// their text.
+func dotLines(label, name string) (lines []string) {
+	if strings.HasPrefix(label, "<") && strings.HasSuffix(label, ">") {
+		label = dotHTMLBr.ReplaceAllString(label[1:len(label)-1], "\n")
+		label = dotHTMLTag.ReplaceAllString(label, "")
+		for _, line := range strings.Split(label, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		return
+	}
+	var b strings.Builder
+	for i := 0; i < len(label); i++ {
+		if label[i] != '\\' || i+1 == len(label) {
+			b.WriteByte(label[i])
+			continue
+		}
+		i++
+		switch label[i] {
+		case 'n', 'l', 'r':
+			lines = append(lines, b.String())
+			b.Reset()
+		case 'N':
+			b.WriteString(name)
+		default:
+			b.WriteByte(label[i])
+		}
+	}
+	if b.Len() > 0 || len(lines) == 0 {
+		lines = append(lines, b.String())
+	}
+	return
+}
+
+// Helperfunction to convert a DOT color to a Color, color lists and schemes
+// are reduced to the first color.
+func dotColor(value string) (c mermaidgen.Color, ok bool) {
+	value = strings.TrimSpace(strings.SplitN(value, ":", 2)[0])
+	value = strings.SplitN(value, ";", 2)[0]
+	if i := strings.LastIndex(value, "/"); i >= 0 {
+		value = value[i+1:]
+	}
+	if value == "" {
+		return "", false
+	}
+	if m := dotHSV.FindStringSubmatch(value); m != nil {
+		h, _ := strconv.ParseFloat(m[1], 64)
+		s, _ := strconv.ParseFloat(m[2], 64)
+		v, _ := strconv.ParseFloat(m[3], 64)
+		l := v * (1 - s/2)
+		sl := 0.0
+		if l > 0 && l < 1 {
+			sl = (v - l) / math.Min(l, 1-l)
+		}
+		return mermaidgen.HSL(h*360, sl, l), true
+	}
+	c = mermaidgen.Color(value)
+	return c, c.Validate() == nil
+}
+
+// Helperfunction to convert DOT sizes like penwidth or fontsize to pixels.
+func dotSize(value string) (pixels uint8, ok bool) {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil || v < 0 || v > 255 {
+		return 0, false
+	}
+	return uint8(math.Round(v)), true
+}
+
+////////// DOT export //////////////////////////////////////////////////////////
+
+// DOT writes the Flowchart as Graphviz DOT digraph to w, e.g. to render it
+// with Graphviz or to process it with other graph tools. The mapping is lossy:
+//
+// Subgraphs become clusters named cluster_<ID>, Direction becomes rankdir.
+// Node shapes are mapped to box, rounded box, circle, diamond and cds. The
+// merged CSS of DefaultNodeStyle, NodeStyles and inline styles is converted to
+// the attributes fillcolor, color, fontcolor, penwidth, fontsize, fontname and
+// dashed styles, all other CSS as well as the class names are lost. The same
+// applies to EdgeStyles. Dotted Edges become dotted, thick Edges bold, lines
+// get arrowhead=none. Click links become URL and tooltip. The Config and
+// ClassMode are not exported.
+func (fc *Flowchart) DOT(w io.Writer) (err error) {
+	var b bytes.Buffer
+	direction := fc.Direction
+	if direction == "" {
+		direction = DirectionTopDown
+	}
+	fmt.Fprintf(&b, "digraph {\n\trankdir=%s\n\tnode [shape=box]\n", direction)
+	fc.dotItems(&b, fc.items, "\t")
+	for _, e := range fc.edges {
+		fc.dotEdge(&b, e)
+	}
+	b.WriteString("}\n")
+	_, err = w.Write(b.Bytes())
+	return
+}
+
+// Helperfunction to write Nodes and Subgraphs recursively.
+func (fc *Flowchart) dotItems(b *bytes.Buffer, items []graphItem,
+	indent string) {
+	for _, item := range items {
+		switch v := item.(type) {
+		case *Node:
+			fc.dotNode(b, v, indent)
+		case *Subgraph:
+			fmt.Fprintf(b, "%ssubgraph %s {\n", indent,
+				dotQuote("cluster_"+v.id))
+			fmt.Fprintf(b, "%s\tlabel=%s\n", indent, dotQuote(subgraphTitle(v)))
+			style := fc.subgraphStyle(v)
+			if fill := dotColorOf(property(style.shape, "fill")); fill != "" {
+				fmt.Fprintf(b, "%s\tstyle=filled\n%s\tfillcolor=%s\n", indent,
+					indent, dotQuote(fill))
+			}
+			if stroke := dotColorOf(property(style.shape, "stroke")); stroke != "" {
+				fmt.Fprintf(b, "%s\tcolor=%s\n", indent, dotQuote(stroke))
+			}
+			if color := dotColorOf(property(style.text, "fill")); color != "" {
+				fmt.Fprintf(b, "%s\tfontcolor=%s\n", indent, dotQuote(color))
+			}
+			fc.dotItems(b, v.items, indent+"\t")
+			fmt.Fprintf(b, "%s}\n", indent)
+		}
+	}
+}
+
+// Helperfunction to write a node statement.
+func (fc *Flowchart) dotNode(b *bytes.Buffer, n *Node, indent string) {
+	var attrs [][2]string
+	if len(n.Text) > 0 {
+		attrs = append(attrs, [2]string{"label", strings.Join(n.Text, "\n")})
+	}
+	var styles []string
+	switch n.Shape {
+	case NShapeRoundRect:
+		styles = append(styles, "rounded")
+	case NShapeCircle:
+		attrs = append(attrs, [2]string{"shape", "circle"})
+	case NShapeRhombus:
+		attrs = append(attrs, [2]string{"shape", "diamond"})
+	case NShapeFlagLeft:
+		attrs = append(attrs, [2]string{"shape", "cds"})
+	}
+	style := fc.nodeStyle(n)
+	attrs = append(attrs, dotStyleAttributes(style, styles, true)...)
+	if n.Link != "" {
+		attrs = append(attrs, [2]string{"URL", n.Link})
+		if n.LinkText != "" {
+			attrs = append(attrs, [2]string{"tooltip", n.LinkText})
+		}
+	}
+	fmt.Fprintf(b, "%s%s%s\n", indent, dotQuote(n.id), dotAttributes(attrs))
+}
+
+// Helperfunction to write an edge statement.
+func (fc *Flowchart) dotEdge(b *bytes.Buffer, e *Edge) {
+	var attrs [][2]string
+	if len(e.Text) > 0 {
+		attrs = append(attrs, [2]string{"label", strings.Join(e.Text, "\n")})
+	}
+	var styles []string
+	switch e.Shape {
+	case EShapeDottedArrow, EShapeDottedLine:
+		styles = append(styles, "dotted")
+	case EShapeThickArrow, EShapeThickLine:
+		styles = append(styles, "bold")
+	}
+	attrs = append(attrs, dotStyleAttributes(fc.edgeStyle(e), styles, false)...)
+	switch e.Shape {
+	case EShapeLine, EShapeDottedLine, EShapeThickLine:
+		attrs = append(attrs, [2]string{"arrowhead", "none"})
+	}
+	fmt.Fprintf(b, "\t%s -> %s%s\n", dotQuote(e.From.id), dotQuote(e.To.id),
+		dotAttributes(attrs))
+}
+
+// Helperfunction to convert merged CSS to DOT attributes, styles are the DOT
+// styles set by shapes.
+func dotStyleAttributes(style svgStyle, styles []string,
+	node bool) (attrs [][2]string) {
+	fill := dotColorOf(property(style.shape, "fill"))
+	if node && fill != "" {
+		styles = append([]string{"filled"}, styles...)
+	}
+	if dash := property(style.shape, "stroke-dasharray"); dash != "" &&
+		dash != "0" && dash != "0px" && len(styles) == 0 {
+		styles = append(styles, "dashed")
+	}
+	if len(styles) > 0 {
+		attrs = append(attrs, [2]string{"style", strings.Join(styles, ",")})
+	}
+	if node && fill != "" {
+		attrs = append(attrs, [2]string{"fillcolor", fill})
+	}
+	if stroke := dotColorOf(property(style.shape, "stroke")); stroke != "" {
+		attrs = append(attrs, [2]string{"color", stroke})
+	}
+	if color := dotColorOf(property(style.text, "fill")); color != "" {
+		attrs = append(attrs, [2]string{"fontcolor", color})
+	}
+	if width := property(style.shape, "stroke-width"); width != "" {
+		attrs = append(attrs, [2]string{"penwidth", strings.TrimSuffix(width,
+			"px")})
+	}
+	if size := property(style.text, "font-size"); size != "" {
+		attrs = append(attrs, [2]string{"fontsize", strings.TrimSuffix(size,
+			"px")})
+	}
+	if family := property(style.text, "font-family"); family != "" {
+		attrs = append(attrs, [2]string{"fontname", family})
+	}
+	return
+}
+
+// Helperfunction to convert a CSS color to the hex notation Graphviz
+// understands, empty if the color is invalid.
+func dotColorOf(value string) (color string) {
+	r, g, b, a, err := mermaidgen.Color(value).Components()
+	if err != nil {
+		return ""
+	}
+	if a < 1 {
+		return fmt.Sprintf("#%02x%02x%02x%02x", r, g, b,
+			uint8(math.Round(a*255)))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// Helperfunction to render an attribute list, empty if there are none.
+func dotAttributes(attrs [][2]string) (list string) {
+	if len(attrs) == 0 {
+		return ""
+	}
+	items := make([]string, len(attrs))
+	for i, kv := range attrs {
+		items[i] = kv[0] + "=" + dotQuote(kv[1])
+	}
+	return " [" + strings.Join(items, " ") + "]"
+}
+
+// Helperfunction to quote DOT IDs where necessary.
+func dotQuote(id string) (quoted string) {
+	if dotBareID.MatchString(id) && !dotKeywords[strings.ToLower(id)] {
+		return id
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(id) + `"`
+}
@@ -0,0 +1,204 @@
+package flowchart_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen/flowchart"
+)
+
+// Importing Graphviz DOT graphs, e.g. from go mod graph or terraform graph
+func ExampleFromDOT() {
+	f, err := flowchart.FromDOT(strings.NewReader(`
+		// build pipeline
+		digraph pipeline {
+			rankdir=LR
+			node [shape=box, style=rounded]
+			subgraph cluster_ci {
+				label="Continuous Integration"
+				lint; test [label="unit\ntests"]
+			}
+			"github.com/x/y" [shape=cds, style=filled, fillcolor="#ffcc00"]
+			lint -> test -> deploy [color=red]
+			deploy -> "github.com/x/y" [style=dashed, label=publish]
+		}`))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Print(f)
+	//Output:
+	//graph LR
+	//subgraph ci ["Continuous Integration"]
+	//lint("lint")
+	//test("unit<br/>tests")
+	//end
+	//github_com_x_y>"github.com/x/y"]
+	//style github_com_x_y fill:#ffcc00
+	//deploy("deploy")
+	//lint --> test
+	//linkStyle 0 stroke:red
+	//test --> deploy
+	//linkStyle 1 stroke:red
+	//deploy -.->|"publish"| github_com_x_y
+}
+
+// Exporting a Flowchart as Graphviz DOT
+func ExampleFlowchart_DOT() {
+	f := flowchart.NewFlowchart()
+	f.Direction = flowchart.DirectionLeftRight
+	sg := f.AddSubgraph("build")
+	sg.Title = "Build stage"
+	n1 := sg.AddNode("compile")
+	n2 := f.AddNode("ship")
+	n2.Shape = flowchart.NShapeRhombus
+	n2.Text = []string{"ship it?"}
+	n2.InlineStyle().Fill = "orange"
+	e := f.AddEdge(n1, n2)
+	e.Shape = flowchart.EShapeDottedArrow
+	e.Text = []string{"artifacts"}
+	f.DOT(os.Stdout)
+	//Output:
+	//digraph {
+	// 	rankdir=LR
+	// 	node [shape=box]
+	// 	subgraph cluster_build {
+	// 		label="Build stage"
+	// 		compile
+	// 	}
+	// 	ship [label="ship it?" shape=diamond style=filled fillcolor="#ffa500"]
+	// 	compile -> ship [label=artifacts style=dotted]
+	//}
+}
+
+func TestFromDOT_roundTrip(t *testing.T) {
+	f, err := flowchart.Parse(`flowchart RL
+	    subgraph outer [Outer]
+	        a(Round)
+	        subgraph inner [Inner]
+	            b((Circle))
+	        end
+	    end
+	    c{Decision} ==> a
+	    a -.- b
+	    b --- c
+	    click c "http://www.example.com" "Example"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dot bytes.Buffer
+	if err = f.DOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	g, err := flowchart.FromDOT(&dot)
+	if err != nil {
+		t.Fatalf("%s\n%s", err, dot.String())
+	}
+	if f.String() != g.String() {
+		t.Errorf("round trip changed the Flowchart\n%s\n%s", f, g)
+	}
+}
+
+func TestFromDOT(t *testing.T) {
+	f, err := flowchart.FromDOT(strings.NewReader(`strict graph {
+		# preprocessor line
+		a -- {b c} /* both */
+		subgraph cluster0 { b:n; node [shape=circle]; d }
+		e [label=<<b>bold</b><br/>text>, color="0.0 1.0 1.0"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `graph TB
+a["a"]
+c["c"]
+subgraph 0 ["0"]
+b["b"]
+d(("d"))
+end
+e["bold<br/>text"]
+style e stroke:#ff0000
+a --- b
+a --- c
+`
+	if got := f.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	for _, src := range []string{
+		``,
+		`digraph {`,
+		`digraph { a -> }`,
+		`digraph { "a }`,
+		`tree { a }`,
+		`digraph { a } b`,
+		`digraph { a [label=x }`,
+	} {
+		if _, err := flowchart.FromDOT(strings.NewReader(src)); err == nil {
+			t.Errorf("%q: expected an error", src)
+		}
+	}
+}
+
+func TestFromDOT_terraform(t *testing.T) {
+	// shaped like the output of terraform graph
+	f, err := flowchart.FromDOT(strings.NewReader(`digraph {
+	compound = "true"
+	newrank = "true"
+	subgraph "root" {
+		"[root] aws_instance.web (expand)" [label = "aws_instance.web", shape = "box"]
+		"[root] provider[\"registry.terraform.io/hashicorp/aws\"]" [label = "provider[\"registry.terraform.io/hashicorp/aws\"]", shape = "diamond"]
+		"[root] aws_instance.web (expand)" -> "[root] provider[\"registry.terraform.io/hashicorp/aws\"]"
+		"end" -> "graph"
+	}
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `graph TB
+root_aws_instance_web_expand["aws_instance.web"]
+root_provider_registry_terraform_io_hashicorp_aws{"provider[#quot;registry.terraform.io/hashicorp/aws#quot;]"}
+end_["end"]
+graph_["graph"]
+root_aws_instance_web_expand --> root_provider_registry_terraform_io_hashicorp_aws
+end_ --> graph_
+`
+	if got := f.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFromDOT_quotes(t *testing.T) {
+	f, err := flowchart.FromDOT(strings.NewReader(
+		`digraph { a [label="say \"hi\""]; a -> b [label="\"x\""] }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the model keeps the raw text, only mermaid code is escaped
+	if text := f.GetNode("a").Text; len(text) != 1 || text[0] != `say "hi"` {
+		t.Errorf("unexpected Text %q", text)
+	}
+	if !strings.Contains(f.String(), `a["say #quot;hi#quot;"]`) {
+		t.Errorf("quotes not escaped\n%s", f)
+	}
+	var dot bytes.Buffer
+	if err = f.DOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(dot.String(), `label="say \"hi\""`) {
+		t.Errorf("label changed by the round trip\n%s", dot.String())
+	}
+	g, err := flowchart.FromDOT(&dot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.String() != g.String() {
+		t.Errorf("round trip changed the Flowchart\n%s\n%s", f, g)
+	}
+	if p, err := flowchart.Parse(f.String()); err != nil ||
+		p.GetNode("a").Text[0] != `say "hi"` || p.String() != f.String() {
+		t.Errorf("parsing changed the quotes: %v\n%s", err, p)
+	}
+}
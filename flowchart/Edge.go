@@ -25,12 +25,13 @@ const (
 // instances directly. Already defined IDs (indices) can be looked up via
 // Flowchart's GetEdge method or iterated over via its ListEdges method.
 type Edge struct {
-	id    int
-	From  *Node      // Pointer to the Node where the Edge starts.
-	To    *Node      // Pointer to the Node where the Edge ends.
-	Shape edgeShape  // The shape of this Edge.
-	Text  []string   // Optional text lines to be added along the Edge.
-	Style *EdgeStyle // Optional CSS style.
+	id      int
+	From    *Node      // Pointer to the Node where the Edge starts.
+	To      *Node      // Pointer to the Node where the Edge ends.
+	Shape   edgeShape  // The shape of this Edge.
+	Text    []string   // Optional text lines to be added along the Edge.
+	Style   *EdgeStyle // Optional CSS style.
+	Comment string     // Optional %% comment rendered above the Edge.
 }
 
 // ID provides access to the Edge's readonly field id.
@@ -42,16 +43,31 @@ func (e *Edge) ID() (id int) {
 
 // String renders this graph element to an edge definition line.
 // If Style member is set an additional linkStyle line will be created.
+// If Comment member is set it is rendered as %% lines above.
 func (e *Edge) String() (renderedElement string) {
-	line := string(e.Shape)
+	return renderString("", e.writeGraph)
+}
+
+// Helperfunction for String and Flowchart's WriteTo, renders the Edge to w.
+func (e *Edge) writeGraph(w *graphWriter) {
+	w.comment(e.Comment)
+	w.startLine()
+	w.WriteString(e.From.id + " " + string(e.Shape))
 	if len(e.Text) > 0 {
-		line += fmt.Sprintf(`|"%s"|`, strings.Join(e.Text, "<br/>"))
+		fmt.Fprintf(w, `|"%s"|`, escapeQuotes(strings.Join(e.Text, "<br/>")))
+	}
+	w.WriteString(" " + e.To.id + "\n")
+	if !w.grouped {
+		e.writeStyle(w)
 	}
-	text := fmt.Sprintf("%s %s %s\n", e.From.id, line, e.To.id)
+}
+
+// Helperfunction to render the linkStyle line of the Edge, if any.
+func (e *Edge) writeStyle(w *graphWriter) {
 	if e.Style != nil {
-		text += fmt.Sprintf(e.Style.String(), strconv.Itoa(e.id))
+		w.startLine()
+		fmt.Fprintf(w, e.Style.String(), strconv.Itoa(e.id))
 	}
-	return text
 }
 
 // AddLines adds one or more lines of text to the Text member.
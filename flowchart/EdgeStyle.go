@@ -3,6 +3,8 @@ package flowchart
 import (
 	"fmt"
 	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
 )
 
 type edgeInterpolation string
@@ -23,11 +25,17 @@ const (
 // create instances directly.
 type EdgeStyle struct {
 	id            string            // virtual ID for lookup
-	Stroke        htmlColor         // Renders to stroke:#333
-	StrokeWidth   uint8             // Renders to stroke-width:2px
-	StrokeDash    uint8             // Renders to stroke-dasharray:5px
-	More          string            // More styles, e.g.: stroke:#333,stroke-width:1px
-	Interpolation edgeInterpolation // Edge curve definition
+	Stroke        mermaidgen.Color  `json:"stroke,omitempty" yaml:"stroke,omitempty"`               // Renders to stroke:#333
+	StrokeWidth   uint8             `json:"strokeWidth,omitempty" yaml:"strokeWidth,omitempty"`     // Renders to stroke-width:2px
+	StrokeDash    uint8             `json:"strokeDash,omitempty" yaml:"strokeDash,omitempty"`       // Renders to stroke-dasharray:5px
+	StrokeLinecap strokeLinecap     `json:"strokeLinecap,omitempty" yaml:"strokeLinecap,omitempty"` // Renders to stroke-linecap:round
+	Color         mermaidgen.Color  `json:"color,omitempty" yaml:"color,omitempty"`                 // Renders to color:#333
+	FontSize      uint8             `json:"fontSize,omitempty" yaml:"fontSize,omitempty"`           // Renders to font-size:12px
+	FontWeight    fontWeight        `json:"fontWeight,omitempty" yaml:"fontWeight,omitempty"`       // Renders to font-weight:bold
+	FontFamily    string            `json:"fontFamily,omitempty" yaml:"fontFamily,omitempty"`       // Renders to font-family:monospace
+	Opacity       *float64          `json:"opacity,omitempty" yaml:"opacity,omitempty"`             // Renders to opacity:0.5
+	More          string            `json:"more,omitempty" yaml:"more,omitempty"`                   // More styles, e.g.: stroke:#333,stroke-width:1px
+	Interpolation edgeInterpolation `json:"interpolation,omitempty" yaml:"interpolation,omitempty"` // Edge curve definition
 }
 
 // ID provides access to the EdgeStyle's readonly field id.
@@ -35,12 +43,45 @@ func (es *EdgeStyle) ID() (id string) {
 	return es.id
 }
 
+// Validate checks the EdgeStyle's values and returns an error describing the
+// first invalid value found, e.g. an Opacity out of range or a FontFamily
+// containing characters that would break the mermaid syntax.
+func (es *EdgeStyle) Validate() (err error) {
+	if err = validateColor("Stroke", es.Stroke); err != nil {
+		return
+	}
+	if err = validateColor("Color", es.Color); err != nil {
+		return
+	}
+	switch es.Interpolation {
+	case "", InterpolationBasis, InterpolationLinear:
+	default:
+		return fmt.Errorf("invalid Interpolation %q", es.Interpolation)
+	}
+	return validateText(es.FontWeight, es.FontFamily, es.Opacity,
+		es.StrokeLinecap, es.More)
+}
+
 // String renders this graph element to a linkStyle line.
 func (es *EdgeStyle) String() (renderedElement string) {
 	interpolation := ""
 	if es.Interpolation != "" {
 		interpolation = "interpolate " + string(es.Interpolation)
 	}
+	definitions := es.definitions()
+	if definitions == "" && interpolation == "" {
+		// neutral element as a fallback to ensure empty linkStyles don't break
+		// the mermaid syntax
+		definitions = fmt.Sprintf(`stroke-width:%dpx`, es.StrokeWidth)
+	}
+	if definitions != "" && interpolation != "" {
+		interpolation += " "
+	}
+	return `linkStyle %s ` + interpolation + definitions + "\n"
+}
+
+// Helperfunction to deduplicate code, renders the CSS definitions only.
+func (es *EdgeStyle) definitions() (definitions string) {
 	styles := []string{}
 	if es.Stroke != "" {
 		styles = append(styles, "stroke:"+string(es.Stroke))
@@ -53,17 +94,26 @@ func (es *EdgeStyle) String() (renderedElement string) {
 		styles = append(styles, fmt.Sprintf(`stroke-dasharray:%dpx`,
 			es.StrokeDash))
 	}
-	if es.More != "" {
-		styles = append(styles, es.More)
+	if es.StrokeLinecap != "" {
+		styles = append(styles, "stroke-linecap:"+string(es.StrokeLinecap))
 	}
-	definitions := strings.Join(styles, ",")
-	if definitions == "" && interpolation == "" {
-		// neutral element as a fallback to ensure empty linkStyles don't break
-		// the mermaid syntax
-		definitions = fmt.Sprintf(`stroke-width:%dpx`, es.StrokeWidth)
+	if es.Color != "" {
+		styles = append(styles, "color:"+string(es.Color))
 	}
-	if definitions != "" && interpolation != "" {
-		interpolation += " "
+	if es.FontSize != 0 {
+		styles = append(styles, fmt.Sprintf(`font-size:%dpx`, es.FontSize))
 	}
-	return `linkStyle %s ` + interpolation + definitions + "\n"
+	if es.FontWeight != "" {
+		styles = append(styles, "font-weight:"+string(es.FontWeight))
+	}
+	if es.FontFamily != "" {
+		styles = append(styles, "font-family:"+styleValue(es.FontFamily))
+	}
+	if es.Opacity != nil {
+		styles = append(styles, fmt.Sprintf(`opacity:%g`, *es.Opacity))
+	}
+	if es.More != "" {
+		styles = append(styles, es.More)
+	}
+	return strings.Join(styles, ",")
 }
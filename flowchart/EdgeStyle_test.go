@@ -56,3 +56,28 @@ func ExampleEdgeStyle_defaultStyle() {
 	//graph TB
 	//linkStyle default interpolate basis
 }
+
+// Typed CSS properties for Edge labels and lines
+func ExampleEdgeStyle_typedProperties() {
+	f := flowchart.NewFlowchart()
+	e := f.AddEdge(f.AddNode("n1"), f.AddNode("n2"))
+	e.AddLines("label")
+	e.Style = f.EdgeStyle("es1")
+	e.Style.StrokeLinecap = flowchart.LinecapSquare
+	e.Style.Color = flowchart.ColorBlue
+	e.Style.FontSize = 10
+	e.Style.FontWeight = flowchart.FontWeightLighter
+	fmt.Println(e.Style.Validate())
+	e.Style.Interpolation = "zigzag"
+	fmt.Println(e.Style.Validate())
+	e.Style.Interpolation = ""
+	fmt.Print(f)
+	//Output:
+	//<nil>
+	//invalid Interpolation "zigzag"
+	//graph TB
+	//n1["n1"]
+	//n2["n2"]
+	//n1 -->|"label"| n2
+	//linkStyle 0 stroke-linecap:square,color:#00f,font-size:10px,font-weight:lighter
+}
@@ -1,13 +1,13 @@
 package flowchart
 
 import (
-	"bytes"
-	"compress/zlib"
-	"encoding/base64"
-	"encoding/json"
+	"bufio"
 	"fmt"
-	"os/exec"
-	"runtime"
+	"io"
+	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/internal/counting"
 )
 
 ////////// ChartDirection //////////////////////////////////////////////////////
@@ -24,11 +24,30 @@ const (
 	DirectionLeftRight chartDirection = `LR`
 )
 
+////////// ClassMode /////////////////////////////////////////////////////////
+
+type classMode string
+
+// Definitions of how NodeStyles are assigned to Nodes and Subgraphs.
+// New Flowcharts get ClassesPerItem as the default.
+//
+// ClassesPerItem renders a class line right after each Node or Subgraph.
+// ClassesInline renders the class of Nodes with exactly one NodeStyle using
+// the :::class shorthand, other Nodes and Subgraphs get class lines.
+// ClassesBulk renders one class line per NodeStyle listing all its Nodes and
+// Subgraphs, e.g. class n1,n2,n3 style1, to keep large graphs compact.
+const (
+	ClassesPerItem classMode = `class`
+	ClassesInline  classMode = `:::`
+	ClassesBulk    classMode = `bulk`
+)
+
 ////////// GraphItem ///////////////////////////////////////////////////////////
 
 // interface to define what can be an "item" to a Flowchart/Subgraph
 type graphItem interface {
-	renderGraph() string
+	writeGraph(w *graphWriter)
+	writeStyles(w *graphWriter)
 }
 
 ////////// Flowchart ///////////////////////////////////////////////////////////
@@ -38,6 +57,7 @@ type graphItem interface {
 // Flowchart's constructor NewFlowchart, do not create instances directly.
 type Flowchart struct {
 	nodeStyles       map[string]*NodeStyle // internal storage for NodeStyles
+	nodeStyleList    []*NodeStyle          // NodeStyles for ordered rendering
 	edgeStyles       map[string]*EdgeStyle // internal storage for EdgeStyles
 	subgraphs        map[string]*Subgraph  // internal storage for Subgraphs
 	nodes            map[string]*Node      // internal storage for Nodes
@@ -45,6 +65,11 @@ type Flowchart struct {
 	items            []graphItem           // sub-items to render
 	Direction        chartDirection        // The direction used to render the graph.
 	DefaultEdgeStyle *EdgeStyle            // Define a default linkStyle element.
+	DefaultNodeStyle *NodeStyle            // Define a default classDef element.
+	ClassMode        classMode             // How classes are assigned to items.
+	Config           *mermaidgen.Config    // Optional theme and configuration.
+	Indent           string                // Indentation per Subgraph level.
+	Grouped          bool                  // Render lines grouped by purpose.
 }
 
 // NewFlowchart is the constructor used to create a new Flowchart object.
@@ -53,6 +78,7 @@ type Flowchart struct {
 func NewFlowchart() (newFlowchart *Flowchart) {
 	f := &Flowchart{}
 	f.Direction = DirectionTopDown
+	f.ClassMode = ClassesPerItem
 	f.nodeStyles = make(map[string]*NodeStyle)
 	f.edgeStyles = make(map[string]*EdgeStyle)
 	f.subgraphs = make(map[string]*Subgraph)
@@ -61,74 +87,259 @@ func NewFlowchart() (newFlowchart *Flowchart) {
 }
 
 // String recursively renders the whole graph to mermaid code lines.
+// It is a shorthand for WriteTo with a strings.Builder.
 func (fc *Flowchart) String() (renderedElement string) {
-	text := fmt.Sprintf("graph %s\n", fc.Direction)
-	if fc.DefaultEdgeStyle != nil {
-		text += fmt.Sprintf(fc.DefaultEdgeStyle.String(), "default")
-	}
-	for _, s := range fc.nodeStyles {
-		text += s.String()
+	return renderString("", fc.writeGraph)
+}
+
+// WriteTo recursively renders the whole graph to mermaid code lines and
+// streams them to w through a single buffered writer, so the rendering time
+// grows linearly with the size of the graph. Implements io.WriterTo.
+//
+// The contents of Subgraphs are indented by Indent per nesting level, e.g.
+// "  " or "\t". If Grouped is set, the lines are ordered by purpose instead of
+// by item, each group being introduced by a comment: the definitions of Nodes
+// and Subgraphs, Edges, styles (classDef, class, style and linkStyle lines)
+// and interactions (click lines).
+func (fc *Flowchart) WriteTo(w io.Writer) (n int64, err error) {
+	cw := &counting.Writer{W: w}
+	b := bufio.NewWriter(cw)
+	fc.writeGraph(&graphWriter{Writer: b})
+	err = b.Flush()
+	return cw.N, err
+}
+
+// Helperfunction for WriteTo and String, renders the whole graph to w. Write
+// errors are kept by w and reported by its Flush method.
+func (fc *Flowchart) writeGraph(w *graphWriter) {
+	w.indent, w.grouped = fc.Indent, fc.Grouped
+	w.WriteString(fc.Config.String())
+	fmt.Fprintf(w, "graph %s\n", fc.Direction)
+	if !fc.Grouped {
+		fc.writeStyleDefinitions(w)
+		for _, item := range fc.items {
+			item.writeGraph(w)
+		}
+		if fc.classMode() == ClassesBulk {
+			fc.writeBulkClasses(w)
+		}
+		for _, e := range fc.edges {
+			e.writeGraph(w)
+		}
+		return
 	}
+	w.group = "nodes"
 	for _, item := range fc.items {
-		text += item.renderGraph()
+		item.writeGraph(w)
 	}
+	w.group = "edges"
 	for _, e := range fc.edges {
-		text += e.String()
+		e.writeGraph(w)
+	}
+	w.group = "styles"
+	fc.writeStyleDefinitions(w)
+	walkItems(fc.items, func(item graphItem) {
+		item.writeStyles(w)
+	})
+	if fc.classMode() == ClassesBulk {
+		fc.writeBulkClasses(w)
+	}
+	for _, e := range fc.edges {
+		e.writeStyle(w)
+	}
+	w.group = "interactions"
+	walkItems(fc.items, func(item graphItem) {
+		if n, ok := item.(*Node); ok {
+			n.writeClick(w)
+		}
+	})
+}
+
+// Helperfunction to deduplicate code, renders the default styles and all
+// classDef lines.
+func (fc *Flowchart) writeStyleDefinitions(w *graphWriter) {
+	if fc.DefaultEdgeStyle != nil {
+		w.startLine()
+		fmt.Fprintf(w, fc.DefaultEdgeStyle.String(), "default")
+	}
+	if fc.DefaultNodeStyle != nil {
+		w.startLine()
+		w.WriteString("classDef default " + fc.DefaultNodeStyle.definitions() +
+			"\n")
+	}
+	for _, s := range fc.nodeStyleList {
+		if s == fc.DefaultNodeStyle && (s.id == "default" || !fc.styleUsed(s)) {
+			// already rendered above, only needed under its own name if an
+			// item references it
+			continue
+		}
+		w.startLine()
+		w.WriteString(s.String())
+	}
+}
+
+// Helperfunction to check whether a NodeStyle is assigned to any Node or
+// Subgraph.
+func (fc *Flowchart) styleUsed(style *NodeStyle) (used bool) {
+	walkItems(fc.items, func(item graphItem) {
+		var styles []*NodeStyle
+		switch v := item.(type) {
+		case *Node:
+			styles = v.styles()
+		case *Subgraph:
+			styles = v.Styles
+		}
+		for _, s := range styles {
+			used = used || s == style
+		}
+	})
+	return
+}
+
+// Helperfunction to deduplicate code, calls fn for all items in rendering
+// order, Subgraphs follow their contents like their class lines do.
+func walkItems(items []graphItem, fn func(item graphItem)) {
+	for _, item := range items {
+		if sg, ok := item.(*Subgraph); ok {
+			walkItems(sg.items, fn)
+		}
+		fn(item)
+	}
+}
+
+// Helperfunction to deduplicate code, returns the effective ClassMode.
+func (fc *Flowchart) classMode() (mode classMode) {
+	if fc == nil || fc.ClassMode == "" {
+		return ClassesPerItem
+	}
+	return fc.ClassMode
+}
+
+// Helperfunction for ClassesBulk, renders one class line per NodeStyle for all
+// Nodes and Subgraphs in rendering order.
+func (fc *Flowchart) writeBulkClasses(w *graphWriter) {
+	ids := make(map[*NodeStyle][]string)
+	walkItems(fc.items, func(item graphItem) {
+		switch v := item.(type) {
+		case *Node:
+			for _, s := range v.styles() {
+				ids[s] = append(ids[s], v.id)
+			}
+		case *Subgraph:
+			for _, s := range v.Styles {
+				ids[s] = append(ids[s], v.id)
+			}
+		}
+	})
+	for _, s := range fc.nodeStyleList {
+		if len(ids[s]) > 0 {
+			writeClasses(w, strings.Join(ids[s], ","), []*NodeStyle{s})
+		}
 	}
-	return text
 }
 
-// Structs for JSON encode
-type mermaidJSON struct {
-	Theme string `json:"theme"`
+// Render writes the mermaid code of the whole graph to w.
+// Implements mermaidgen.Diagram.
+func (fc *Flowchart) Render(w io.Writer) (err error) {
+	_, err = fc.WriteTo(w)
+	return
 }
 
-type dataJSON struct {
-	Code    string      `json:"code"`
-	Mermaid mermaidJSON `json:"mermaid"`
+// Kind returns mermaidgen.KindFlowchart. Implements mermaidgen.Diagram.
+func (fc *Flowchart) Kind() (kind mermaidgen.DiagramKind) {
+	return mermaidgen.KindFlowchart
+}
+
+// GetConfig returns the Flowchart's Config, which may be nil.
+// Implements mermaidgen.Diagram.
+func (fc *Flowchart) GetConfig() (config *mermaidgen.Config) {
+	return fc.Config
+}
+
+// SetConfig replaces the Flowchart's Config, nil removes it.
+// Implements mermaidgen.Diagram.
+func (fc *Flowchart) SetConfig(config *mermaidgen.Config) {
+	fc.Config = config
 }
 
 // LiveURL renders the Flowchart and generates a view URL for
-// https://mermaidjs.github.io/mermaid-live-editor from it.
+// https://mermaid.live from it, see mermaidgen.LiveURL for details.
 func (fc *Flowchart) LiveURL() (url string) {
-	liveURL := `https://mermaid.live/view/#pako:`
-	data, _ := json.Marshal(dataJSON{
-		Code: fc.String(), Mermaid: mermaidJSON{Theme: "default"},
-	})
-	var b bytes.Buffer
-	w, _ := zlib.NewWriterLevel(&b, zlib.BestCompression)
-	w.Write(data)
-	w.Close()
-	return liveURL + base64.URLEncoding.EncodeToString(b.Bytes())
+	url, _ = mermaidgen.LiveURL(fc)
+	return
 }
 
 // ViewInBrowser uses the URL generated by Flowchart's LiveURL method and opens
-// that URL in the OS's default browser. It starts the browser command
-// non-blocking and eventually returns any error occured.
+// that URL in the OS's default browser via mermaidgen.ViewInBrowser. It
+// eventually returns any error occured.
 func (fc *Flowchart) ViewInBrowser() (err error) {
-	switch runtime.GOOS {
-	case "openbsd", "linux":
-		return exec.Command("xdg-open", fc.LiveURL()).Start()
-	case "darwin":
-		return exec.Command("open", fc.LiveURL()).Start()
-	case "windows":
-		return exec.Command("rundll32", "url.dll,FileProtocolHandler",
-			fc.LiveURL()).Start()
-	default:
-		return fmt.Errorf("unsupported platform")
+	return mermaidgen.ViewInBrowser(fc)
+}
+
+////////// rendering helpers ///////////////////////////////////////////////////
+
+// Helperfunction to deduplicate code, renders a single element to a string
+// using the given indentation per nesting level.
+func renderString(indent string,
+	write func(w *graphWriter)) (renderedElement string) {
+	var s strings.Builder
+	b := bufio.NewWriter(&s)
+	write(&graphWriter{Writer: b, indent: indent})
+	b.Flush()
+	return s.String()
+}
+
+// Buffered writer used for rendering, it knows the formatting options of the
+// Flowchart and the current Subgraph nesting level.
+type graphWriter struct {
+	*bufio.Writer
+	indent  string // indentation per nesting level
+	grouped bool   // lines are grouped by purpose
+	level   int    // current nesting level
+	group   string // group comment to write before the next line
+}
+
+// Helperfunction to start a new line, writes the pending group comment and the
+// indentation of the current nesting level.
+func (w *graphWriter) startLine() {
+	if w.group != "" {
+		w.WriteString("%% " + w.group + "\n")
+		w.group = ""
 	}
+	for i := 0; i < w.level; i++ {
+		w.WriteString(w.indent)
+	}
+}
+
+// Helperfunction to write a comment attached to an item, each line of the
+// text becomes a %% line.
+func (w *graphWriter) comment(text string) {
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		w.startLine()
+		w.WriteString(strings.TrimRight("%% "+line, " \r") + "\n")
+	}
+}
+
+// Helperfunction to escape double quotes in texts, they end mermaid strings.
+func escapeQuotes(text string) (escaped string) {
+	return strings.Replace(text, `"`, "#quot;", -1)
 }
 
 ////////// add & get Styles ////////////////////////////////////////////////////
 
 // NodeStyle is used to create new or lookup existing NodeStyles by ID.
 // The returned object pointers can be assigned to any number of Nodes
-// to style them using CSS.
+// or Subgraphs to style them using CSS. NodeStyles are rendered in the order
+// they were created.
 func (fc *Flowchart) NodeStyle(id string) (style *NodeStyle) {
 	s, found := fc.nodeStyles[id]
 	if !found {
 		s = &NodeStyle{id: id, StrokeWidth: 1}
 		fc.nodeStyles[id] = s
+		fc.nodeStyleList = append(fc.nodeStyleList, s)
 	}
 	return s
 }
@@ -174,7 +385,7 @@ func (fc *Flowchart) AddNode(id string) (newNode *Node) {
 	if alreadyExists {
 		return nil
 	}
-	n := &Node{id: id, Shape: NShapeRect}
+	n := &Node{id: id, flowchart: fc, Shape: NShapeRect}
 	fc.nodes[id] = n
 	fc.items = append(fc.items, n)
 	return n
@@ -219,6 +430,148 @@ func (fc *Flowchart) GetEdge(index int) (existingEdge *Edge) {
 	return fc.edges[index]
 }
 
+////////// remove Items ////////////////////////////////////////////////////////
+
+// Helperfunction to deduplicate code, returns the item list of the given
+// container, nil means the top level Flowchart.
+func (fc *Flowchart) itemsOf(sg *Subgraph) (items *[]graphItem) {
+	if sg == nil {
+		return &fc.items
+	}
+	return &sg.items
+}
+
+// Helperfunction to deduplicate code, removes item from the given list and
+// returns the index it was found at or -1.
+func removeItem(items *[]graphItem, item graphItem) (index int) {
+	for i, it := range *items {
+		if it == item {
+			*items = append((*items)[:i], (*items)[i+1:]...)
+			return i
+		}
+	}
+	return -1
+}
+
+// Helperfunction to deduplicate code, re-assigns the Edges' IDs (indices) after
+// Edges have been removed so that linkStyle lines stay correct.
+func (fc *Flowchart) reindexEdges() {
+	for i, e := range fc.edges {
+		e.id = i
+	}
+}
+
+// RemoveNode removes a previously defined Node by its ID from the Flowchart or
+// the Subgraph it is contained in. All Edges starting or ending at that Node
+// are removed, too, and the IDs (indices) of the remaining Edges are updated.
+// The removed Node is returned, if this ID doesn't exist, nil is returned.
+func (fc *Flowchart) RemoveNode(id string) (removedNode *Node) {
+	n, found := fc.nodes[id]
+	if !found {
+		return nil
+	}
+	delete(fc.nodes, id)
+	removeItem(fc.itemsOf(n.subgraph), n)
+	n.subgraph = nil
+	edges := make([]*Edge, 0, len(fc.edges))
+	for _, e := range fc.edges {
+		if e.From != n && e.To != n {
+			edges = append(edges, e)
+		}
+	}
+	fc.edges = edges
+	fc.reindexEdges()
+	return n
+}
+
+// RemoveEdge removes a previously defined Edge by its ID (index). The IDs of
+// all following Edges are decremented accordingly, so linkStyle lines keep
+// matching. The removed Edge is returned, if this index doesn't exist, nil is
+// returned.
+func (fc *Flowchart) RemoveEdge(index int) (removedEdge *Edge) {
+	e := fc.GetEdge(index)
+	if e == nil {
+		return nil
+	}
+	fc.edges = append(fc.edges[:index], fc.edges[index+1:]...)
+	fc.reindexEdges()
+	return e
+}
+
+// Helperfunction for RemoveSubgraph, recursively removes a detached item and
+// all of its contents from the Flowchart's lookup tables.
+func (fc *Flowchart) removeContents(item graphItem) {
+	switch v := item.(type) {
+	case *Node:
+		fc.RemoveNode(v.id)
+	case *Subgraph:
+		delete(fc.subgraphs, v.id)
+		items := v.items
+		v.items, v.subgraph = nil, nil
+		for _, sub := range items {
+			fc.removeContents(sub)
+		}
+	}
+}
+
+// RemoveSubgraph removes a previously defined Subgraph by its ID. If
+// withContents is true, all nested Subgraphs and Nodes (and thus all Edges
+// connected to them) are removed as well. Otherwise the contents are moved
+// to the Subgraph's parent (Subgraph or top level Flowchart) at the position
+// of the removed Subgraph. The removed Subgraph is returned, if this ID doesn't
+// exist, nil is returned.
+func (fc *Flowchart) RemoveSubgraph(id string, withContents bool) (removedSubgraph *Subgraph) {
+	sg, found := fc.subgraphs[id]
+	if !found {
+		return nil
+	}
+	delete(fc.subgraphs, id)
+	parent := fc.itemsOf(sg.subgraph)
+	index := removeItem(parent, sg)
+	items := sg.items
+	sg.items = nil
+	if withContents {
+		for _, item := range items {
+			fc.removeContents(item)
+		}
+	} else {
+		for _, item := range items {
+			switch v := item.(type) {
+			case *Node:
+				v.subgraph = sg.subgraph
+			case *Subgraph:
+				v.subgraph = sg.subgraph
+			}
+		}
+		tail := append(items, (*parent)[index:]...)
+		*parent = append((*parent)[:index], tail...)
+	}
+	sg.subgraph = nil
+	return sg
+}
+
+////////// move Items //////////////////////////////////////////////////////////
+
+// MoveNode moves a previously defined Node by its ID to the given Subgraph,
+// or to the top level Flowchart if to is nil. The Node is appended to the end
+// of its new container, Edges are not affected. The moved Node is returned, if
+// this ID doesn't exist or the Subgraph doesn't belong to this Flowchart, nil
+// is returned.
+func (fc *Flowchart) MoveNode(id string, to *Subgraph) (movedNode *Node) {
+	n, found := fc.nodes[id]
+	if !found {
+		return nil
+	}
+	if to != nil && fc.subgraphs[to.id] != to {
+		return nil
+	}
+	removeItem(fc.itemsOf(n.subgraph), n)
+	n.subgraph = to
+	target := fc.itemsOf(to)
+	*target = append(*target, n)
+	return n
+}
+
 ////////// list Items //////////////////////////////////////////////////////////
 
 // ListSubgraphs returns a slice of all previously defined Subgraphs.
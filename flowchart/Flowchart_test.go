@@ -1,8 +1,14 @@
 package flowchart_test
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
 
+	"github.com/Heiko-san/mermaidgen"
 	"github.com/Heiko-san/mermaidgen/flowchart"
 )
 
@@ -60,5 +66,213 @@ func ExampleFlowchart_liveEditorLinks() {
 	f.AddEdge(f.AddNode("n1"), f.AddNode("n2"))
 	// you can also use f.ViewInBrowser() to open the URL in browser directly
 	fmt.Println(f.LiveURL())
-	// Output: https://mermaid.live/view/#pako:eNqqVkrOT0lVslJKL0osyFAIcYrJyzOMjlHKM4xRio3JyzMCsY0gbEMFXd2YUgMD41SFPKOYPCUdpdzUotzEzBQlq2qlkozUXJA5KalpiaU5JUq1tYAAAAD__yEwHQk=
+	// Output: https://mermaid.live/view/#pako:eNqqVkrOT0lVslJKL0osyFAIcYrJyzOMjlHKM4xRio3JyzMCsY0gbEMFXd2YUgMD41SFPKOYPCUdpdzUotzEzBQlq2qlkozUXJA5KalpiaU5JUq1tYABACEwHQk=
+}
+
+// Removing Nodes, Edges and Subgraphs
+func ExampleFlowchart_removeItems() {
+	f := flowchart.NewFlowchart()
+	sg1 := f.AddSubgraph("sg1")
+	sg1.Title = "keep my contents"
+	sg2 := f.AddSubgraph("sg2")
+	sg2.Title = "drop my contents"
+	n1 := f.AddNode("n1")
+	n2 := sg1.AddNode("n2")
+	n3 := sg2.AddNode("n3")
+	n4 := f.AddNode("n4")
+	f.AddEdge(n1, n2)
+	f.AddEdge(n1, n3)
+	f.AddEdge(n2, n4).Style = f.EdgeStyle("es1")
+	f.AddEdge(n1, n4)
+	// removing a Node removes all of its Edges, too
+	f.RemoveNode("n3")
+	// Edge IDs (indices) are updated, so linkStyles keep matching
+	fmt.Println(f.RemoveEdge(0).ID(), f.GetEdge(1).ID())
+	// Subgraphs may be removed with or without their contents
+	f.RemoveSubgraph("sg1", false)
+	f.RemoveSubgraph("sg2", true)
+	// removing unknown IDs yields nil
+	fmt.Println(f.RemoveNode("n3"), f.RemoveEdge(5), f.RemoveSubgraph("sg2", true))
+	fmt.Print(f)
+	//Output:
+	//0 1
+	//<nil> <nil> <nil>
+	//graph TB
+	//n2["n2"]
+	//n1["n1"]
+	//n4["n4"]
+	//n2 --> n4
+	//linkStyle 0 stroke-width:1px
+	//n1 --> n4
+}
+
+// Moving Nodes between Subgraphs and the top level
+func ExampleFlowchart_moveNodes() {
+	f := flowchart.NewFlowchart()
+	sg1 := f.AddSubgraph("sg1")
+	sg1.Title = "sg1"
+	sg2 := f.AddSubgraph("sg2")
+	sg2.Title = "sg2"
+	sg1.AddNode("n1")
+	f.AddNode("n2")
+	// move into a Subgraph
+	f.MoveNode("n2", sg1)
+	// move from one Subgraph to another
+	f.MoveNode("n1", sg2)
+	// a nil Subgraph means the top level Flowchart
+	n3 := sg2.AddNode("n3")
+	f.MoveNode("n3", nil)
+	fmt.Println(n3.Subgraph() == nil, f.GetNode("n1").Subgraph() == sg2)
+	fmt.Print(f)
+	//Output:
+	//true true
+	//graph TB
+	//subgraph sg1 ["sg1"]
+	//n2["n2"]
+	//end
+	//subgraph sg2 ["sg2"]
+	//n1["n1"]
+	//end
+	//n3["n3"]
+}
+
+// Defining a theme and further configuration
+func ExampleFlowchart_config() {
+	f := flowchart.NewFlowchart()
+	f.AddNode("n1")
+	f.Config, _ = mermaidgen.NewConfig(mermaidgen.ThemeDark)
+	f.Config.Flowchart = &mermaidgen.FlowchartConfig{NodeSpacing: 80}
+	fmt.Print(f)
+	//Output:
+	//---
+	//config:
+	//   flowchart:
+	//     nodeSpacing: 80
+	//   theme: dark
+	//---
+	//graph TB
+	//n1["n1"]
+}
+
+// Streaming a graph to a file or network connection
+func ExampleFlowchart_WriteTo() {
+	f := flowchart.NewFlowchart()
+	f.AddEdge(f.AddNode("n1"), f.AddNode("n2"))
+	n, err := f.WriteTo(os.Stdout)
+	fmt.Println(n, err)
+	//Output:
+	//graph TB
+	//n1["n1"]
+	//n2["n2"]
+	//n1 --> n2
+	//37 <nil>
+}
+
+// Helper type to check the error handling of WriteTo.
+type failingWriter struct{ left int }
+
+func (w *failingWriter) Write(p []byte) (n int, err error) {
+	if len(p) > w.left {
+		n, w.left = w.left, 0
+		return n, errors.New("disk full")
+	}
+	w.left -= len(p)
+	return len(p), nil
+}
+
+func TestFlowchart_WriteTo(t *testing.T) {
+	f := largeFlowchart(1000)
+	var b bytes.Buffer
+	n, err := f.WriteTo(&b)
+	if err != nil || n != int64(b.Len()) || b.String() != f.String() {
+		t.Errorf("WriteTo returned %d, %v for %d bytes", n, err, b.Len())
+	}
+	n, err = f.WriteTo(&failingWriter{left: 5000})
+	if err == nil || n != 5000 {
+		t.Errorf("expected an error after 5000 bytes, got %d, %v", n, err)
+	}
+}
+
+// Helperfunction to build a dependency graph with the given number of Nodes
+// spread over Subgraphs, with styles and two Edges per Node.
+func largeFlowchart(nodes int) (f *flowchart.Flowchart) {
+	f = flowchart.NewFlowchart()
+	style := f.NodeStyle("pkg")
+	style.Fill = "#eee"
+	var sg *flowchart.Subgraph
+	for i := 0; i < nodes; i++ {
+		if i%100 == 0 {
+			sg = f.AddSubgraph(fmt.Sprintf("module%d", i/100))
+		}
+		n := sg.AddNode(fmt.Sprintf("pkg%d", i))
+		n.AddLines(fmt.Sprintf("github.com/example/pkg%d", i))
+		n.AddStyles(style)
+		if i > 0 {
+			f.AddEdge(f.GetNode(fmt.Sprintf("pkg%d", i/2)), n)
+			f.AddEdge(f.GetNode(fmt.Sprintf("pkg%d", i-1)), n).Shape =
+				flowchart.EShapeDottedArrow
+		}
+	}
+	return
+}
+
+// The ns/op of the sub-benchmarks grow linearly with the number of Nodes.
+func BenchmarkFlowchart_WriteTo(b *testing.B) {
+	for _, nodes := range []int{1000, 5000, 20000} {
+		f := largeFlowchart(nodes)
+		b.Run(fmt.Sprintf("nodes=%d", nodes), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				f.WriteTo(ioutil.Discard)
+			}
+		})
+	}
+}
+
+func BenchmarkFlowchart_String(b *testing.B) {
+	for _, nodes := range []int{1000, 5000, 20000} {
+		f := largeFlowchart(nodes)
+		b.Run(fmt.Sprintf("nodes=%d", nodes), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_ = f.String()
+			}
+		})
+	}
+}
+
+// Readable output with indentation, groups and comments
+func ExampleFlowchart_prettyPrint() {
+	f := flowchart.NewFlowchart()
+	f.Indent = "  "
+	f.Grouped = true
+	sg := f.AddSubgraph("backend")
+	sg.Comment = "services behind the load balancer"
+	api := sg.AddNode("api")
+	api.AddStyles(f.NodeStyle("service"))
+	api.Link = "https://api.example.com"
+	db := sg.AddSubgraph("storage").AddNode("db")
+	db.Shape = flowchart.NShapeRoundRect
+	e := f.AddEdge(api, db)
+	e.Comment = "reads and writes\nvia connection pool"
+	e.Style = f.EdgeStyle("data")
+	fmt.Print(f)
+	//Output:
+	//graph TB
+	//%% nodes
+	//%% services behind the load balancer
+	//subgraph backend ["backend"]
+	//   api["api"]
+	//   subgraph storage ["storage"]
+	//     db("db")
+	//   end
+	//end
+	//%% edges
+	//%% reads and writes
+	//%% via connection pool
+	//api --> db
+	//%% styles
+	//classDef service stroke-width:1px
+	//class api service
+	//linkStyle 0 stroke-width:1px
+	//%% interactions
+	//click api "https://api.example.com" "https://api.example.com"
 }
@@ -1,15 +0,0 @@
-package flowchart
-
-type htmlColor string
-
-// Color definitions for use with NodeStyles and EdgeStyles.
-const (
-	ColorBlack   htmlColor = `#000`
-	ColorBlue    htmlColor = `#00f`
-	ColorGreen   htmlColor = `#0f0`
-	ColorCyan    htmlColor = `#0ff`
-	ColorRed     htmlColor = `#f00`
-	ColorMagenta htmlColor = `#f0f`
-	ColorYellow  htmlColor = `#ff0`
-	ColorWhite   htmlColor = `#fff`
-)
@@ -0,0 +1,372 @@
+package flowchart
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/Heiko-san/mermaidgen"
+)
+
+// SchemaVersion is the version of the JSON and YAML schema written by
+// Flowchart's MarshalJSON and MarshalYAML methods. It is increased on
+// incompatible changes, documents of other versions are rejected.
+const SchemaVersion = 1
+
+// Names of the shapes in the JSON and YAML schema.
+var (
+	nodeShapeNames = map[nodeShape]string{NShapeRect: "rect",
+		NShapeRoundRect: "roundRect", NShapeCircle: "circle",
+		NShapeRhombus: "rhombus", NShapeFlagLeft: "flagLeft"}
+	edgeShapeNames = map[edgeShape]string{EShapeArrow: "arrow",
+		EShapeDottedArrow: "dottedArrow", EShapeThickArrow: "thickArrow",
+		EShapeLine: "line", EShapeDottedLine: "dottedLine",
+		EShapeThickLine: "thickLine"}
+)
+
+// Schema of a Flowchart. NodeStyles, EdgeStyles and Nodes are referenced by
+// their IDs.
+type jsonFlowchart struct {
+	Version          int                `json:"version" yaml:"version"`
+	Kind             string             `json:"kind" yaml:"kind"`
+	Direction        chartDirection     `json:"direction,omitempty" yaml:"direction,omitempty"`
+	ClassMode        classMode          `json:"classMode,omitempty" yaml:"classMode,omitempty"`
+	Indent           string             `json:"indent,omitempty" yaml:"indent,omitempty"`
+	Grouped          bool               `json:"grouped,omitempty" yaml:"grouped,omitempty"`
+	Config           *mermaidgen.Config `json:"config,omitempty" yaml:"config,omitempty"`
+	ConfigSyntax     string             `json:"configSyntax,omitempty" yaml:"configSyntax,omitempty"`
+	ConfigTitle      string             `json:"configTitle,omitempty" yaml:"configTitle,omitempty"`
+	NodeStyles       []jsonNodeStyle    `json:"nodeStyles,omitempty" yaml:"nodeStyles,omitempty"`
+	EdgeStyles       []jsonEdgeStyle    `json:"edgeStyles,omitempty" yaml:"edgeStyles,omitempty"`
+	DefaultNodeStyle *string            `json:"defaultNodeStyle,omitempty" yaml:"defaultNodeStyle,omitempty"`
+	DefaultEdgeStyle *string            `json:"defaultEdgeStyle,omitempty" yaml:"defaultEdgeStyle,omitempty"`
+	Items            []jsonItem         `json:"items,omitempty" yaml:"items,omitempty"`
+	Edges            []jsonEdge         `json:"edges,omitempty" yaml:"edges,omitempty"`
+}
+
+// Schema of a NodeStyle.
+type jsonNodeStyle struct {
+	ID        string `json:"id" yaml:"id"`
+	NodeStyle `yaml:",inline"`
+}
+
+// Schema of an EdgeStyle.
+type jsonEdgeStyle struct {
+	ID        string `json:"id" yaml:"id"`
+	EdgeStyle `yaml:",inline"`
+}
+
+// Schema of a Node (Type "node") or Subgraph (Type "subgraph").
+type jsonItem struct {
+	Type        string     `json:"type" yaml:"type"`
+	ID          string     `json:"id" yaml:"id"`
+	Shape       string     `json:"shape,omitempty" yaml:"shape,omitempty"`
+	Title       string     `json:"title,omitempty" yaml:"title,omitempty"`
+	Text        []string   `json:"text,omitempty" yaml:"text,omitempty"`
+	Link        string     `json:"link,omitempty" yaml:"link,omitempty"`
+	LinkText    string     `json:"linkText,omitempty" yaml:"linkText,omitempty"`
+	Styles      []string   `json:"styles,omitempty" yaml:"styles,omitempty"`
+	InlineStyle *NodeStyle `json:"inlineStyle,omitempty" yaml:"inlineStyle,omitempty"`
+	Items       []jsonItem `json:"items,omitempty" yaml:"items,omitempty"`
+	Comment     string     `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+// Schema of an Edge.
+type jsonEdge struct {
+	From    string   `json:"from" yaml:"from"`
+	To      string   `json:"to" yaml:"to"`
+	Shape   string   `json:"shape,omitempty" yaml:"shape,omitempty"`
+	Text    []string `json:"text,omitempty" yaml:"text,omitempty"`
+	Style   *string  `json:"style,omitempty" yaml:"style,omitempty"`
+	Comment string   `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+////////// encode //////////////////////////////////////////////////////////////
+
+// MarshalJSON encodes the whole Flowchart to a versioned JSON document (see
+// SchemaVersion), which can be decoded by UnmarshalJSON without any loss,
+// including the IDs, the order of all items and the relations between Nodes,
+// Edges and styles. An error is returned if the Flowchart references Nodes or
+// styles that don't belong to it, e.g. because they were removed.
+// Implements json.Marshaler.
+func (fc *Flowchart) MarshalJSON() (data []byte, err error) {
+	s, err := fc.schema()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(s)
+}
+
+// MarshalYAML returns the same structure as MarshalJSON for YAML libraries
+// like gopkg.in/yaml.v2 and gopkg.in/yaml.v3, which call it automatically.
+func (fc *Flowchart) MarshalYAML() (document interface{}, err error) {
+	return fc.schema()
+}
+
+// Helperfunction to convert the Flowchart to its schema.
+func (fc *Flowchart) schema() (s *jsonFlowchart, err error) {
+	s = &jsonFlowchart{Version: SchemaVersion,
+		Kind: string(mermaidgen.KindFlowchart), Direction: fc.Direction,
+		ClassMode: fc.ClassMode, Indent: fc.Indent, Grouped: fc.Grouped,
+		Config: fc.Config}
+	if fc.Config != nil {
+		s.ConfigSyntax, s.ConfigTitle = string(fc.Config.Syntax), fc.Config.Title
+	}
+	for _, ns := range fc.nodeStyleList {
+		s.NodeStyles = append(s.NodeStyles, jsonNodeStyle{ns.id, *ns})
+	}
+	ids := make([]string, 0, len(fc.edgeStyles))
+	for id := range fc.edgeStyles {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		s.EdgeStyles = append(s.EdgeStyles,
+			jsonEdgeStyle{id, *fc.edgeStyles[id]})
+	}
+	if fc.DefaultNodeStyle != nil {
+		if s.DefaultNodeStyle, err = fc.nodeStyleRef(
+			fc.DefaultNodeStyle); err != nil {
+			return nil, err
+		}
+	}
+	if fc.DefaultEdgeStyle != nil {
+		if s.DefaultEdgeStyle, err = fc.edgeStyleRef(
+			fc.DefaultEdgeStyle); err != nil {
+			return nil, err
+		}
+	}
+	if s.Items, err = fc.schemaItems(fc.items); err != nil {
+		return nil, err
+	}
+	for _, e := range fc.edges {
+		if e.From == nil || e.To == nil || fc.nodes[e.From.id] != e.From ||
+			fc.nodes[e.To.id] != e.To {
+			return nil, fmt.Errorf("MarshalJSON: Edge %d references a Node "+
+				"that doesn't belong to the Flowchart", e.id)
+		}
+		je := jsonEdge{From: e.From.id, To: e.To.id,
+			Shape: shapeName(edgeShapeNames[e.Shape], string(e.Shape)),
+			Text:  e.Text, Comment: e.Comment}
+		if e.Style != nil {
+			if je.Style, err = fc.edgeStyleRef(e.Style); err != nil {
+				return nil, err
+			}
+		}
+		s.Edges = append(s.Edges, je)
+	}
+	return s, nil
+}
+
+// Helperfunction to convert Nodes and Subgraphs recursively.
+func (fc *Flowchart) schemaItems(items []graphItem) (schema []jsonItem,
+	err error) {
+	for _, item := range items {
+		var ji jsonItem
+		var styles []*NodeStyle
+		switch v := item.(type) {
+		case *Node:
+			ji = jsonItem{Type: "node", ID: v.id,
+				Shape: shapeName(nodeShapeNames[v.Shape], string(v.Shape)),
+				Text:  v.Text, Link: v.Link, LinkText: v.LinkText,
+				InlineStyle: v.inline, Comment: v.Comment}
+			styles = v.styles()
+		case *Subgraph:
+			ji = jsonItem{Type: "subgraph", ID: v.id, Title: v.Title,
+				InlineStyle: v.inline, Comment: v.Comment}
+			if ji.Items, err = fc.schemaItems(v.items); err != nil {
+				return nil, err
+			}
+			styles = v.Styles
+		}
+		for _, ns := range styles {
+			ref, err := fc.nodeStyleRef(ns)
+			if err != nil {
+				return nil, err
+			}
+			ji.Styles = append(ji.Styles, *ref)
+		}
+		schema = append(schema, ji)
+	}
+	return
+}
+
+// Helperfunction to get the name of a shape, shapes that are not defined by
+// this package are kept as they are.
+func shapeName(name, shape string) (schemaName string) {
+	if name == "" {
+		return shape
+	}
+	return name
+}
+
+// Helperfunction to reference a NodeStyle by its ID.
+func (fc *Flowchart) nodeStyleRef(ns *NodeStyle) (id *string, err error) {
+	if fc.nodeStyles[ns.id] != ns {
+		return nil, fmt.Errorf("MarshalJSON: NodeStyle %q doesn't belong to "+
+			"the Flowchart", ns.id)
+	}
+	return &ns.id, nil
+}
+
+// Helperfunction to reference an EdgeStyle by its ID.
+func (fc *Flowchart) edgeStyleRef(es *EdgeStyle) (id *string, err error) {
+	if fc.edgeStyles[es.id] != es {
+		return nil, fmt.Errorf("MarshalJSON: EdgeStyle %q doesn't belong to "+
+			"the Flowchart", es.id)
+	}
+	return &es.id, nil
+}
+
+////////// decode //////////////////////////////////////////////////////////////
+
+// UnmarshalJSON replaces the Flowchart with the one decoded from a JSON
+// document as written by MarshalJSON. Unlike other Flowcharts, a zero value
+// Flowchart may be used as target. An error is returned if the document has
+// another SchemaVersion or kind, or if it contains duplicate IDs or unknown
+// references. Implements json.Unmarshaler.
+func (fc *Flowchart) UnmarshalJSON(data []byte) (err error) {
+	var s jsonFlowchart
+	if err = json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("UnmarshalJSON: %s", err)
+	}
+	return fc.fromSchema(&s)
+}
+
+// UnmarshalYAML is the counterpart of MarshalYAML for YAML libraries like
+// gopkg.in/yaml.v2 and gopkg.in/yaml.v3, see UnmarshalJSON for details.
+func (fc *Flowchart) UnmarshalYAML(unmarshal func(interface{}) error) (err error) {
+	var s jsonFlowchart
+	if err = unmarshal(&s); err != nil {
+		return err
+	}
+	return fc.fromSchema(&s)
+}
+
+// Helperfunction to replace the Flowchart with the one described by a schema.
+func (fc *Flowchart) fromSchema(s *jsonFlowchart) (err error) {
+	if s.Version != SchemaVersion {
+		return fmt.Errorf("UnmarshalJSON: unsupported schema version %d",
+			s.Version)
+	}
+	if s.Kind != string(mermaidgen.KindFlowchart) {
+		return fmt.Errorf("UnmarshalJSON: unexpected kind %q", s.Kind)
+	}
+	*fc = *NewFlowchart()
+	fc.Direction, fc.ClassMode, fc.Config = s.Direction, s.ClassMode, s.Config
+	fc.Indent, fc.Grouped = s.Indent, s.Grouped
+	if fc.Config != nil {
+		// the constructor converts the string to the unexported type
+		syntax, _ := mermaidgen.NewConfig(mermaidgen.ThemeDefault,
+			s.ConfigSyntax)
+		fc.Config.Syntax, fc.Config.Title = syntax.Syntax, s.ConfigTitle
+	}
+	for _, js := range s.NodeStyles {
+		ns := fc.NodeStyle(js.ID)
+		*ns = js.NodeStyle
+		ns.id = js.ID
+	}
+	for _, js := range s.EdgeStyles {
+		es := fc.EdgeStyle(js.ID)
+		*es = js.EdgeStyle
+		es.id = js.ID
+	}
+	if s.DefaultNodeStyle != nil {
+		fc.DefaultNodeStyle = fc.nodeStyles[*s.DefaultNodeStyle]
+		if fc.DefaultNodeStyle == nil {
+			return fmt.Errorf("UnmarshalJSON: unknown NodeStyle %q",
+				*s.DefaultNodeStyle)
+		}
+	}
+	if s.DefaultEdgeStyle != nil {
+		fc.DefaultEdgeStyle = fc.edgeStyles[*s.DefaultEdgeStyle]
+		if fc.DefaultEdgeStyle == nil {
+			return fmt.Errorf("UnmarshalJSON: unknown EdgeStyle %q",
+				*s.DefaultEdgeStyle)
+		}
+	}
+	if err = fc.itemsFromSchema(nil, s.Items); err != nil {
+		return err
+	}
+	for i, je := range s.Edges {
+		from, to := fc.nodes[je.From], fc.nodes[je.To]
+		if from == nil || to == nil {
+			return fmt.Errorf("UnmarshalJSON: Edge %d references an unknown "+
+				"Node", i)
+		}
+		e := fc.AddEdge(from, to)
+		e.Text, e.Comment = je.Text, je.Comment
+		if je.Shape != "" {
+			e.Shape = edgeShape(je.Shape)
+			for shape, name := range edgeShapeNames {
+				if name == je.Shape {
+					e.Shape = shape
+				}
+			}
+		}
+		if je.Style != nil {
+			if e.Style = fc.edgeStyles[*je.Style]; e.Style == nil {
+				return fmt.Errorf("UnmarshalJSON: unknown EdgeStyle %q",
+					*je.Style)
+			}
+		}
+	}
+	return nil
+}
+
+// Helperfunction to create Nodes and Subgraphs recursively, sg nil means the
+// top level Flowchart.
+func (fc *Flowchart) itemsFromSchema(sg *Subgraph, items []jsonItem) (err error) {
+	for _, ji := range items {
+		var styles *[]*NodeStyle
+		switch ji.Type {
+		case "node":
+			var n *Node
+			if sg == nil {
+				n = fc.AddNode(ji.ID)
+			} else {
+				n = sg.AddNode(ji.ID)
+			}
+			if n == nil {
+				return fmt.Errorf("UnmarshalJSON: duplicate Node %q", ji.ID)
+			}
+			n.Text, n.Link, n.LinkText = ji.Text, ji.Link, ji.LinkText
+			n.inline, n.Comment = ji.InlineStyle, ji.Comment
+			if ji.Shape != "" {
+				n.Shape = nodeShape(ji.Shape)
+				for shape, name := range nodeShapeNames {
+					if name == ji.Shape {
+						n.Shape = shape
+					}
+				}
+			}
+			styles = &n.Styles
+		case "subgraph":
+			var sub *Subgraph
+			if sg == nil {
+				sub = fc.AddSubgraph(ji.ID)
+			} else {
+				sub = sg.AddSubgraph(ji.ID)
+			}
+			if sub == nil {
+				return fmt.Errorf("UnmarshalJSON: duplicate Subgraph %q", ji.ID)
+			}
+			sub.Title, sub.inline = ji.Title, ji.InlineStyle
+			sub.Comment = ji.Comment
+			if err = fc.itemsFromSchema(sub, ji.Items); err != nil {
+				return err
+			}
+			styles = &sub.Styles
+		default:
+			return fmt.Errorf("UnmarshalJSON: unknown item type %q", ji.Type)
+		}
+		for _, id := range ji.Styles {
+			ns := fc.nodeStyles[id]
+			if ns == nil {
+				return fmt.Errorf("UnmarshalJSON: unknown NodeStyle %q", id)
+			}
+			*styles = append(*styles, ns)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package flowchart_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen/flowchart"
+)
+
+// Storing Flowcharts as JSON
+func ExampleFlowchart_MarshalJSON() {
+	f := flowchart.NewFlowchart()
+	f.Direction = flowchart.DirectionLeftRight
+	important := f.NodeStyle("important")
+	important.Fill = "#f96"
+	sg := f.AddSubgraph("sg1")
+	sg.Title = "Services"
+	api := sg.AddNode("api")
+	api.AddStyles(important)
+	db := f.AddNode("db")
+	db.Shape = flowchart.NShapeCircle
+	f.AddEdge(api, db).AddLines("queries")
+	data, _ := json.MarshalIndent(f, "", "  ")
+	fmt.Println(string(data))
+	// and back
+	var g flowchart.Flowchart
+	json.Unmarshal(data, &g)
+	fmt.Print(g.String())
+	//Output:
+	//{
+	//   "version": 1,
+	//   "kind": "flowchart",
+	//   "direction": "LR",
+	//   "classMode": "class",
+	//   "nodeStyles": [
+	//     {
+	//       "id": "important",
+	//       "fill": "#f96",
+	//       "strokeWidth": 1
+	//     }
+	//   ],
+	//   "items": [
+	//     {
+	//       "type": "subgraph",
+	//       "id": "sg1",
+	//       "title": "Services",
+	//       "items": [
+	//         {
+	//           "type": "node",
+	//           "id": "api",
+	//           "shape": "rect",
+	//           "styles": [
+	//             "important"
+	//           ]
+	//         }
+	//       ]
+	//     },
+	//     {
+	//       "type": "node",
+	//       "id": "db",
+	//       "shape": "circle"
+	//     }
+	//   ],
+	//   "edges": [
+	//     {
+	//       "from": "api",
+	//       "to": "db",
+	//       "shape": "arrow",
+	//       "text": [
+	//         "queries"
+	//       ]
+	//     }
+	//   ]
+	//}
+	//graph LR
+	//classDef important fill:#f96
+	//subgraph sg1 ["Services"]
+	//api["api"]
+	//class api important
+	//end
+	//db(("db"))
+	//api -->|"queries"| db
+}
+
+// Helperfunction to emulate a YAML library, which hands the document to
+// UnmarshalYAML via an unmarshal callback.
+func yamlRoundTrip(t *testing.T, f *flowchart.Flowchart) (g *flowchart.Flowchart) {
+	document, err := f.MarshalYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g = &flowchart.Flowchart{}
+	err = g.UnmarshalYAML(func(v interface{}) error {
+		return json.Unmarshal(data, v)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestFlowchart_UnmarshalJSON(t *testing.T) {
+	f, err := flowchart.Parse(`%%{init: {"theme": "dark"}}%%
+	flowchart RL
+	    classDef default fill:#eee
+	    classDef warn stroke:#f00,stroke-width:2px,opacity:0.5
+	    linkStyle default stroke:#999
+	    subgraph outer [Outer]
+	        a(Round)
+	        subgraph inner [Inner]
+	            b((Circle))
+	        end
+	    end
+	    class inner warn
+	    style outer fill:#def
+	    c{Decision} ==>|yes| a
+	    a -.- b
+	    b --- c
+	    style c color:#fff
+	    linkStyle 1 stroke:#0f0,interpolate basis
+	    click c "http://www.example.com" "Example"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.ClassMode = flowchart.ClassesBulk
+	f.GetNode("a").AddStyles(f.NodeStyle("warn"))
+	f.Indent, f.Grouped = "\t", true
+	f.GetSubgraph("inner").Comment = "nested"
+	f.GetNode("b").Comment = "the circle"
+	f.GetEdge(2).Comment = "back\nto c"
+	f.Config.Title = "My chart"
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g flowchart.Flowchart
+	if err = json.Unmarshal(data, &g); err != nil {
+		t.Fatal(err)
+	}
+	if f.String() != g.String() {
+		t.Errorf("JSON round trip changed the Flowchart\n%s\n%s", f, &g)
+	}
+	if h := yamlRoundTrip(t, f); f.String() != h.String() {
+		t.Errorf("YAML round trip changed the Flowchart\n%s\n%s", f, h)
+	}
+	// relations are restored as pointers
+	if g.GetEdge(0).To != g.GetNode("a") || g.GetNode("a").Subgraph() !=
+		g.GetSubgraph("outer") || g.DefaultNodeStyle != g.NodeStyle("default") ||
+		g.Config.Title != "My chart" {
+		t.Error("relations were not restored")
+	}
+	for _, doc := range []string{
+		`{"version": 2, "kind": "flowchart"}`,
+		`{"version": 1, "kind": "gantt"}`,
+		`{"version": 1, "kind": "flowchart", "items": [{"type": "x", "id": "a"}]}`,
+		`{"version": 1, "kind": "flowchart", "items": [{"type": "node", "id": "a"}, {"type": "node", "id": "a"}]}`,
+		`{"version": 1, "kind": "flowchart", "edges": [{"from": "a", "to": "b"}]}`,
+		`{"version": 1, "kind": "flowchart", "items": [{"type": "node", "id": "a", "styles": ["s"]}]}`,
+		`{"version": 1, "kind": "flowchart", "defaultEdgeStyle": "s"}`,
+	} {
+		if err := json.Unmarshal([]byte(doc), &g); err == nil {
+			t.Errorf("%s: expected an error", doc)
+		}
+	}
+	// removed Nodes can't be referenced
+	n := f.RemoveNode("b")
+	f.AddEdge(n, f.GetNode("a"))
+	if _, err := json.Marshal(f); err == nil ||
+		!strings.Contains(err.Error(), "doesn't belong") {
+		t.Errorf("expected an error for the removed Node, got %v", err)
+	}
+}
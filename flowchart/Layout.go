@@ -0,0 +1,646 @@
+package flowchart
+
+import (
+	"math"
+)
+
+// The layout engine used by the in-process renderers. It implements a simple
+// Sugiyama-style layered layout: cycles are broken by reversing edges, Nodes
+// are assigned to ranks (layers), long Edges get dummy nodes for every rank
+// they cross, the nodes of each rank are ordered by barycenters and finally
+// coordinates are assigned. All computation happens in "rank space", where pos
+// is the coordinate along a rank and level the coordinate across ranks, and is
+// converted to x/y honoring Direction.
+// Subgraphs are laid out recursively and placed as a single block in their
+// containing level, so clusters never overlap. Edges are laid out on the level
+// of the innermost Subgraph containing both of their Nodes.
+
+// Sizes used by layout, in pixels for SVG or in characters for text.
+type layoutMetrics struct {
+	nodeSize       func(n *Node) (w, h float64)      // size of a Node
+	labelSize      func(e *Edge) (w, h float64)      // size of an Edge's text
+	titleSize      func(sg *Subgraph) (w, h float64) // size of a Subgraph title
+	nodeSpacing    float64                           // between nodes of a rank
+	rankSpacing    float64                           // between ranks
+	clusterPadding float64                           // around Subgraph contents
+	margin         float64                           // around the whole graph
+}
+
+// A positioned Node, Subgraph block or dummy node of a long Edge.
+type layoutNode struct {
+	node       *Node          // nil for dummies and Subgraph blocks
+	cluster    *layoutCluster // set for Subgraph blocks
+	rank       int
+	order      int
+	x, y, w, h float64 // center and size
+	pos, level float64 // center in rank space
+	in, out    []*layoutNode
+}
+
+// A routed Edge.
+type layoutEdge struct {
+	edge     *Edge
+	from, to *layoutNode   // nodes or blocks on the Edge's level
+	reversed bool          // reversed to break a cycle
+	dummies  []*layoutNode // from source rank to target rank
+	points   [][2]float64  // route from Edge.From to Edge.To
+	labelX   float64       // center of the label
+	labelY   float64
+	labelW   float64
+	labelH   float64
+}
+
+// A positioned Subgraph.
+type layoutCluster struct {
+	subgraph   *Subgraph
+	inner      *layoutLevel
+	titleH     float64
+	x, y, w, h float64 // top left corner and size
+}
+
+// The nodes and Edges of a Flowchart or Subgraph.
+type layoutLevel struct {
+	units         []*layoutNode // Nodes and Subgraph blocks in rendering order
+	edges         []*layoutEdge
+	ranks         [][]*layoutNode
+	width, height float64
+}
+
+// The result of layout.
+type layout struct {
+	direction chartDirection
+	metrics   layoutMetrics
+	nodes     []*layoutNode         // real Nodes in rendering order
+	byNode    map[*Node]*layoutNode // real Nodes for lookup
+	edges     []*layoutEdge         // in Flowchart order
+	clusters  []*layoutCluster      // outer Subgraphs first
+	width     float64
+	height    float64
+}
+
+// Helperfunction to check whether ranks are laid out horizontally.
+func (l *layout) horizontal() (isHorizontal bool) {
+	return l.direction == DirectionLeftRight || l.direction == DirectionRightLeft
+}
+
+// Helperfunction to compute the layout of the whole Flowchart.
+func (fc *Flowchart) layout(m layoutMetrics) (l *layout) {
+	l = &layout{direction: fc.Direction, metrics: m,
+		byNode: make(map[*Node]*layoutNode)}
+	if l.direction == "" {
+		l.direction = DirectionTopDown
+	}
+	edges := make(map[*Edge]*layoutEdge)
+	top, _ := l.layoutItems(fc.items, fc.edges, edges)
+	l.place(top, m.margin, m.margin)
+	l.width = top.width + 2*m.margin
+	l.height = top.height + 2*m.margin
+	for _, e := range fc.edges {
+		if le := edges[e]; le != nil {
+			l.edges = append(l.edges, le)
+		}
+	}
+	l.routeEdges()
+	return l
+}
+
+// Helperfunction to lay out the given items relative to their level. Edges
+// between the items (or their contents) are added to edges. unitOf maps all
+// Nodes inside the items to the unit representing them on this level.
+func (l *layout) layoutItems(items []graphItem, all []*Edge,
+	edges map[*Edge]*layoutEdge) (lvl *layoutLevel,
+	unitOf map[*Node]*layoutNode) {
+	m := l.metrics
+	lvl = &layoutLevel{}
+	unitOf = make(map[*Node]*layoutNode)
+	for _, item := range items {
+		switch v := item.(type) {
+		case *Node:
+			u := &layoutNode{node: v}
+			u.w, u.h = m.nodeSize(v)
+			unitOf[v] = u
+			lvl.units = append(lvl.units, u)
+			l.nodes = append(l.nodes, u)
+			l.byNode[v] = u
+		case *Subgraph:
+			c := &layoutCluster{subgraph: v}
+			l.clusters = append(l.clusters, c)
+			inner, innerUnits := l.layoutItems(v.items, all, edges)
+			titleW, titleH := m.titleSize(v)
+			c.inner, c.titleH = inner, titleH
+			u := &layoutNode{cluster: c}
+			u.w = math.Max(inner.width, titleW) + 2*m.clusterPadding
+			u.h = inner.height + 2*m.clusterPadding + titleH
+			for n := range innerUnits {
+				unitOf[n] = u
+			}
+			lvl.units = append(lvl.units, u)
+		}
+	}
+	for _, e := range all {
+		from, to := unitOf[e.From], unitOf[e.To]
+		if from == nil || to == nil || edges[e] != nil {
+			// not on this level or already laid out on an inner level
+			continue
+		}
+		le := &layoutEdge{edge: e, from: from, to: to}
+		if len(e.Text) > 0 {
+			le.labelW, le.labelH = m.labelSize(e)
+		}
+		edges[e] = le
+		lvl.edges = append(lvl.edges, le)
+	}
+	lvl.breakCycles()
+	lvl.assignRanks()
+	lvl.insertDummies()
+	lvl.orderRanks()
+	l.assignPositions(lvl)
+	l.assignLevels(lvl)
+	l.toCoordinates(lvl)
+	return
+}
+
+////////// ranks ///////////////////////////////////////////////////////////////
+
+// Helperfunction to reverse Edges that close cycles, found by a depth first
+// search in rendering order.
+func (lvl *layoutLevel) breakCycles() {
+	out := make(map[*layoutNode][]*layoutEdge)
+	for _, le := range lvl.edges {
+		out[le.from] = append(out[le.from], le)
+	}
+	const (
+		unvisited = iota
+		active
+		done
+	)
+	state := make(map[*layoutNode]int)
+	var visit func(n *layoutNode)
+	visit = func(n *layoutNode) {
+		state[n] = active
+		for _, le := range out[n] {
+			switch state[le.to] {
+			case unvisited:
+				visit(le.to)
+			case active:
+				le.reversed = le.from != le.to
+			}
+		}
+		state[n] = done
+	}
+	for _, n := range lvl.units {
+		if state[n] == unvisited {
+			visit(n)
+		}
+	}
+}
+
+// Helperfunction to get the source and target of an Edge after breaking
+// cycles.
+func (le *layoutEdge) ends() (source, target *layoutNode) {
+	if le.reversed {
+		return le.to, le.from
+	}
+	return le.from, le.to
+}
+
+// Helperfunction to assign ranks by longest path, sources are moved down
+// towards their successors afterwards to shorten Edges.
+func (lvl *layoutLevel) assignRanks() {
+	preds := make(map[*layoutNode][]*layoutNode)
+	succs := make(map[*layoutNode][]*layoutNode)
+	for _, le := range lvl.edges {
+		source, target := le.ends()
+		if source == target {
+			continue
+		}
+		preds[target] = append(preds[target], source)
+		succs[source] = append(succs[source], target)
+	}
+	// Kahn's algorithm keeping the rendering order for ties
+	indegree := make(map[*layoutNode]int)
+	for n, p := range preds {
+		indegree[n] = len(p)
+	}
+	var topo, queue []*layoutNode
+	for _, n := range lvl.units {
+		if indegree[n] == 0 {
+			queue = append(queue, n)
+		}
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		topo = append(topo, n)
+		for _, s := range succs[n] {
+			indegree[s]--
+			if indegree[s] == 0 {
+				queue = append(queue, s)
+			}
+		}
+	}
+	for _, n := range topo {
+		for _, p := range preds[n] {
+			if p.rank+1 > n.rank {
+				n.rank = p.rank + 1
+			}
+		}
+	}
+	for i := len(topo) - 1; i >= 0; i-- {
+		n := topo[i]
+		if len(preds[n]) > 0 || len(succs[n]) == 0 {
+			continue
+		}
+		min := math.MaxInt32
+		for _, s := range succs[n] {
+			if s.rank < min {
+				min = s.rank
+			}
+		}
+		n.rank = min - 1
+	}
+}
+
+// Helperfunction to add dummy nodes to Edges spanning multiple ranks and to
+// build the ranks.
+func (lvl *layoutLevel) insertDummies() {
+	maxRank := 0
+	for _, n := range lvl.units {
+		if n.rank > maxRank {
+			maxRank = n.rank
+		}
+	}
+	lvl.ranks = make([][]*layoutNode, maxRank+1)
+	for _, n := range lvl.units {
+		lvl.ranks[n.rank] = append(lvl.ranks[n.rank], n)
+	}
+	for _, le := range lvl.edges {
+		source, target := le.ends()
+		if source == target {
+			continue
+		}
+		prev := source
+		for r := source.rank + 1; r < target.rank; r++ {
+			d := &layoutNode{rank: r}
+			le.dummies = append(le.dummies, d)
+			lvl.ranks[r] = append(lvl.ranks[r], d)
+			link(prev, d)
+			prev = d
+		}
+		link(prev, target)
+		if len(le.dummies) > 0 {
+			// the middle dummy reserves the space for the label
+			mid := le.dummies[(len(le.dummies)-1)/2]
+			mid.w, mid.h = le.labelW, le.labelH
+		}
+	}
+	for _, rank := range lvl.ranks {
+		setOrder(rank)
+	}
+}
+
+// Helperfunction to connect two nodes of adjacent ranks.
+func link(from, to *layoutNode) {
+	from.out = append(from.out, to)
+	to.in = append(to.in, from)
+}
+
+////////// order ///////////////////////////////////////////////////////////////
+
+// Helperfunction to order the nodes of each rank, alternating down and up
+// sweeps of the barycenter heuristic. The order with the fewest crossings is
+// kept.
+func (lvl *layoutLevel) orderRanks() {
+	best := lvl.saveOrder()
+	bestCrossings := lvl.crossings()
+	for i := 0; i < 12 && bestCrossings > 0; i++ {
+		if i%2 == 0 {
+			for r := 1; r < len(lvl.ranks); r++ {
+				lvl.sortByBarycenter(r, true)
+			}
+		} else {
+			for r := len(lvl.ranks) - 2; r >= 0; r-- {
+				lvl.sortByBarycenter(r, false)
+			}
+		}
+		if c := lvl.crossings(); c < bestCrossings {
+			best, bestCrossings = lvl.saveOrder(), c
+		}
+	}
+	lvl.ranks = best
+	for _, rank := range lvl.ranks {
+		setOrder(rank)
+	}
+}
+
+// Helperfunction to copy the current order of all ranks.
+func (lvl *layoutLevel) saveOrder() (ranks [][]*layoutNode) {
+	ranks = make([][]*layoutNode, len(lvl.ranks))
+	for r, rank := range lvl.ranks {
+		ranks[r] = append([]*layoutNode{}, rank...)
+	}
+	return
+}
+
+// Helperfunction to update the order field of all nodes in a rank.
+func setOrder(rank []*layoutNode) {
+	for i, n := range rank {
+		n.order = i
+	}
+}
+
+// Helperfunction to stable sort a rank by the mean order of its neighbours in
+// the previous (down) or next (up) rank. Nodes without neighbours keep their
+// position.
+func (lvl *layoutLevel) sortByBarycenter(r int, down bool) {
+	rank := lvl.ranks[r]
+	keys := make(map[*layoutNode]float64, len(rank))
+	for _, n := range rank {
+		neighbours := n.out
+		if down {
+			neighbours = n.in
+		}
+		keys[n] = float64(n.order)
+		if len(neighbours) > 0 {
+			sum := 0.0
+			for _, o := range neighbours {
+				sum += float64(o.order)
+			}
+			keys[n] = sum / float64(len(neighbours))
+		}
+	}
+	// insertion sort is stable and ranks are small
+	for i := 1; i < len(rank); i++ {
+		for j := i; j > 0 && keys[rank[j]] < keys[rank[j-1]]; j-- {
+			rank[j], rank[j-1] = rank[j-1], rank[j]
+		}
+	}
+	setOrder(rank)
+}
+
+// Helperfunction to count the Edge crossings between all adjacent ranks.
+func (lvl *layoutLevel) crossings() (count int) {
+	for _, rank := range lvl.ranks {
+		var pairs [][2]int
+		for _, n := range rank {
+			for _, o := range n.out {
+				pairs = append(pairs, [2]int{n.order, o.order})
+			}
+		}
+		for i := range pairs {
+			for j := i + 1; j < len(pairs); j++ {
+				a, b := pairs[i], pairs[j]
+				if (a[0] < b[0] && a[1] > b[1]) || (a[0] > b[0] && a[1] < b[1]) {
+					count++
+				}
+			}
+		}
+	}
+	return
+}
+
+////////// coordinates /////////////////////////////////////////////////////////
+
+// Helperfunction to get the size of a node along (pos) and across (level)
+// its rank.
+func (l *layout) extent(n *layoutNode) (along, across float64) {
+	if l.horizontal() {
+		return n.h, n.w
+	}
+	return n.w, n.h
+}
+
+// Helperfunction to get the minimum distance between the centers of two
+// adjacent nodes of a rank, dummies need less space.
+func (l *layout) separation(a, b *layoutNode) (sep float64) {
+	alongA, _ := l.extent(a)
+	alongB, _ := l.extent(b)
+	if a.node == nil && a.cluster == nil || b.node == nil && b.cluster == nil {
+		return (alongA+alongB)/2 + l.metrics.nodeSpacing/2
+	}
+	return (alongA+alongB)/2 + l.metrics.nodeSpacing
+}
+
+// Helperfunction to assign pos coordinates, nodes are placed at the mean pos
+// of their neighbours as close as the separations allow.
+func (l *layout) assignPositions(lvl *layoutLevel) {
+	for _, rank := range lvl.ranks {
+		pos := 0.0
+		for i, n := range rank {
+			if i > 0 {
+				pos += l.separation(rank[i-1], n)
+			}
+			n.pos = pos
+		}
+	}
+	place := func(rank []*layoutNode, down bool) {
+		desired := make([]float64, len(rank))
+		for i, n := range rank {
+			neighbours := n.out
+			if down {
+				neighbours = n.in
+			}
+			desired[i] = n.pos
+			if len(neighbours) > 0 {
+				sum := 0.0
+				for _, o := range neighbours {
+					sum += o.pos
+				}
+				desired[i] = sum / float64(len(neighbours))
+			}
+		}
+		offsets := make([]float64, len(rank))
+		for i := 1; i < len(rank); i++ {
+			offsets[i] = offsets[i-1] + l.separation(rank[i-1], rank[i])
+		}
+		for i, p := range isotonic(desired, offsets) {
+			rank[i].pos = p
+		}
+	}
+	for i := 0; i < 8; i++ {
+		for r := 1; r < len(lvl.ranks); r++ {
+			place(lvl.ranks[r], true)
+		}
+		for r := len(lvl.ranks) - 2; r >= 0; r-- {
+			place(lvl.ranks[r], false)
+		}
+	}
+}
+
+// Helperfunction to find the positions closest (least squares) to desired that
+// keep at least the given offsets between each other, using pool adjacent
+// violators.
+func isotonic(desired, offsets []float64) (positions []float64) {
+	type block struct {
+		sum   float64
+		count int
+	}
+	var blocks []block
+	for i, d := range desired {
+		blocks = append(blocks, block{d - offsets[i], 1})
+		for len(blocks) > 1 {
+			last, prev := blocks[len(blocks)-1], blocks[len(blocks)-2]
+			if prev.sum/float64(prev.count) <= last.sum/float64(last.count) {
+				break
+			}
+			blocks = append(blocks[:len(blocks)-2],
+				block{prev.sum + last.sum, prev.count + last.count})
+		}
+	}
+	for _, b := range blocks {
+		for j := 0; j < b.count; j++ {
+			positions = append(positions,
+				b.sum/float64(b.count)+offsets[len(positions)])
+		}
+	}
+	return
+}
+
+// Helperfunction to assign level coordinates to the ranks. The space between
+// two ranks grows with the labels of the Edges between them.
+func (l *layout) assignLevels(lvl *layoutLevel) {
+	labels := make([]float64, len(lvl.ranks))
+	for _, le := range lvl.edges {
+		source, target := le.ends()
+		if target.rank-source.rank != 1 {
+			continue
+		}
+		size := le.labelH
+		if l.horizontal() {
+			size = le.labelW
+		}
+		if size > labels[source.rank] {
+			labels[source.rank] = size
+		}
+	}
+	level := 0.0
+	for r, rank := range lvl.ranks {
+		thickness := 0.0
+		for _, n := range rank {
+			if _, across := l.extent(n); across > thickness {
+				thickness = across
+			}
+		}
+		if r > 0 {
+			level += l.metrics.rankSpacing + labels[r-1]
+		}
+		for _, n := range rank {
+			n.level = level + thickness/2
+		}
+		level += thickness
+	}
+}
+
+// Helperfunction to convert rank space to x/y coordinates according to the
+// direction and move the level's bounding box to the origin.
+func (l *layout) toCoordinates(lvl *layoutLevel) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, rank := range lvl.ranks {
+		for _, n := range rank {
+			switch l.direction {
+			case DirectionLeftRight:
+				n.x, n.y = n.level, n.pos
+			case DirectionRightLeft:
+				n.x, n.y = -n.level, n.pos
+			case DirectionBottomUp:
+				n.x, n.y = n.pos, -n.level
+			default:
+				n.x, n.y = n.pos, n.level
+			}
+			minX, minY = math.Min(minX, n.x-n.w/2), math.Min(minY, n.y-n.h/2)
+			maxX, maxY = math.Max(maxX, n.x+n.w/2), math.Max(maxY, n.y+n.h/2)
+		}
+	}
+	if math.IsInf(minX, 1) {
+		return
+	}
+	for _, rank := range lvl.ranks {
+		for _, n := range rank {
+			n.x, n.y = n.x-minX, n.y-minY
+		}
+	}
+	lvl.width, lvl.height = maxX-minX, maxY-minY
+}
+
+// Helperfunction to move a level and all levels inside of it to its final
+// position and to compute the cluster boxes.
+func (l *layout) place(lvl *layoutLevel, dx, dy float64) {
+	for _, rank := range lvl.ranks {
+		for _, n := range rank {
+			n.x, n.y = n.x+dx, n.y+dy
+			c := n.cluster
+			if c == nil {
+				continue
+			}
+			c.x, c.y, c.w, c.h = n.x-n.w/2, n.y-n.h/2, n.w, n.h
+			pad := l.metrics.clusterPadding
+			l.place(c.inner, c.x+(c.w-c.inner.width)/2, c.y+pad+c.titleH)
+		}
+	}
+}
+
+////////// route Edges /////////////////////////////////////////////////////////
+
+// Helperfunction to compute the route and label position of all Edges. Routes
+// go through the dummy nodes and are clipped at the Node shapes.
+func (l *layout) routeEdges() {
+	for _, le := range l.edges {
+		from, to := l.byNode[le.edge.From], l.byNode[le.edge.To]
+		if from == to {
+			// loop at the side of the Node
+			x, y := from.x+from.w/2, from.y
+			d := math.Max(from.h/4, 1)
+			le.points = [][2]float64{{x, y - d}, {x + 2*d, y - d},
+				{x + 2*d, y + d}, {x, y + d}}
+			le.labelX, le.labelY = x+2*d+le.labelW/2, y
+			continue
+		}
+		points := [][2]float64{{from.x, from.y}}
+		for i := range le.dummies {
+			d := le.dummies[i]
+			if le.reversed {
+				d = le.dummies[len(le.dummies)-1-i]
+			}
+			points = append(points, [2]float64{d.x, d.y})
+		}
+		points = append(points, [2]float64{to.x, to.y})
+		points[0] = clip(from, points[1])
+		points[len(points)-1] = clip(to, points[len(points)-2])
+		le.points = points
+		if len(le.dummies) > 0 {
+			mid := le.dummies[(len(le.dummies)-1)/2]
+			le.labelX, le.labelY = mid.x, mid.y
+		} else {
+			le.labelX = (points[0][0] + points[1][0]) / 2
+			le.labelY = (points[0][1] + points[1][1]) / 2
+		}
+	}
+}
+
+// Helperfunction to find the point where the line from the center of n to p
+// leaves the shape of n.
+func clip(n *layoutNode, p [2]float64) (point [2]float64) {
+	dx, dy := p[0]-n.x, p[1]-n.y
+	if dx == 0 && dy == 0 {
+		return p
+	}
+	t := math.Inf(1)
+	switch n.node.Shape {
+	case NShapeCircle:
+		t = n.w / 2 / math.Hypot(dx, dy)
+	case NShapeRhombus:
+		t = 1 / (math.Abs(dx)/(n.w/2) + math.Abs(dy)/(n.h/2))
+	default:
+		if dx != 0 {
+			t = n.w / 2 / math.Abs(dx)
+		}
+		if dy != 0 {
+			t = math.Min(t, n.h/2/math.Abs(dy))
+		}
+	}
+	if t > 1 {
+		return p
+	}
+	return [2]float64{n.x + dx*t, n.y + dy*t}
+}
@@ -24,12 +24,19 @@ const (
 // not create instances directly. Already defined IDs can be looked up via
 // Flowchart's GetNode method or iterated over via its ListNodes method.
 type Node struct {
-	id       string
-	Shape    nodeShape  // The shape of this Node.
-	Text     []string   // The body text, ID if no text is added.
-	Link     string     // Optional URL for a click-hook.
-	LinkText string     // Optional tooltip for the link.
-	Style    *NodeStyle // Optional CSS style.
+	id        string
+	flowchart *Flowchart   // top lvl pointer
+	subgraph  *Subgraph    // containing Subgraph, nil for top level
+	Shape     nodeShape    // The shape of this Node.
+	Text      []string     // The body text, ID if no text is added.
+	Link      string       // Optional URL for a click-hook.
+	LinkText  string       // Optional tooltip for the link.
+	Styles    []*NodeStyle // Optional CSS styles (classes).
+	inline    *NodeStyle   // Optional one-off CSS style.
+	Comment   string       // Optional %% comment rendered above the Node.
+	// Deprecated: Style is kept for compatibility, use Styles instead. If set,
+	// it is rendered as the first entry of Styles.
+	Style *NodeStyle
 }
 
 // ID provides access to the Node's readonly field id.
@@ -37,32 +44,76 @@ func (n *Node) ID() (id string) {
 	return n.id
 }
 
+// Subgraph provides access to the Subgraph that contains this Node. If this
+// Node is contained in the top level Flowchart itself, nil is returned.
+func (n *Node) Subgraph() (containingSubgraph *Subgraph) {
+	return n.subgraph
+}
+
 // Implements graphItem, see String() for further details.
-func (n *Node) renderGraph() string {
+func (n *Node) writeGraph(w *graphWriter) {
+	w.comment(n.Comment)
 	textbox := n.id
 	if len(n.Text) > 0 {
-		textbox = strings.Join(n.Text, "<br/>")
+		textbox = escapeQuotes(strings.Join(n.Text, "<br/>"))
 	}
-	text := n.id + fmt.Sprintf(string(n.Shape), textbox) + "\n"
-	if n.Style != nil {
-		text += fmt.Sprintf("class %s %s\n", n.id, n.Style.id)
+	w.startLine()
+	w.WriteString(n.id)
+	fmt.Fprintf(w, string(n.Shape), textbox)
+	if n.inlineClass() {
+		w.WriteString(":::" + n.styles()[0].id)
 	}
-	if n.Link != "" {
-		linktxt := n.Link
-		if n.LinkText != "" {
-			linktxt = n.LinkText
-		}
-		text += fmt.Sprintf("click %s \"%s\" \"%s\"\n",
-			n.id, n.Link, linktxt)
+	w.WriteString("\n")
+	if !w.grouped {
+		n.writeStyles(w)
+		n.writeClick(w)
 	}
-	return text
+}
+
+// Helperfunction to check whether the class is rendered using the :::class
+// shorthand.
+func (n *Node) inlineClass() (inline bool) {
+	return n.flowchart.classMode() == ClassesInline && len(n.styles()) == 1
+}
+
+// Helperfunction to get the NodeStyles including the deprecated Style member,
+// which is the first one if set.
+func (n *Node) styles() (styles []*NodeStyle) {
+	if n.Style == nil || len(n.Styles) > 0 && n.Styles[0] == n.Style {
+		return n.Styles
+	}
+	return append([]*NodeStyle{n.Style}, n.Styles...)
+}
+
+// Implements graphItem, renders the class and style lines of the Node.
+func (n *Node) writeStyles(w *graphWriter) {
+	if n.flowchart.classMode() != ClassesBulk && !n.inlineClass() {
+		writeClasses(w, n.id, n.styles())
+	}
+	writeInlineStyle(w, n.id, n.inline)
+}
+
+// Helperfunction to render the click line of the Node, if any.
+func (n *Node) writeClick(w *graphWriter) {
+	if n.Link == "" {
+		return
+	}
+	linktxt := n.Link
+	if n.LinkText != "" {
+		linktxt = n.LinkText
+	}
+	w.startLine()
+	fmt.Fprintf(w, "click %s \"%s\" \"%s\"\n", n.id, n.Link, linktxt)
 }
 
 // String renders this graph element to a node definition line.
-// If Style member is set an additional class line will be created.
+// If Styles member is set an additional class line will be created, unless
+// the Flowchart's ClassMode defines otherwise.
+// If an InlineStyle is defined an additional style line will be created.
 // If Link member is set an additional click line will be created.
+// If Comment member is set it is rendered as %% lines above.
 func (n *Node) String() (renderedElement string) {
-	return n.renderGraph()
+	return renderString("", n.writeGraph)
 }
 
 // AddLines adds one or more lines of text to the Text member.
@@ -71,3 +122,24 @@ func (n *Node) String() (renderedElement string) {
 func (n *Node) AddLines(lines ...string) {
 	n.Text = append(n.Text, lines...)
 }
+
+// AddStyles adds one or more NodeStyles to the Styles member.
+// Each NodeStyle is rendered as an additional class of this Node.
+func (n *Node) AddStyles(styles ...*NodeStyle) {
+	n.Styles = append(n.Styles, styles...)
+}
+
+// InlineStyle is used to create or access this Node's one-off NodeStyle.
+// Unlike NodeStyles assigned via Styles, it renders to a style line for this
+// Node only instead of a named classDef.
+func (n *Node) InlineStyle() (style *NodeStyle) {
+	if n.inline == nil {
+		n.inline = &NodeStyle{StrokeWidth: 1}
+	}
+	return n.inline
+}
+
+// RemoveInlineStyle drops this Node's one-off NodeStyle, if any.
+func (n *Node) RemoveInlineStyle() {
+	n.inline = nil
+}
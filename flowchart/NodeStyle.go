@@ -3,27 +3,63 @@ package flowchart
 import (
 	"fmt"
 	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
 )
 
-// A NodeStyle is used to add CSS to a Node. It renders to a classDef line.
+// A NodeStyle is used to add CSS to a Node or Subgraph. It renders to a
+// classDef line.
 // Retrieve an instance of NodeStyle via Flowchart's NodeStyle method, do not
-// create instances directly.
+// create instances directly. One-off styles that render to a style line are
+// retrieved via Node's or Subgraph's InlineStyle method.
 type NodeStyle struct {
-	id          string
-	Fill        htmlColor // renders to something like fill:#f9f
-	Stroke      htmlColor // renders to something like stroke:#333
-	StrokeWidth uint8     // renders to something like stroke-width:2px
-	StrokeDash  uint8     // renders to something like stroke-dasharray:5px
-	More        string    // more styles, e.g.: stroke:#333,stroke-width:1px
+	id            string
+	Fill          mermaidgen.Color `json:"fill,omitempty" yaml:"fill,omitempty"`                   // renders to something like fill:#f9f
+	Stroke        mermaidgen.Color `json:"stroke,omitempty" yaml:"stroke,omitempty"`               // renders to something like stroke:#333
+	StrokeWidth   uint8            `json:"strokeWidth,omitempty" yaml:"strokeWidth,omitempty"`     // renders to something like stroke-width:2px
+	StrokeDash    uint8            `json:"strokeDash,omitempty" yaml:"strokeDash,omitempty"`       // renders to something like stroke-dasharray:5px
+	StrokeLinecap strokeLinecap    `json:"strokeLinecap,omitempty" yaml:"strokeLinecap,omitempty"` // renders to something like stroke-linecap:round
+	Color         mermaidgen.Color `json:"color,omitempty" yaml:"color,omitempty"`                 // renders to something like color:#333
+	FontSize      uint8            `json:"fontSize,omitempty" yaml:"fontSize,omitempty"`           // renders to something like font-size:12px
+	FontWeight    fontWeight       `json:"fontWeight,omitempty" yaml:"fontWeight,omitempty"`       // renders to something like font-weight:bold
+	FontFamily    string           `json:"fontFamily,omitempty" yaml:"fontFamily,omitempty"`       // renders to something like font-family:monospace
+	Opacity       *float64         `json:"opacity,omitempty" yaml:"opacity,omitempty"`             // renders to something like opacity:0.5
+	Rx            uint8            `json:"rx,omitempty" yaml:"rx,omitempty"`                       // renders to something like rx:5px
+	Ry            uint8            `json:"ry,omitempty" yaml:"ry,omitempty"`                       // renders to something like ry:5px
+	Padding       uint8            `json:"padding,omitempty" yaml:"padding,omitempty"`             // renders to something like padding:10px
+	More          string           `json:"more,omitempty" yaml:"more,omitempty"`                   // more styles, e.g.: stroke:#333,stroke-width:1px
 }
 
 // ID provides access to the NodeStyle's readonly field id.
+// For inline styles the ID is empty.
 func (ns *NodeStyle) ID() (id string) {
 	return ns.id
 }
 
 // String renders this graph element to a classDef line.
 func (ns *NodeStyle) String() (renderedElement string) {
+	return fmt.Sprintf("classDef %s %s\n", ns.id, ns.definitions())
+}
+
+// Validate checks the NodeStyle's values and returns an error describing the
+// first invalid value found, e.g. an Opacity out of range or a FontFamily
+// containing characters that would break the mermaid syntax.
+func (ns *NodeStyle) Validate() (err error) {
+	if err = validateColor("Fill", ns.Fill); err != nil {
+		return
+	}
+	if err = validateColor("Stroke", ns.Stroke); err != nil {
+		return
+	}
+	if err = validateColor("Color", ns.Color); err != nil {
+		return
+	}
+	return validateText(ns.FontWeight, ns.FontFamily, ns.Opacity,
+		ns.StrokeLinecap, ns.More)
+}
+
+// Helperfunction to deduplicate code, renders the CSS definitions only.
+func (ns *NodeStyle) definitions() (definitions string) {
 	styles := []string{}
 	if ns.Fill != "" {
 		styles = append(styles, "fill:"+string(ns.Fill))
@@ -39,14 +75,69 @@ func (ns *NodeStyle) String() (renderedElement string) {
 		styles = append(styles, fmt.Sprintf(`stroke-dasharray:%dpx`,
 			ns.StrokeDash))
 	}
+	if ns.StrokeLinecap != "" {
+		styles = append(styles, "stroke-linecap:"+string(ns.StrokeLinecap))
+	}
+	if ns.Color != "" {
+		styles = append(styles, "color:"+string(ns.Color))
+	}
+	if ns.FontSize != 0 {
+		styles = append(styles, fmt.Sprintf(`font-size:%dpx`, ns.FontSize))
+	}
+	if ns.FontWeight != "" {
+		styles = append(styles, "font-weight:"+string(ns.FontWeight))
+	}
+	if ns.FontFamily != "" {
+		styles = append(styles, "font-family:"+styleValue(ns.FontFamily))
+	}
+	if ns.Opacity != nil {
+		styles = append(styles, fmt.Sprintf(`opacity:%g`, *ns.Opacity))
+	}
+	if ns.Rx != 0 {
+		styles = append(styles, fmt.Sprintf(`rx:%dpx`, ns.Rx))
+	}
+	if ns.Ry != 0 {
+		styles = append(styles, fmt.Sprintf(`ry:%dpx`, ns.Ry))
+	}
+	if ns.Padding != 0 {
+		styles = append(styles, fmt.Sprintf(`padding:%dpx`, ns.Padding))
+	}
 	if ns.More != "" {
 		styles = append(styles, ns.More)
 	}
-	definitions := strings.Join(styles, ",")
+	definitions = strings.Join(styles, ",")
 	if definitions == "" {
 		// neutral element as a fallback to ensure empty classDefs don't break
 		// the mermaid syntax
 		definitions = fmt.Sprintf(`stroke-width:%dpx`, ns.StrokeWidth)
 	}
-	return fmt.Sprintf("classDef %s %s\n", ns.id, definitions)
+	return definitions
+}
+
+// Helperfunction to deduplicate code, writes a class line assigning all given
+// NodeStyles to the given comma separated IDs. Writes nothing if there are no
+// styles.
+func writeClasses(w *graphWriter, ids string, styles []*NodeStyle) {
+	if len(styles) == 0 {
+		return
+	}
+	w.startLine()
+	w.WriteString("class " + ids + " ")
+	for i, s := range styles {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		w.WriteString(s.id)
+	}
+	w.WriteByte('\n')
+}
+
+// Helperfunction to deduplicate code, writes a style line for a one-off
+// NodeStyle of the given ID. Writes nothing if there is no style.
+func writeInlineStyle(w *graphWriter, id string, style *NodeStyle) {
+	if style == nil {
+		return
+	}
+	w.startLine()
+	w.WriteString("style " + id + " " + style.definitions() + "\n")
 }
@@ -2,7 +2,9 @@ package flowchart_test
 
 import (
 	"fmt"
+	"testing"
 
+	"github.com/Heiko-san/mermaidgen"
 	"github.com/Heiko-san/mermaidgen/flowchart"
 )
 
@@ -15,9 +17,9 @@ func ExampleNodeStyle() {
 	ns1 := f.NodeStyle("ns1")
 	ns2 := f.NodeStyle("ns2")
 	// and assign them to Nodes
-	n1.Style = ns1
+	n1.AddStyles(ns1)
 	// you can also lookup previously defined NodeStyles
-	n2.Style = f.NodeStyle("ns2")
+	n2.AddStyles(f.NodeStyle("ns2"))
 	// there are some CSS shortcuts
 	ns2.Fill = flowchart.ColorYellow
 	ns2.Stroke = flowchart.ColorBlue
@@ -44,3 +46,158 @@ func ExampleNodeStyle_privateFields() {
 	fmt.Println(ns.ID())
 	//Output: this_is_my_id
 }
+
+// Assigning multiple NodeStyles to Nodes and Subgraphs
+func ExampleNodeStyle_multipleClasses() {
+	f := flowchart.NewFlowchart()
+	ns1 := f.NodeStyle("ns1")
+	ns1.Fill = flowchart.ColorYellow
+	ns2 := f.NodeStyle("ns2")
+	ns2.StrokeWidth = 3
+	sg := f.AddSubgraph("sg1")
+	n1 := sg.AddNode("n1")
+	// Nodes and Subgraphs can have any number of NodeStyles
+	n1.AddStyles(ns1, ns2)
+	sg.AddStyles(ns2)
+	// the default NodeStyle applies to all Nodes without explicit classes
+	f.DefaultNodeStyle = f.NodeStyle("base")
+	f.DefaultNodeStyle.Stroke = flowchart.ColorBlue
+	fmt.Print(f)
+	//Output:
+	//graph TB
+	//classDef default stroke:#00f
+	//classDef ns1 fill:#ff0
+	//classDef ns2 stroke-width:3px
+	//subgraph sg1 ["sg1"]
+	//n1["n1"]
+	//class n1 ns1,ns2
+	//end
+	//class sg1 ns2
+}
+
+// Choosing how classes are assigned to Nodes and Subgraphs
+func ExampleNodeStyle_classModes() {
+	f := flowchart.NewFlowchart()
+	ns1 := f.NodeStyle("ns1")
+	ns2 := f.NodeStyle("ns2")
+	sg := f.AddSubgraph("sg1")
+	sg.AddStyles(ns1)
+	sg.AddNode("n1").AddStyles(ns1)
+	f.AddNode("n2").AddStyles(ns1, ns2)
+	f.AddNode("n3").AddStyles(ns2)
+	// the :::class shorthand is used for Nodes with a single NodeStyle
+	f.ClassMode = flowchart.ClassesInline
+	fmt.Print(f)
+	// one class line per NodeStyle keeps large graphs compact
+	f.ClassMode = flowchart.ClassesBulk
+	fmt.Print(f)
+	//Output:
+	//graph TB
+	//classDef ns1 stroke-width:1px
+	//classDef ns2 stroke-width:1px
+	//subgraph sg1 ["sg1"]
+	//n1["n1"]:::ns1
+	//end
+	//class sg1 ns1
+	//n2["n2"]
+	//class n2 ns1,ns2
+	//n3["n3"]:::ns2
+	//graph TB
+	//classDef ns1 stroke-width:1px
+	//classDef ns2 stroke-width:1px
+	//subgraph sg1 ["sg1"]
+	//n1["n1"]
+	//end
+	//n2["n2"]
+	//n3["n3"]
+	//class n1,sg1,n2 ns1
+	//class n2,n3 ns2
+}
+
+// Typed CSS properties and one-off styles
+func ExampleNodeStyle_typedProperties() {
+	f := flowchart.NewFlowchart()
+	ns := f.NodeStyle("ns1")
+	ns.Color = flowchart.ColorWhite
+	ns.FontSize = 14
+	ns.FontWeight = flowchart.FontWeightBold
+	// commas of font family lists are escaped
+	ns.FontFamily = "Fira Code, monospace"
+	opacity := 0.8
+	ns.Opacity = &opacity
+	ns.Rx, ns.Ry = 5, 5
+	ns.Padding = 10
+	n1 := f.AddNode("n1")
+	n1.AddStyles(ns)
+	// a style line just for this Node, no classDef needed
+	n1.InlineStyle().Fill = flowchart.ColorRed
+	n1.InlineStyle().StrokeLinecap = flowchart.LinecapRound
+	// styles can be validated before rendering
+	fmt.Println(ns.Validate(), n1.InlineStyle().Validate())
+	fmt.Print(f)
+	//Output:
+	//<nil> <nil>
+	//graph TB
+	//classDef ns1 color:#fff,font-size:14px,font-weight:bold,font-family:Fira Code\, monospace,opacity:0.8,rx:5px,ry:5px,padding:10px
+	//n1["n1"]
+	//class n1 ns1
+	//style n1 fill:#f00,stroke-linecap:round
+}
+
+func TestNodeStyle_Validate(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	opacity := 1.5
+	for name, modify := range map[string]func(ns *flowchart.NodeStyle){
+		"Fill":          func(ns *flowchart.NodeStyle) { ns.Fill = "#12" },
+		"Stroke":        func(ns *flowchart.NodeStyle) { ns.Stroke = "red;" },
+		"Color":         func(ns *flowchart.NodeStyle) { ns.Color = "#ggg" },
+		"FontWeight":    func(ns *flowchart.NodeStyle) { ns.FontWeight = "fat" },
+		"FontFamily":    func(ns *flowchart.NodeStyle) { ns.FontFamily = "a;b" },
+		"Opacity":       func(ns *flowchart.NodeStyle) { ns.Opacity = &opacity },
+		"StrokeLinecap": func(ns *flowchart.NodeStyle) { ns.StrokeLinecap = "x" },
+		"More":          func(ns *flowchart.NodeStyle) { ns.More = "a:b;c:d" },
+	} {
+		ns := f.NodeStyle(name)
+		modify(ns)
+		if err := ns.Validate(); err == nil {
+			t.Errorf("no error returned: %s invalid", name)
+		}
+	}
+	ns := f.NodeStyle("valid")
+	ns.Fill = "rgb(10, 20, 30)"
+	ns.Stroke = "#abcdef"
+	ns.Color = "rebeccapurple"
+	ns.FontWeight = flowchart.FontWeight600
+	if err := ns.Validate(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+// Using mermaidgen.Color for fills and readable text colors
+func ExampleNodeStyle_colors() {
+	f := flowchart.NewFlowchart()
+	ns := f.NodeStyle("ns1")
+	ns.Fill = mermaidgen.HSL(210, 0.5, 0.4)
+	ns.Stroke = ns.Fill.Darken(0.2)
+	ns.Color = ns.Fill.ReadableTextColor()
+	fmt.Print(ns)
+	//Output:
+	//classDef ns1 fill:#336699,stroke:#19334d,color:#fff
+}
+
+func TestFlowchart_DefaultNodeStyle(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	f.DefaultNodeStyle = f.NodeStyle("base")
+	f.DefaultNodeStyle.Stroke = flowchart.ColorBlue
+	f.AddNode("n1")
+	if f.String() != "graph TB\nclassDef default stroke:#00f\nn1[\"n1\"]\n" {
+		t.Errorf("unexpected Flowchart\n%s", f)
+	}
+	// explicitly assigned it is needed under its own name
+	f.AddNode("n2").AddStyles(f.DefaultNodeStyle)
+	expected := "graph TB\nclassDef default stroke:#00f\n" +
+		"classDef base stroke:#00f\nn1[\"n1\"]\nn2[\"n2\"]\nclass n2 base\n"
+	if f.String() != expected {
+		t.Errorf("unexpected Flowchart\n%s", f)
+	}
+}
@@ -2,6 +2,7 @@ package flowchart_test
 
 import (
 	"fmt"
+	"testing"
 
 	"github.com/Heiko-san/mermaidgen/flowchart"
 )
@@ -21,9 +22,9 @@ func ExampleNode() {
 	n2.Link = "http://www.example.com"
 	n2.LinkText = "tooltip"
 	// CSS styling (see NodeStyle for more details)
-	n2.Style = f.NodeStyle("ns1")
+	n2.AddStyles(f.NodeStyle("ns1"))
 	// Previously defined Nodes can be looked up
-	f.GetNode("n2").Style.Fill = flowchart.ColorCyan
+	f.GetNode("n2").Styles[0].Fill = flowchart.ColorCyan
 	fmt.Print(f)
 	//Output:
 	//graph TB
@@ -42,3 +43,18 @@ func ExampleNode_privateFields() {
 	fmt.Println(n1.ID())
 	//Output: this_is_my_id
 }
+
+func TestNode_Style(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	n := f.AddNode("n1")
+	n.Style = f.NodeStyle("ns1")
+	n.AddStyles(f.NodeStyle("ns2"))
+	if n.String() != "n1[\"n1\"]\nclass n1 ns1,ns2\n" {
+		t.Errorf("unexpected Node\n%s", n)
+	}
+	n.Styles = []*flowchart.NodeStyle{n.Style}
+	f.ClassMode = flowchart.ClassesInline
+	if n.String() != "n1[\"n1\"]:::ns1\n" {
+		t.Errorf("unexpected Node\n%s", n)
+	}
+}
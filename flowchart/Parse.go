@@ -0,0 +1,649 @@
+package flowchart
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
+)
+
+// regular expressions used by the parser
+var (
+	parseHeader    = regexp.MustCompile(`^(?:graph|flowchart)(?:\s+(TB|TD|BT|RL|LR))?$`)
+	parseSubgraph  = regexp.MustCompile(`^([^\s\[]+)\s*\[\s*(.*?)\s*\]$`)
+	parseID        = regexp.MustCompile(`^[\pL\pN_]+(?:[-.][\pL\pN_]+)*`)
+	parseClassUse  = regexp.MustCompile(`^:::([\pL\pN_-]+)`)
+	parseLink      = regexp.MustCompile(`^(?:-{2,}>|-\.+->|={2,}>|-{3,}|-\.+-|={3,})`)
+	parseLinkText  = regexp.MustCompile(`^(--|==|-\.)\s+(.*?)\s+(-{2,}>|={2,}>|\.-+>|-{3,}|={3,}|\.-+)`)
+	parseLinkLabel = regexp.MustCompile(`^\|([^|]*)\|`)
+	parseClick     = regexp.MustCompile(`^click\s+(\S+)\s+(?:href\s+)?"([^"]*)"(?:\s+"([^"]*)")?(?:\s+_\w+)?$`)
+	parseLineBreak = regexp.MustCompile(`<br\s*/?>`)
+)
+
+// Node shapes in the order they need to be checked, shapes that Node doesn't
+// support are listed to provide meaningful errors.
+var parseShapes = []struct {
+	open, close string
+	shape       nodeShape
+}{
+	{`(((`, `)))`, ""},
+	{`((`, `))`, NShapeCircle},
+	{`([`, `])`, ""},
+	{`(`, `)`, NShapeRoundRect},
+	{`[[`, `]]`, ""},
+	{`[(`, `)]`, ""},
+	{`[/`, `]`, ""},
+	{`[\`, `]`, ""},
+	{`[`, `]`, NShapeRect},
+	{`{{`, `}}`, ""},
+	{`{`, `}`, NShapeRhombus},
+	{`>`, `]`, NShapeFlagLeft},
+}
+
+// Deferred statements, applied after all Nodes and Edges are known.
+type parseDeferred struct {
+	line int
+	kind string
+	ids  string
+	args string
+}
+
+// Group comments written by Flowcharts with Grouped set, they aren't attached
+// to items.
+var parseGroupComments = map[string]bool{"nodes": true, "edges": true,
+	"styles": true, "interactions": true}
+
+// Internal state of the parser.
+type parser struct {
+	fc       *Flowchart
+	stack    []*Subgraph
+	deferred []parseDeferred
+	comments []string
+	line     int
+}
+
+// Parse creates a Flowchart from mermaid code, e.g. to validate or modify
+// existing diagrams. It supports the syntax modelled by this package: Node
+// shapes, Edges (including chains and &), Subgraphs, classDef, class, :::,
+// style, linkStyle and click statements with URLs, as well as a leading
+// frontmatter or init directive which is stored as Config. %% comment lines
+// directly above a Subgraph, a Node definition or an Edge are stored as the
+// item's Comment, other comments are dropped. Unsupported syntax,
+// such as Node shapes without a constant in this package, yields an error
+// containing the line number.
+func Parse(code string) (newFlowchart *Flowchart, err error) {
+	body, config, err := mermaidgen.SplitConfig(code)
+	if err != nil {
+		return nil, fmt.Errorf("Parse: %s", err)
+	}
+	// line numbers count the lines of the configuration too
+	offset := strings.Count(code[:len(code)-len(body)], "\n")
+	p := &parser{fc: NewFlowchart()}
+	p.fc.Config = config
+	header := false
+	for i, line := range strings.Split(body, "\n") {
+		p.line = offset + i + 1
+		p.comment(line)
+		for _, stmt := range splitStatements(line) {
+			if !header {
+				m := parseHeader.FindStringSubmatch(stmt)
+				if m == nil {
+					return nil, p.errorf("expected graph or flowchart header")
+				}
+				p.setDirection(m[1])
+				header = true
+				continue
+			}
+			if err = p.statement(stmt); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !header {
+		return nil, fmt.Errorf("Parse: no graph or flowchart header found")
+	}
+	if len(p.stack) > 0 {
+		return nil, fmt.Errorf("Parse: subgraph %s not closed", p.stack[0].id)
+	}
+	if err = p.applyDeferred(); err != nil {
+		return nil, err
+	}
+	return p.fc, nil
+}
+
+// ParseLiveURL decodes a live editor URL via mermaidgen.DecodeLiveURL and
+// parses the contained code via Parse. The Config from the URL is used unless
+// the code itself contains one.
+func ParseLiveURL(liveURL string) (newFlowchart *Flowchart, err error) {
+	code, config, err := mermaidgen.DecodeLiveURL(liveURL)
+	if err != nil {
+		return nil, err
+	}
+	newFlowchart, err = Parse(code)
+	if err != nil {
+		return nil, err
+	}
+	if newFlowchart.Config == nil {
+		newFlowchart.Config = config
+	}
+	return
+}
+
+// Helperfunction to prefix errors with the current line number.
+func (p *parser) errorf(format string, args ...interface{}) (err error) {
+	return fmt.Errorf("Parse: line %d: %s", p.line,
+		fmt.Sprintf(format, args...))
+}
+
+// Helperfunction to collect the comments above a statement, empty lines
+// discard them.
+func (p *parser) comment(line string) {
+	line = strings.TrimSpace(line)
+	switch {
+	case line == "":
+		p.comments = nil
+	case strings.HasPrefix(line, "%%"):
+		text := strings.TrimSpace(line[2:])
+		if !parseGroupComments[text] {
+			p.comments = append(p.comments, text)
+		}
+	}
+}
+
+// Helperfunction to split a line into statements separated by ; and to drop
+// comments, quoted text is respected.
+func splitStatements(line string) (statements []string) {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "%%") {
+		return nil
+	}
+	quoted := false
+	start := 0
+	for i, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ';' && !quoted:
+			if s := strings.TrimSpace(line[start:i]); s != "" {
+				statements = append(statements, s)
+			}
+			start = i + 1
+		}
+	}
+	if s := strings.TrimSpace(line[start:]); s != "" {
+		statements = append(statements, s)
+	}
+	return
+}
+
+// Helperfunction to set the Flowchart's Direction, TD is an alias for TB.
+func (p *parser) setDirection(direction string) {
+	switch direction {
+	case "", "TD":
+		p.fc.Direction = DirectionTopDown
+	default:
+		p.fc.Direction = chartDirection(direction)
+	}
+}
+
+// Helperfunction to dispatch a single statement.
+func (p *parser) statement(stmt string) (err error) {
+	comment := strings.Join(p.comments, "\n")
+	p.comments = nil
+	keyword := strings.Fields(stmt)[0]
+	args := strings.TrimSpace(strings.TrimPrefix(stmt, keyword))
+	switch keyword {
+	case "subgraph":
+		return p.subgraph(args, comment)
+	case "end":
+		if len(p.stack) == 0 {
+			return p.errorf("end without subgraph")
+		}
+		p.stack = p.stack[:len(p.stack)-1]
+		return nil
+	case "direction":
+		// Subgraph directions aren't modelled, the Flowchart's is used
+		return nil
+	case "classDef", "class", "style", "linkStyle":
+		fields := strings.Fields(args)
+		if len(fields) < 2 {
+			return p.errorf("%s needs an ID and a definition", keyword)
+		}
+		p.deferred = append(p.deferred, parseDeferred{line: p.line,
+			kind: keyword, ids: fields[0],
+			args: strings.TrimSpace(strings.TrimPrefix(args, fields[0]))})
+		return nil
+	case "click":
+		m := parseClick.FindStringSubmatch(stmt)
+		if m == nil {
+			return p.errorf("only click statements with URLs are supported")
+		}
+		p.deferred = append(p.deferred, parseDeferred{line: p.line,
+			kind: keyword, ids: m[1], args: m[2] + "\n" + m[3]})
+		return nil
+	}
+	return p.chain(stmt, comment)
+}
+
+// Helperfunction to parse subgraph statements.
+func (p *parser) subgraph(args, comment string) (err error) {
+	id, title := args, ""
+	if m := parseSubgraph.FindStringSubmatch(args); m != nil {
+		id, title = m[1], unquote(m[2])
+	}
+	if id == "" {
+		return p.errorf("subgraph without ID")
+	}
+	if title == id {
+		title = ""
+	}
+	var sg *Subgraph
+	if len(p.stack) == 0 {
+		sg = p.fc.AddSubgraph(id)
+	} else {
+		sg = p.stack[len(p.stack)-1].AddSubgraph(id)
+	}
+	if sg == nil {
+		return p.errorf("duplicate subgraph %s", id)
+	}
+	sg.Title, sg.Comment = title, comment
+	p.stack = append(p.stack, sg)
+	return nil
+}
+
+// Helperfunction to parse node definitions and edge chains. The comment is
+// attached to the first Edge or to the Node if there are no Edges.
+func (p *parser) chain(stmt, comment string) (err error) {
+	var groups [][]*Node
+	var links []*Edge
+	rest := stmt
+	for {
+		group, r, err := p.nodeGroup(rest)
+		if err != nil {
+			return err
+		}
+		groups = append(groups, group)
+		rest = strings.TrimSpace(r)
+		if rest == "" {
+			break
+		}
+		link, r, err := p.link(rest)
+		if err != nil {
+			return err
+		}
+		links = append(links, link)
+		rest = strings.TrimSpace(r)
+	}
+	if len(links) == 0 && comment != "" {
+		groups[0][0].Comment = comment
+	}
+	for i, link := range links {
+		for _, from := range groups[i] {
+			for _, to := range groups[i+1] {
+				e := p.fc.AddEdge(from, to)
+				e.Shape = link.Shape
+				e.AddLines(link.Text...)
+				e.Comment, comment = comment, ""
+			}
+		}
+	}
+	return nil
+}
+
+// Helperfunction to parse Nodes separated by &.
+func (p *parser) nodeGroup(s string) (nodes []*Node, rest string, err error) {
+	for {
+		n, r, err := p.node(s)
+		if err != nil {
+			return nil, "", err
+		}
+		nodes = append(nodes, n)
+		r = strings.TrimSpace(r)
+		if !strings.HasPrefix(r, "&") {
+			return nodes, r, nil
+		}
+		s = strings.TrimSpace(r[1:])
+	}
+}
+
+// Helperfunction to parse a Node reference with optional shape and class.
+func (p *parser) node(s string) (n *Node, rest string, err error) {
+	id := parseID.FindString(s)
+	if id == "" {
+		return nil, "", p.errorf("expected node ID at %q", s)
+	}
+	rest = s[len(id):]
+	n = p.fc.GetNode(id)
+	if n == nil {
+		if p.fc.GetSubgraph(id) != nil {
+			return nil, "", p.errorf("edges to subgraphs are not supported")
+		}
+		if len(p.stack) == 0 {
+			n = p.fc.AddNode(id)
+		} else {
+			n = p.stack[len(p.stack)-1].AddNode(id)
+		}
+	} else if n.Subgraph() == nil && len(p.stack) > 0 {
+		// like mermaid, mentioning a node in a subgraph moves it there
+		p.fc.MoveNode(id, p.stack[len(p.stack)-1])
+	}
+	for _, shape := range parseShapes {
+		if !strings.HasPrefix(rest, shape.open) {
+			continue
+		}
+		if shape.shape == "" {
+			return nil, "", p.errorf("unsupported node shape %s%s", shape.open,
+				shape.close)
+		}
+		text, r, err := p.shapeText(rest[len(shape.open):], shape.close)
+		if err != nil {
+			return nil, "", err
+		}
+		n.Shape, n.Text = shape.shape, nil
+		if text = unescapeQuotes(text); text != id {
+			n.AddLines(parseLineBreak.Split(text, -1)...)
+		}
+		rest = r
+		break
+	}
+	if m := parseClassUse.FindStringSubmatch(rest); m != nil {
+		p.deferred = append(p.deferred, parseDeferred{line: p.line,
+			kind: "class", ids: id, args: m[1]})
+		rest = rest[len(m[0]):]
+	}
+	return n, rest, nil
+}
+
+// Helperfunction to extract the (optionally quoted) text of a shape.
+func (p *parser) shapeText(s, close string) (text, rest string, err error) {
+	if strings.HasPrefix(s, `"`) {
+		end := strings.Index(s[1:], `"`)
+		if end < 0 || !strings.HasPrefix(s[end+2:], close) {
+			return "", "", p.errorf("unterminated node text %q", s)
+		}
+		return s[1 : end+1], s[end+2+len(close):], nil
+	}
+	end := strings.Index(s, close)
+	if end < 0 {
+		return "", "", p.errorf("unterminated node text %q", s)
+	}
+	return strings.TrimSpace(s[:end]), s[end+len(close):], nil
+}
+
+// Helperfunction to parse a link, the result is returned as a template Edge.
+func (p *parser) link(s string) (link *Edge, rest string, err error) {
+	link = &Edge{}
+	var arrow string
+	if m := parseLink.FindString(s); m != "" {
+		arrow, rest = m, strings.TrimSpace(s[len(m):])
+		if l := parseLinkLabel.FindStringSubmatch(rest); l != nil {
+			link.Text = parseLineBreak.Split(unquote(strings.TrimSpace(l[1])), -1)
+			rest = rest[len(l[0]):]
+		}
+	} else if m := parseLinkText.FindStringSubmatch(s); m != nil {
+		arrow, rest = m[1]+m[3], s[len(m[0]):]
+		link.Text = parseLineBreak.Split(unquote(m[2]), -1)
+	} else {
+		return nil, "", p.errorf("unsupported link at %q", s)
+	}
+	dotted := strings.Contains(arrow, ".")
+	thick := strings.HasPrefix(arrow, "=")
+	switch head := strings.HasSuffix(arrow, ">"); {
+	case dotted && head:
+		link.Shape = EShapeDottedArrow
+	case dotted:
+		link.Shape = EShapeDottedLine
+	case thick && head:
+		link.Shape = EShapeThickArrow
+	case thick:
+		link.Shape = EShapeThickLine
+	case head:
+		link.Shape = EShapeArrow
+	default:
+		link.Shape = EShapeLine
+	}
+	return link, rest, nil
+}
+
+// Helperfunction to remove surrounding double quotes, escaped ones are
+// unescaped.
+func unquote(s string) (unquoted string) {
+	if len(s) > 1 && strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`) {
+		s = s[1 : len(s)-1]
+	}
+	return unescapeQuotes(s)
+}
+
+// Helperfunction to revert escapeQuotes.
+func unescapeQuotes(text string) (unescaped string) {
+	return strings.Replace(text, "#quot;", `"`, -1)
+}
+
+// Helperfunction to look up a Node or Subgraph for class, style and click
+// statements, unknown IDs create a Node like mermaid does.
+func (p *parser) lookup(id string) (n *Node, sg *Subgraph) {
+	if sg = p.fc.GetSubgraph(id); sg != nil {
+		return nil, sg
+	}
+	if n = p.fc.GetNode(id); n == nil {
+		n = p.fc.AddNode(id)
+	}
+	return n, nil
+}
+
+// Helperfunction to apply the deferred statements in order of appearance,
+// classDefs are applied first so NodeStyles are created in their order.
+func (p *parser) applyDeferred() (err error) {
+	for _, d := range p.deferred {
+		if d.kind != "classDef" {
+			continue
+		}
+		p.line = d.line
+		for _, name := range strings.Split(d.ids, ",") {
+			ns := p.fc.NodeStyle(name)
+			if name == "default" {
+				p.fc.DefaultNodeStyle = ns
+			}
+			if err = applyNodeStyle(ns, d.args); err != nil {
+				return p.errorf("%s", err)
+			}
+		}
+	}
+	styleCount := 0
+	for _, d := range p.deferred {
+		p.line = d.line
+		switch d.kind {
+		case "class":
+			for _, id := range strings.Split(d.ids, ",") {
+				for _, name := range strings.Split(d.args, ",") {
+					ns := p.fc.NodeStyle(strings.TrimSpace(name))
+					if n, sg := p.lookup(id); n != nil {
+						n.AddStyles(ns)
+					} else {
+						sg.AddStyles(ns)
+					}
+				}
+			}
+		case "style":
+			n, sg := p.lookup(d.ids)
+			var ns *NodeStyle
+			if n != nil {
+				ns = n.InlineStyle()
+			} else {
+				ns = sg.InlineStyle()
+			}
+			if err = applyNodeStyle(ns, d.args); err != nil {
+				return p.errorf("%s", err)
+			}
+		case "click":
+			n, _ := p.lookup(d.ids)
+			if n == nil {
+				return p.errorf("click on subgraphs is not supported")
+			}
+			link := strings.SplitN(d.args, "\n", 2)
+			n.Link = link[0]
+			if link[1] != link[0] {
+				n.LinkText = link[1]
+			}
+		case "linkStyle":
+			es := p.fc.EdgeStyle(fmt.Sprintf("linkStyle%d", styleCount))
+			styleCount++
+			if err = applyEdgeStyle(es, d.args); err != nil {
+				return p.errorf("%s", err)
+			}
+			if d.ids == "default" {
+				p.fc.DefaultEdgeStyle = es
+				continue
+			}
+			for _, index := range strings.Split(d.ids, ",") {
+				i, _ := strconv.Atoi(index)
+				e := p.fc.GetEdge(i)
+				if e == nil || strconv.Itoa(i) != index {
+					return p.errorf("linkStyle for unknown edge %s", index)
+				}
+				e.Style = es
+			}
+		}
+	}
+	return nil
+}
+
+// Helperfunction to split CSS definitions at unescaped commas outside of
+// parentheses, escaped commas in the values are unescaped.
+func splitDefinitions(definitions string) (properties [][2]string) {
+	depth, start := 0, 0
+	split := func(end int) {
+		prop := strings.TrimSpace(definitions[start:end])
+		if prop == "" {
+			return
+		}
+		kv := strings.SplitN(prop, ":", 2)
+		if len(kv) == 1 {
+			kv = append(kv, "")
+		}
+		properties = append(properties, [2]string{strings.TrimSpace(kv[0]),
+			strings.Replace(strings.TrimSpace(kv[1]), `\,`, ",", -1)})
+	}
+	for i, r := range definitions {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			// escaped commas are part of the value
+			if depth == 0 && (i == 0 || definitions[i-1] != '\\') {
+				split(i)
+				start = i + 1
+			}
+		}
+	}
+	split(len(definitions))
+	return
+}
+
+// Helperfunction to parse pixel values like 5px, ok is false if the value
+// doesn't fit a uint8.
+func parsePixels(value string) (pixels uint8, ok bool) {
+	v, err := strconv.ParseUint(strings.TrimSuffix(value, "px"), 10, 8)
+	return uint8(v), err == nil
+}
+
+// Helperfunction to parse CSS definitions into a NodeStyle, properties without
+// a matching field are collected in More. The result is validated.
+func applyNodeStyle(ns *NodeStyle, definitions string) (err error) {
+	var more []string
+	for _, kv := range splitDefinitions(definitions) {
+		key, value, ok := kv[0], kv[1], true
+		switch key {
+		case "fill":
+			ns.Fill = mermaidgen.Color(value)
+		case "stroke":
+			ns.Stroke = mermaidgen.Color(value)
+		case "stroke-width":
+			ns.StrokeWidth, ok = parsePixels(value)
+		case "stroke-dasharray":
+			ns.StrokeDash, ok = parsePixels(value)
+		case "stroke-linecap":
+			ns.StrokeLinecap = strokeLinecap(value)
+		case "color":
+			ns.Color = mermaidgen.Color(value)
+		case "font-size":
+			ns.FontSize, ok = parsePixels(value)
+		case "font-weight":
+			ns.FontWeight = fontWeight(value)
+		case "font-family":
+			ns.FontFamily = value
+		case "opacity":
+			ns.Opacity, ok = parseOpacity(value)
+		case "rx":
+			ns.Rx, ok = parsePixels(value)
+		case "ry":
+			ns.Ry, ok = parsePixels(value)
+		case "padding":
+			ns.Padding, ok = parsePixels(value)
+		default:
+			ok = false
+		}
+		if !ok {
+			more = append(more, key+":"+styleValue(value))
+		}
+	}
+	ns.More = strings.Join(more, ",")
+	return ns.Validate()
+}
+
+// Helperfunction to parse linkStyle definitions into an EdgeStyle, properties
+// without a matching field are collected in More. The result is validated.
+func applyEdgeStyle(es *EdgeStyle, definitions string) (err error) {
+	if fields := strings.Fields(definitions); len(fields) > 0 &&
+		fields[0] == "interpolate" {
+		if len(fields) < 2 {
+			return fmt.Errorf("interpolate without curve")
+		}
+		es.Interpolation = edgeInterpolation(fields[1])
+		definitions = strings.Join(fields[2:], " ")
+	}
+	var more []string
+	for _, kv := range splitDefinitions(definitions) {
+		key, value, ok := kv[0], kv[1], true
+		switch key {
+		case "stroke":
+			es.Stroke = mermaidgen.Color(value)
+		case "stroke-width":
+			es.StrokeWidth, ok = parsePixels(value)
+		case "stroke-dasharray":
+			es.StrokeDash, ok = parsePixels(value)
+		case "stroke-linecap":
+			es.StrokeLinecap = strokeLinecap(value)
+		case "color":
+			es.Color = mermaidgen.Color(value)
+		case "font-size":
+			es.FontSize, ok = parsePixels(value)
+		case "font-weight":
+			es.FontWeight = fontWeight(value)
+		case "font-family":
+			es.FontFamily = value
+		case "opacity":
+			es.Opacity, ok = parseOpacity(value)
+		default:
+			ok = false
+		}
+		if !ok {
+			more = append(more, key+":"+styleValue(value))
+		}
+	}
+	es.More = strings.Join(more, ",")
+	return es.Validate()
+}
+
+// Helperfunction to parse opacity values.
+func parseOpacity(value string) (opacity *float64, ok bool) {
+	o, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, false
+	}
+	return &o, true
+}
@@ -0,0 +1,182 @@
+package flowchart_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen/flowchart"
+)
+
+// Parsing mermaid code into a Flowchart
+func ExampleParse() {
+	f, err := flowchart.Parse(`flowchart LR
+    A[Start] --> B{Is it?}
+    B -- Yes --> C(OK) & D
+    subgraph sg1 [Results]
+        C
+        D((maybe))
+    end
+    classDef hot fill:#f00
+    class C hot
+    linkStyle 0 stroke:#00f`)
+	fmt.Println(err)
+	// the result can be modified and rendered again
+	f.RemoveNode("D")
+	fmt.Print(f)
+	//Output:
+	//<nil>
+	//graph LR
+	//classDef hot fill:#f00
+	//A["Start"]
+	//B{"Is it?"}
+	//subgraph sg1 ["Results"]
+	//C("OK")
+	//class C hot
+	//end
+	//A --> B
+	//linkStyle 0 stroke:#00f
+	//B -->|"Yes"| C
+}
+
+// Unsupported syntax is reported with the line number
+func ExampleParse_errors() {
+	_, err := flowchart.Parse("graph TB\nA --> B[(database)]")
+	fmt.Println(err)
+	_, err = flowchart.Parse("graph TB\nsubgraph sg1\nA")
+	fmt.Println(err)
+	_, err = flowchart.Parse("sequenceDiagram")
+	fmt.Println(err)
+	//Output:
+	//Parse: line 2: unsupported node shape [()]
+	//Parse: subgraph sg1 not closed
+	//Parse: line 1: expected graph or flowchart header
+}
+
+func TestParse_roundTrip(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	f.Direction = flowchart.DirectionRightLeft
+	ns := f.NodeStyle("ns1")
+	ns.Fill = flowchart.ColorYellow
+	ns.More = "stroke-dasharray:5 5"
+	f.DefaultNodeStyle = f.NodeStyle("default")
+	f.DefaultNodeStyle.FontSize = 12
+	sg1 := f.AddSubgraph("sg1")
+	sg1.Title = "the title"
+	sg2 := sg1.AddSubgraph("sg2")
+	sg2.AddStyles(ns)
+	n1 := sg2.AddNode("n1")
+	n1.Shape = flowchart.NShapeCircle
+	n1.AddLines("line 1", "line 2")
+	n1.AddStyles(ns)
+	n1.InlineStyle().Stroke = flowchart.ColorRed
+	n2 := f.AddNode("n2")
+	n2.Shape = flowchart.NShapeFlagLeft
+	n2.Link = "http://www.example.com"
+	n2.LinkText = "tooltip"
+	n3 := f.AddNode("n3")
+	n3.Link = "http://www.example.com"
+	for _, shape := range []string{"-->", "-.->", "==>", "---", "-.-", "==="} {
+		e := f.AddEdge(n1, n2)
+		e.AddLines("via " + shape)
+		f.AddEdge(n2, n3).Style = f.EdgeStyle("es1")
+	}
+	f.EdgeStyle("es1").Interpolation = flowchart.InterpolationBasis
+	f.EdgeStyle("es1").Stroke = flowchart.ColorBlue
+	f.DefaultEdgeStyle = f.EdgeStyle("es1")
+	code := f.String()
+	parsed, err := flowchart.Parse(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %s\n%s", err, code)
+	}
+	if parsed.String() != code {
+		t.Errorf("round trip failed, expected:\n%s\ngot:\n%s", code,
+			parsed.String())
+	}
+}
+
+func TestParse_errors(t *testing.T) {
+	for _, code := range []string{
+		"",
+		"graph XY",
+		"graph TB\nend",
+		"graph TB\nA --o B",
+		"graph TB\nA -->",
+		"graph TB\nA[unterminated",
+		"graph TB\nA[\"unterminated]",
+		"graph TB\nsubgraph sg1\nend\nsubgraph sg1\nend",
+		"graph TB\nsubgraph sg1\nend\nA --> sg1",
+		"graph TB\nclassDef ns1",
+		"graph TB\nclassDef ns1 opacity:2",
+		"graph TB\nA --> B\nlinkStyle 1 stroke:#f00",
+		"graph TB\nA --> B\nlinkStyle 0 interpolate",
+		"graph TB\nclick A callback",
+		"---\nconfig:\n  theme: dark\n",
+	} {
+		if _, err := flowchart.Parse(code); err == nil {
+			t.Errorf("%q: no error returned", code)
+		}
+	}
+}
+
+func TestParse_fontFamily(t *testing.T) {
+	code := "graph TB\nclassDef ns1 font-family:Arial\\, sans-serif,x:a\\,b\n"
+	fc, err := flowchart.Parse(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ns := fc.NodeStyle("ns1"); ns.FontFamily != "Arial, sans-serif" ||
+		ns.More != "x:a\\,b" {
+		t.Errorf("unexpected NodeStyle %+v", ns)
+	}
+	if fc.String() != code {
+		t.Errorf("unexpected Flowchart\n%s", fc)
+	}
+}
+
+func TestParse_errorLines(t *testing.T) {
+	for code, expected := range map[string]string{
+		"---\nconfig:\n  theme: dark\n---\ngraph TB\nA --> B[(x)]": "line 6",
+		"%%{init: {\"theme\": \"dark\"}}%%\ngraph TB\nA[(x)]":      "line 3",
+	} {
+		_, err := flowchart.Parse(code)
+		if err == nil || !strings.Contains(err.Error(), expected+":") {
+			t.Errorf("%q: unexpected error %v", code, err)
+		}
+	}
+}
+
+func TestParse_comments(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	sg := f.AddSubgraph("sg")
+	sg.Comment = "the subgraph"
+	n1 := sg.AddNode("n1")
+	n1.Comment = "first line\nsecond line"
+	n1.AddStyles(f.NodeStyle("ns"))
+	n1.Link = "http://www.example.com"
+	n2 := f.AddNode("n2")
+	e := f.AddEdge(n1, n2)
+	e.Comment = "the edge"
+	e.Style = f.EdgeStyle("es")
+	for _, grouped := range []bool{false, true} {
+		f.Indent, f.Grouped = "\t", grouped
+		code := f.String()
+		parsed, err := flowchart.Parse(code)
+		if err != nil {
+			t.Fatalf("unexpected error: %s\n%s", err, code)
+		}
+		parsed.Indent, parsed.Grouped = "\t", grouped
+		if parsed.String() != code {
+			t.Errorf("round trip failed, expected:\n%s\ngot:\n%s", code,
+				parsed.String())
+		}
+	}
+	parsed, _ := flowchart.Parse("graph TB\n%% dropped\n\n%% n1\nn1\n" +
+		"%% dropped, too\nclass n1 ns\n%% edge\nn1 --> n2 --> n3\n")
+	if c := parsed.GetNode("n1").Comment; c != "n1" {
+		t.Errorf("unexpected Node comment %q", c)
+	}
+	if c := parsed.GetEdge(0).Comment + parsed.GetEdge(1).Comment; c != "edge" {
+		t.Errorf("unexpected Edge comments %q", c)
+	}
+}
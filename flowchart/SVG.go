@@ -0,0 +1,604 @@
+package flowchart
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Heiko-san/mermaidgen"
+)
+
+////////// themes //////////////////////////////////////////////////////////////
+
+// Colors of a mermaid theme as used by SVGRenderer.
+type svgTheme struct {
+	nodeFill        mermaidgen.Color
+	nodeStroke      mermaidgen.Color
+	clusterFill     mermaidgen.Color
+	clusterStroke   mermaidgen.Color
+	line            mermaidgen.Color
+	text            mermaidgen.Color
+	labelBackground mermaidgen.Color
+	background      mermaidgen.Color // empty for transparent
+}
+
+// Approximations of mermaid's themes, unknown themes use the default.
+var svgThemes = map[string]svgTheme{
+	"default": {"#ececff", "#9370db", "#ffffde", "#aaaa33", "#333333",
+		"#333333", "#e8e8e8", ""},
+	"base": {"#fff4dd", "#9f9f9f", "#ffffde", "#aaaa33", "#333333",
+		"#333333", "#e8e8e8", ""},
+	"forest": {"#cde498", "#13540c", "#cdffb2", "#6eaa49", "#000000",
+		"#000000", "#e8e8e8", ""},
+	"dark": {"#1f2020", "#cccccc", "#302f3d", "#555555", "#d3d3d3",
+		"#cccccc", "#585858", "#333333"},
+	"neutral": {"#eeeeee", "#999999", "#f4f4f4", "#666666", "#666666",
+		"#333333", "#e8e8e8", ""},
+}
+
+// Helperfunction to get the theme for a Config, ThemeVariables override the
+// theme's colors.
+func svgThemeOf(c *mermaidgen.Config) (t svgTheme) {
+	t = svgThemes["default"]
+	if c == nil {
+		return
+	}
+	if known, ok := svgThemes[string(c.Theme)]; ok {
+		t = known
+	}
+	for name, target := range map[string]*mermaidgen.Color{
+		"primaryColor":        &t.nodeFill,
+		"primaryBorderColor":  &t.nodeStroke,
+		"primaryTextColor":    &t.text,
+		"lineColor":           &t.line,
+		"clusterBkg":          &t.clusterFill,
+		"clusterBorder":       &t.clusterStroke,
+		"edgeLabelBackground": &t.labelBackground,
+		"background":          &t.background,
+	} {
+		if v, ok := c.ThemeVariables[name]; ok {
+			*target = mermaidgen.Color(v)
+		}
+	}
+	return
+}
+
+////////// SVGRenderer /////////////////////////////////////////////////////////
+
+// SVGRenderer renders Flowcharts to SVG images in pure Go, without Node.js or
+// a browser. It uses a layered layout honoring Direction, draws Subgraphs as
+// clusters, supports all Node and Edge shapes, Edge texts, NodeStyles,
+// EdgeStyles and click links. The Flowchart's Config is used for the theme,
+// the font and the spacing settings. The result looks similar to mermaid's
+// output, but is not pixel identical. Unset (zero value) fields use the
+// defaults.
+type SVGRenderer struct {
+	FontFamily string  // Config's FontFamily or a sans-serif font if not set
+	FontSize   float64 // Font size in pixels, 16 if not set
+}
+
+// RenderDiagram writes the given Flowchart as SVG image to w, other Diagrams
+// yield an error. Implements mermaidgen.Renderer.
+func (r *SVGRenderer) RenderDiagram(ctx context.Context, d mermaidgen.Diagram,
+	w io.Writer) (err error) {
+	fc, ok := d.(*Flowchart)
+	if !ok {
+		return fmt.Errorf("RenderDiagram: %s diagrams are not supported",
+			d.Kind())
+	}
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+	s := newSVGContext(fc, r)
+	_, err = w.Write(s.render())
+	return
+}
+
+// SVG renders the Flowchart to an SVG image using an SVGRenderer with default
+// settings and writes it to w.
+func (fc *Flowchart) SVG(w io.Writer) (err error) {
+	return (&SVGRenderer{}).RenderDiagram(context.Background(), fc, w)
+}
+
+////////// render //////////////////////////////////////////////////////////////
+
+// State of a single SVG rendering.
+type svgContext struct {
+	fc          *Flowchart
+	theme       svgTheme
+	fontFamily  string
+	fontSize    float64
+	padding     float64
+	nodeSpacing float64
+	rankSpacing float64
+	margin      float64
+	markers     []mermaidgen.Color // arrowhead colors, index is the marker ID
+	b           bytes.Buffer
+}
+
+// Helperfunction to create an svgContext, applying the defaults.
+func newSVGContext(fc *Flowchart, r *SVGRenderer) (s *svgContext) {
+	s = &svgContext{fc: fc, theme: svgThemeOf(fc.Config),
+		fontFamily: `"trebuchet ms", verdana, arial, sans-serif`,
+		fontSize:   16, padding: 15, nodeSpacing: 50, rankSpacing: 50,
+		margin: 8}
+	if c := fc.Config; c != nil {
+		if c.FontFamily != "" {
+			s.fontFamily = c.FontFamily
+		}
+		if f := c.Flowchart; f != nil {
+			setPositive(&s.padding, f.Padding)
+			setPositive(&s.nodeSpacing, f.NodeSpacing)
+			setPositive(&s.rankSpacing, f.RankSpacing)
+			setPositive(&s.margin, f.DiagramPadding)
+		}
+	}
+	if r.FontFamily != "" {
+		s.fontFamily = r.FontFamily
+	}
+	if r.FontSize > 0 {
+		s.fontSize = r.FontSize
+	}
+	return
+}
+
+// Helperfunction to override a default with a positive setting.
+func setPositive(target *float64, value int) {
+	if value > 0 {
+		*target = float64(value)
+	}
+}
+
+// Helperfunction to render the whole SVG document.
+func (s *svgContext) render() (svg []byte) {
+	l := s.fc.layout(layoutMetrics{
+		nodeSize:       s.nodeSize,
+		labelSize:      s.labelSize,
+		titleSize:      s.titleSize,
+		nodeSpacing:    s.nodeSpacing,
+		rankSpacing:    s.rankSpacing,
+		clusterPadding: s.padding / 2,
+		margin:         s.margin,
+	})
+	if s.theme.background != "" {
+		s.printf(`<rect width="100%%" height="100%%" fill="%s"/>`+"\n",
+			attribute(s.theme.background))
+	}
+	s.printf("<g class=\"clusters\">\n")
+	for _, c := range l.clusters {
+		s.renderCluster(c)
+	}
+	s.printf("</g>\n<g class=\"edges\">\n")
+	for _, le := range l.edges {
+		s.renderEdge(le)
+	}
+	s.printf("</g>\n<g class=\"edgeLabels\">\n")
+	for _, le := range l.edges {
+		s.renderEdgeLabel(le)
+	}
+	s.printf("</g>\n<g class=\"nodes\">\n")
+	for _, n := range l.nodes {
+		s.renderNode(n)
+	}
+	s.printf("</g>\n")
+	body := append([]byte{}, s.b.Bytes()...)
+	s.b.Reset()
+	s.printf(`<svg xmlns="http://www.w3.org/2000/svg" `+
+		`xmlns:xlink="http://www.w3.org/1999/xlink" width="%s" height="%s" `+
+		`viewBox="0 0 %s %s" font-family="%s" font-size="%s">`+"\n",
+		num(l.width), num(l.height), num(l.width), num(l.height),
+		html.EscapeString(s.fontFamily), num(s.fontSize))
+	if len(s.markers) > 0 {
+		s.printf("<defs>\n")
+		for i, color := range s.markers {
+			s.printf(`<marker id="arrowhead-%d" viewBox="0 0 10 10" `+
+				`refX="9" refY="5" markerUnits="userSpaceOnUse" `+
+				`markerWidth="12" markerHeight="12" orient="auto">`+
+				`<path d="M 0 0 L 10 5 L 0 10 z" fill="%s"/></marker>`+"\n",
+				i, attribute(color))
+		}
+		s.printf("</defs>\n")
+	}
+	s.b.Write(body)
+	s.printf("</svg>\n")
+	return s.b.Bytes()
+}
+
+// Helperfunction to write formatted output.
+func (s *svgContext) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&s.b, format, args...)
+}
+
+// Helperfunction to format coordinates with 2 decimals at most.
+func num(v float64) (formatted string) {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+////////// styles //////////////////////////////////////////////////////////////
+
+// CSS properties that apply to texts rather than shapes, and their SVG names.
+var svgTextProperties = map[string]string{
+	"color": "fill", "font-size": "font-size", "font-weight": "font-weight",
+	"font-family": "font-family",
+}
+
+// CSS properties of a NodeStyle or EdgeStyle split up for SVG elements.
+type svgStyle struct {
+	shape    [][2]string
+	text     [][2]string
+	fontSize float64 // 0 if not set
+	padding  float64 // 0 if not set
+}
+
+// Helperfunction to merge CSS definitions, later definitions override earlier
+// ones, and to split them into shape and text properties.
+func newSVGStyle(definitions ...string) (style svgStyle) {
+	set := func(props *[][2]string, key, value string) {
+		for i, kv := range *props {
+			if kv[0] == key {
+				(*props)[i][1] = value
+				return
+			}
+		}
+		*props = append(*props, [2]string{key, value})
+	}
+	for _, d := range definitions {
+		for _, kv := range splitDefinitions(d) {
+			key, value := kv[0], kv[1]
+			px, _ := strconv.ParseFloat(strings.TrimSuffix(value, "px"), 64)
+			switch {
+			case key == "padding":
+				style.padding = px
+			case svgTextProperties[key] != "":
+				if key == "font-size" {
+					style.fontSize = px
+				}
+				set(&style.text, svgTextProperties[key], value)
+			default:
+				set(&style.shape, key, value)
+			}
+		}
+	}
+	return
+}
+
+// Helperfunction to render CSS properties to a style attribute.
+func styleAttribute(props [][2]string) (attribute string) {
+	if len(props) == 0 {
+		return ""
+	}
+	rules := make([]string, len(props))
+	for i, kv := range props {
+		rules[i] = kv[0] + ":" + kv[1]
+	}
+	return ` style="` + html.EscapeString(strings.Join(rules, ";")) + `"`
+}
+
+// Helperfunction to escape a Color, e.g. from the Config's ThemeVariables, for
+// use as attribute value.
+func attribute(c mermaidgen.Color) (value string) {
+	return html.EscapeString(string(c))
+}
+
+// Helperfunction to get a merged property value, empty if not set.
+func property(props [][2]string, key string) (value string) {
+	for _, kv := range props {
+		if kv[0] == key {
+			value = kv[1]
+		}
+	}
+	return
+}
+
+// Helperfunction to get the merged styles of a Node.
+func (fc *Flowchart) nodeStyle(n *Node) (style svgStyle) {
+	var definitions []string
+	if fc.DefaultNodeStyle != nil {
+		definitions = append(definitions, fc.DefaultNodeStyle.definitions())
+	}
+	for _, ns := range n.styles() {
+		definitions = append(definitions, ns.definitions())
+	}
+	if n.inline != nil {
+		definitions = append(definitions, n.inline.definitions())
+	}
+	return newSVGStyle(definitions...)
+}
+
+// Helperfunction to get the merged styles of a Subgraph.
+func (fc *Flowchart) subgraphStyle(sg *Subgraph) (style svgStyle) {
+	var definitions []string
+	for _, ns := range sg.Styles {
+		definitions = append(definitions, ns.definitions())
+	}
+	if sg.inline != nil {
+		definitions = append(definitions, sg.inline.definitions())
+	}
+	return newSVGStyle(definitions...)
+}
+
+// Helperfunction to get the merged styles of an Edge.
+func (fc *Flowchart) edgeStyle(e *Edge) (style svgStyle) {
+	var definitions []string
+	if fc.DefaultEdgeStyle != nil {
+		definitions = append(definitions, fc.DefaultEdgeStyle.definitions())
+	}
+	if e.Style != nil {
+		definitions = append(definitions, e.Style.definitions())
+	}
+	return newSVGStyle(definitions...)
+}
+
+////////// sizes ///////////////////////////////////////////////////////////////
+
+// Helperfunction to estimate the size of a text block, there are no font
+// metrics available so an average character width is assumed.
+func textSize(lines []string, fontSize float64) (w, h float64) {
+	for _, line := range lines {
+		if lw := float64(utf8.RuneCountInString(line)) * fontSize * 0.6; lw > w {
+			w = lw
+		}
+	}
+	return w, float64(len(lines)) * fontSize * 1.2
+}
+
+// Helperfunction to get the text lines of a Node.
+func nodeLines(n *Node) (lines []string) {
+	if len(n.Text) == 0 {
+		return []string{n.id}
+	}
+	return n.Text
+}
+
+// Helperfunction to get the font size of a style.
+func (s *svgContext) fontSizeOf(style svgStyle) (fontSize float64) {
+	if style.fontSize > 0 {
+		return style.fontSize
+	}
+	return s.fontSize
+}
+
+// Helperfunction for layoutMetrics, computes a Node's size from its text and
+// shape.
+func (s *svgContext) nodeSize(n *Node) (w, h float64) {
+	style := s.fc.nodeStyle(n)
+	tw, th := textSize(nodeLines(n), s.fontSizeOf(style))
+	p := s.padding
+	if style.padding > 0 {
+		p = style.padding
+	}
+	switch n.Shape {
+	case NShapeCircle:
+		d := math.Hypot(tw, th) + p
+		return d, d
+	case NShapeRhombus:
+		// a square rhombus containing the text box
+		d := tw + th + 2*p
+		return d, d
+	case NShapeFlagLeft:
+		h = th + p*4/3
+		return tw + 2*p + h/4, h
+	default:
+		return tw + 2*p, th + p*4/3
+	}
+}
+
+// Helperfunction for layoutMetrics, computes the size of an Edge's text.
+func (s *svgContext) labelSize(e *Edge) (w, h float64) {
+	w, h = textSize(e.Text, s.fontSizeOf(s.fc.edgeStyle(e)))
+	return w + 4, h + 4
+}
+
+// Helperfunction for layoutMetrics, computes the size of a Subgraph's title.
+func (s *svgContext) titleSize(sg *Subgraph) (w, h float64) {
+	return textSize([]string{subgraphTitle(sg)}, s.fontSizeOf(s.fc.subgraphStyle(sg)))
+}
+
+// Helperfunction to get the title of a Subgraph.
+func subgraphTitle(sg *Subgraph) (title string) {
+	if sg.Title != "" {
+		return sg.Title
+	}
+	return sg.id
+}
+
+////////// elements ////////////////////////////////////////////////////////////
+
+// Helperfunction to render centered text lines.
+func (s *svgContext) renderText(lines []string, x, y float64, style svgStyle,
+	class string) {
+	lineHeight := s.fontSizeOf(style) * 1.2
+	s.printf(`<text class="%s" text-anchor="middle" dominant-baseline="central"`+
+		` fill="%s"%s>`, class, attribute(s.theme.text), styleAttribute(style.text))
+	top := y - float64(len(lines)-1)*lineHeight/2
+	for i, line := range lines {
+		s.printf(`<tspan x="%s" y="%s">%s</tspan>`, num(x),
+			num(top+float64(i)*lineHeight), html.EscapeString(line))
+	}
+	s.printf("</text>\n")
+}
+
+// Helperfunction to render a Subgraph's box and title.
+func (s *svgContext) renderCluster(c *layoutCluster) {
+	style := s.fc.subgraphStyle(c.subgraph)
+	s.printf(`<g class="cluster" id="%s">`+"\n",
+		html.EscapeString(c.subgraph.id))
+	s.printf(`<rect x="%s" y="%s" width="%s" height="%s" fill="%s" `+
+		`stroke="%s" stroke-width="1"%s/>`+"\n", num(c.x), num(c.y), num(c.w),
+		num(c.h), attribute(s.theme.clusterFill),
+		attribute(s.theme.clusterStroke), styleAttribute(style.shape))
+	s.renderText([]string{subgraphTitle(c.subgraph)}, c.x+c.w/2,
+		c.y+s.padding/2+c.titleH/2, style, "clusterLabel")
+	s.printf("</g>\n")
+}
+
+// Helperfunction to render a Node including its click link.
+func (s *svgContext) renderNode(ln *layoutNode) {
+	n := ln.node
+	style := s.fc.nodeStyle(n)
+	if n.Link != "" {
+		link := html.EscapeString(n.Link)
+		s.printf(`<a href="%s" xlink:href="%s">`, link, link)
+		if n.LinkText != "" {
+			s.printf(`<title>%s</title>`, html.EscapeString(n.LinkText))
+		}
+		s.printf("\n")
+	}
+	classes := []string{"node"}
+	for _, ns := range n.styles() {
+		classes = append(classes, ns.id)
+	}
+	s.printf(`<g class="%s" id="%s">`+"\n",
+		html.EscapeString(strings.Join(classes, " ")), html.EscapeString(n.id))
+	attributes := fmt.Sprintf(` fill="%s" stroke="%s" stroke-width="1"%s`,
+		attribute(s.theme.nodeFill), attribute(s.theme.nodeStroke),
+		styleAttribute(style.shape))
+	x0, y0 := ln.x-ln.w/2, ln.y-ln.h/2
+	x1, y1 := ln.x+ln.w/2, ln.y+ln.h/2
+	switch n.Shape {
+	case NShapeCircle:
+		s.printf(`<circle cx="%s" cy="%s" r="%s"%s/>`+"\n", num(ln.x),
+			num(ln.y), num(ln.w/2), attributes)
+	case NShapeRhombus:
+		s.printf(`<polygon points="%s,%s %s,%s %s,%s %s,%s"%s/>`+"\n",
+			num(ln.x), num(y0), num(x1), num(ln.y), num(ln.x), num(y1),
+			num(x0), num(ln.y), attributes)
+	case NShapeFlagLeft:
+		s.printf(`<polygon points="%s,%s %s,%s %s,%s %s,%s %s,%s"%s/>`+"\n",
+			num(x0), num(y0), num(x1), num(y0), num(x1), num(y1), num(x0),
+			num(y1), num(x0+ln.h/4), num(ln.y), attributes)
+	default:
+		radius := 0.0
+		if n.Shape == NShapeRoundRect {
+			radius = 5
+		}
+		s.printf(`<rect x="%s" y="%s" width="%s" height="%s" rx="%s" `+
+			`ry="%s"%s/>`+"\n", num(x0), num(y0), num(ln.w), num(ln.h),
+			num(radius), num(radius), attributes)
+	}
+	textX := ln.x
+	if n.Shape == NShapeFlagLeft {
+		textX += ln.h / 8
+	}
+	s.renderText(nodeLines(n), textX, ln.y, style, "nodeLabel")
+	s.printf("</g>\n")
+	if n.Link != "" {
+		s.printf("</a>\n")
+	}
+}
+
+// Helperfunction to get the ID of the arrowhead marker for a color.
+func (s *svgContext) marker(color mermaidgen.Color) (id int) {
+	for i, c := range s.markers {
+		if c == color {
+			return i
+		}
+	}
+	s.markers = append(s.markers, color)
+	return len(s.markers) - 1
+}
+
+// Helperfunction to get the effective interpolation of an Edge.
+func (s *svgContext) interpolation(e *Edge) (interpolation edgeInterpolation) {
+	if e.Style != nil && e.Style.Interpolation != "" {
+		return e.Style.Interpolation
+	}
+	if s.fc.DefaultEdgeStyle != nil && s.fc.DefaultEdgeStyle.Interpolation != "" {
+		return s.fc.DefaultEdgeStyle.Interpolation
+	}
+	// mermaid draws basis curves if no curve is configured
+	if c := s.fc.Config; c != nil && c.Flowchart != nil &&
+		c.Flowchart.Curve != "" && c.Flowchart.Curve != mermaidgen.CurveBasis {
+		return InterpolationLinear
+	}
+	return InterpolationBasis
+}
+
+// Helperfunction to render an Edge's path.
+func (s *svgContext) renderEdge(le *layoutEdge) {
+	e := le.edge
+	style := s.fc.edgeStyle(e)
+	width, dash, arrow := "2", "", false
+	switch e.Shape {
+	case EShapeArrow:
+		arrow = true
+	case EShapeDottedArrow:
+		dash, arrow = ` stroke-dasharray="3"`, true
+	case EShapeThickArrow:
+		width, arrow = "3.5", true
+	case EShapeDottedLine:
+		dash = ` stroke-dasharray="3"`
+	case EShapeThickLine:
+		width = "3.5"
+	}
+	color := s.theme.line
+	if stroke := property(style.shape, "stroke"); stroke != "" {
+		color = mermaidgen.Color(stroke)
+	}
+	d := linearPath(le.points)
+	if s.interpolation(e) == InterpolationBasis {
+		d = basisPath(le.points)
+	}
+	marker := ""
+	if arrow {
+		marker = fmt.Sprintf(` marker-end="url(#arrowhead-%d)"`, s.marker(color))
+	}
+	s.printf(`<path class="edge" d="%s" fill="none" stroke="%s" `+
+		`stroke-width="%s"%s%s%s/>`+"\n", d, attribute(s.theme.line), width, dash,
+		styleAttribute(style.shape), marker)
+}
+
+// Helperfunction to render an Edge's text.
+func (s *svgContext) renderEdgeLabel(le *layoutEdge) {
+	if len(le.edge.Text) == 0 {
+		return
+	}
+	style := s.fc.edgeStyle(le.edge)
+	s.printf(`<g class="edgeLabel"><rect x="%s" y="%s" width="%s" `+
+		`height="%s" fill="%s"/>`+"\n", num(le.labelX-le.labelW/2),
+		num(le.labelY-le.labelH/2), num(le.labelW), num(le.labelH),
+		attribute(s.theme.labelBackground))
+	s.renderText(le.edge.Text, le.labelX, le.labelY, style, "edgeText")
+	s.printf("</g>\n")
+}
+
+// Helperfunction to render points to straight path segments.
+func linearPath(points [][2]float64) (d string) {
+	parts := make([]string, len(points))
+	for i, p := range points {
+		parts[i] = "L" + num(p[0]) + "," + num(p[1])
+	}
+	return "M" + strings.Join(parts, " ")[1:]
+}
+
+// Helperfunction to render points to a smooth path using the same uniform
+// B-spline as d3's curveBasis.
+func basisPath(points [][2]float64) (d string) {
+	if len(points) < 3 {
+		return linearPath(points)
+	}
+	var parts []string
+	bezier := func(p0, p1, p [2]float64) {
+		parts = append(parts, fmt.Sprintf("C%s,%s %s,%s %s,%s",
+			num((2*p0[0]+p1[0])/3), num((2*p0[1]+p1[1])/3),
+			num((p0[0]+2*p1[0])/3), num((p0[1]+2*p1[1])/3),
+			num((p0[0]+4*p1[0]+p[0])/6), num((p0[1]+4*p1[1]+p[1])/6)))
+	}
+	first, second := points[0], points[1]
+	parts = append(parts, "M"+num(first[0])+","+num(first[1]),
+		"L"+num((5*first[0]+second[0])/6)+","+num((5*first[1]+second[1])/6))
+	for i := 2; i < len(points); i++ {
+		bezier(points[i-2], points[i-1], points[i])
+	}
+	n := len(points)
+	last := points[n-1]
+	bezier(points[n-2], last, last)
+	parts = append(parts, "L"+num(last[0])+","+num(last[1]))
+	return strings.Join(parts, " ")
+}
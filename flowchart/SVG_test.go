@@ -0,0 +1,253 @@
+package flowchart_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/flowchart"
+	"github.com/Heiko-san/mermaidgen/gantt"
+)
+
+// Rendering Flowcharts to SVG without external tools
+func ExampleFlowchart_SVG() {
+	f := flowchart.NewFlowchart()
+	f.Direction = flowchart.DirectionLeftRight
+	e := f.AddEdge(f.AddNode("n1"), f.AddNode("n2"))
+	e.AddLines("go")
+	f.SVG(os.Stdout)
+	//Output:
+	//<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" width="187.6" height="55.2" viewBox="0 0 187.6 55.2" font-family="&#34;trebuchet ms&#34;, verdana, arial, sans-serif" font-size="16">
+	//<defs>
+	//<marker id="arrowhead-0" viewBox="0 0 10 10" refX="9" refY="5" markerUnits="userSpaceOnUse" markerWidth="12" markerHeight="12" orient="auto"><path d="M 0 0 L 10 5 L 0 10 z" fill="#333333"/></marker>
+	//</defs>
+	//<g class="clusters">
+	//</g>
+	//<g class="edges">
+	//<path class="edge" d="M57.2,27.6 L130.4,27.6" fill="none" stroke="#333333" stroke-width="2" marker-end="url(#arrowhead-0)"/>
+	//</g>
+	//<g class="edgeLabels">
+	//<g class="edgeLabel"><rect x="82.2" y="16" width="23.2" height="23.2" fill="#e8e8e8"/>
+	//<text class="edgeText" text-anchor="middle" dominant-baseline="central" fill="#333333"><tspan x="93.8" y="27.6">go</tspan></text>
+	//</g>
+	//</g>
+	//<g class="nodes">
+	//<g class="node" id="n1">
+	//<rect x="8" y="8" width="49.2" height="39.2" rx="0" ry="0" fill="#ececff" stroke="#9370db" stroke-width="1"/>
+	//<text class="nodeLabel" text-anchor="middle" dominant-baseline="central" fill="#333333"><tspan x="32.6" y="27.6">n1</tspan></text>
+	//</g>
+	//<g class="node" id="n2">
+	//<rect x="130.4" y="8" width="49.2" height="39.2" rx="0" ry="0" fill="#ececff" stroke="#9370db" stroke-width="1"/>
+	//<text class="nodeLabel" text-anchor="middle" dominant-baseline="central" fill="#333333"><tspan x="155" y="27.6">n2</tspan></text>
+	//</g>
+	//</g>
+	//</svg>
+}
+
+// Helperfunction to parse the boxes of all nodes and clusters of an SVG.
+func svgBoxes(t *testing.T, svg []byte) (nodes, clusters map[string][4]float64) {
+	nodes = make(map[string][4]float64)
+	clusters = make(map[string][4]float64)
+	d := xml.NewDecoder(bytes.NewReader(svg))
+	var group, class string
+	for {
+		token, err := d.Token()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %s\n%s", err, svg)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		attr := make(map[string]float64)
+		var points []float64
+		for _, a := range start.Attr {
+			switch a.Name.Local {
+			case "id":
+				group = a.Value
+			case "class":
+				class = a.Value
+			case "points":
+				for _, p := range strings.FieldsFunc(a.Value, func(r rune) bool {
+					return r == ' ' || r == ','
+				}) {
+					v, _ := strconv.ParseFloat(p, 64)
+					points = append(points, v)
+				}
+			default:
+				attr[a.Name.Local], _ = strconv.ParseFloat(a.Value, 64)
+			}
+		}
+		switch start.Name.Local {
+		case "rect", "circle", "polygon":
+			box := [4]float64{attr["x"], attr["y"], attr["x"] + attr["width"],
+				attr["y"] + attr["height"]}
+			switch start.Name.Local {
+			case "circle":
+				box = [4]float64{attr["cx"] - attr["r"], attr["cy"] - attr["r"],
+					attr["cx"] + attr["r"], attr["cy"] + attr["r"]}
+			case "polygon":
+				box = [4]float64{points[0], points[1], points[0], points[1]}
+				for i := 0; i < len(points); i += 2 {
+					box[0], box[2] = math.Min(box[0], points[i]),
+						math.Max(box[2], points[i])
+					box[1], box[3] = math.Min(box[1], points[i+1]),
+						math.Max(box[3], points[i+1])
+				}
+			}
+			if class == "cluster" {
+				clusters[group] = box
+			} else if strings.HasPrefix(class, "node") {
+				nodes[group] = box
+			}
+		}
+	}
+}
+
+// Helperfunction to check whether two boxes overlap.
+func overlap(a, b [4]float64) bool {
+	return a[0] < b[2] && b[0] < a[2] && a[1] < b[3] && b[1] < a[3]
+}
+
+// Helperfunction to check whether box a is inside of box b.
+func inside(a, b [4]float64) bool {
+	return a[0] >= b[0] && a[1] >= b[1] && a[2] <= b[2] && a[3] <= b[3]
+}
+
+func TestSVGRenderer_layout(t *testing.T) {
+	for _, direction := range []string{"TB", "BT", "LR", "RL"} {
+		f, err := flowchart.Parse("graph " + direction + `
+			A --> B -- text --> C((C)) --> D{D}
+			A --> D
+			D --> A
+			D --> D
+			subgraph sg1
+				B
+				subgraph sg2
+					E>E]
+				end
+			end
+			C --> E
+			F
+			click F "http://example.com" "tip"
+			classDef hot fill:#f00,color:#fff
+			class B hot`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b bytes.Buffer
+		if err = f.SVG(&b); err != nil {
+			t.Fatal(err)
+		}
+		nodes, clusters := svgBoxes(t, b.Bytes())
+		if len(nodes) != 6 || len(clusters) != 2 {
+			t.Fatalf("%s: expected 6 nodes and 2 clusters, got %v %v",
+				direction, nodes, clusters)
+		}
+		for id, box := range nodes {
+			for other, otherBox := range nodes {
+				if id != other && overlap(box, otherBox) {
+					t.Errorf("%s: %s overlaps %s", direction, id, other)
+				}
+			}
+			in := id == "B" || id == "E"
+			if in != inside(box, clusters["sg1"]) {
+				t.Errorf("%s: %s inside sg1 is %v", direction, id, !in)
+			} else if !in && overlap(box, clusters["sg1"]) {
+				t.Errorf("%s: %s overlaps sg1", direction, id)
+			}
+		}
+		if !inside(clusters["sg2"], clusters["sg1"]) || !inside(nodes["E"], clusters["sg2"]) {
+			t.Errorf("%s: sg2 not inside sg1", direction)
+		}
+		// the chain A --> C --> D follows the direction
+		a, c := nodes["A"], nodes["C"]
+		if ok := map[string]bool{"TB": a[3] < c[1], "BT": a[1] > c[3],
+			"LR": a[2] < c[0], "RL": a[0] > c[2]}[direction]; !ok {
+			t.Errorf("%s: wrong direction %v %v", direction, a, c)
+		}
+		for _, expected := range []string{
+			`xlink:href="http://example.com"><title>tip</title>`,
+			`style="fill:#f00"`, `style="fill:#fff"`,
+			`>text</tspan>`} {
+			if !bytes.Contains(b.Bytes(), []byte(expected)) {
+				t.Errorf("%s: %s missing", direction, expected)
+			}
+		}
+	}
+}
+
+func TestSVGRenderer_RenderDiagram(t *testing.T) {
+	g, _ := gantt.NewGantt()
+	var r mermaidgen.Renderer = &flowchart.SVGRenderer{}
+	if err := r.RenderDiagram(context.Background(), g, &bytes.Buffer{}); err == nil {
+		t.Error("no error for gantt")
+	}
+	// themes and fonts
+	f := flowchart.NewFlowchart()
+	f.AddNode("n1")
+	f.Config, _ = mermaidgen.NewConfig(mermaidgen.ThemeDark)
+	f.Config.ThemeVariables = map[string]string{"primaryColor": "#123456"}
+	r = &flowchart.SVGRenderer{FontFamily: "monospace", FontSize: 10}
+	var b bytes.Buffer
+	if err := r.RenderDiagram(context.Background(), f, &b); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{`font-family="monospace"`,
+		`font-size="10"`, `fill="#123456"`, `fill="#333333"/>`} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("%s missing in %s", expected, b.String())
+		}
+	}
+	// empty Flowcharts
+	b.Reset()
+	if err := flowchart.NewFlowchart().SVG(&b); err != nil ||
+		!strings.Contains(b.String(), `width="16"`) {
+		t.Errorf("unexpected result %s %v", b.String(), err)
+	}
+}
+
+func TestSVGRenderer_curveAndColors(t *testing.T) {
+	f := flowchart.NewFlowchart()
+	a, b, c := f.AddNode("a"), f.AddNode("b"), f.AddNode("c")
+	f.AddEdge(a, b)
+	f.AddEdge(b, c)
+	f.AddEdge(a, c) // spans two layers, so it has bends
+	var svg bytes.Buffer
+	if err := f.SVG(&svg); err != nil {
+		t.Fatal(err)
+	}
+	// no curve setting results in CurveBasis like in mermaid
+	if !strings.Contains(svg.String(), " C") {
+		t.Errorf("no basis curve in %s", svg.String())
+	}
+	f.Config, _ = mermaidgen.NewConfig(mermaidgen.ThemeDefault)
+	f.Config.Flowchart = &mermaidgen.FlowchartConfig{Curve: mermaidgen.CurveLinear}
+	f.Config.ThemeVariables = map[string]string{
+		"primaryColor": `red"/><script/>`, "lineColor": `a&b`}
+	svg.Reset()
+	if err := f.SVG(&svg); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(svg.String(), " C") {
+		t.Errorf("basis curve despite CurveLinear in %s", svg.String())
+	}
+	if strings.Contains(svg.String(), "<script") ||
+		!strings.Contains(svg.String(), `fill="red&#34;/&gt;&lt;script/&gt;"`) ||
+		strings.Contains(svg.String(), "a&b") {
+		t.Errorf("theme colors not escaped in %s", svg.String())
+	}
+	if err := xml.Unmarshal(svg.Bytes(), new(struct{})); err != nil {
+		t.Errorf("invalid SVG: %s", err)
+	}
+}
@@ -10,10 +10,14 @@ import (
 // directly. Already defined IDs can be looked up via Flowchart's GetSubgraph
 // method or iterated over via its ListSubgraphs method.
 type Subgraph struct {
-	id        string      // virtual ID for lookup
-	flowchart *Flowchart  // top lvl pointer
-	items     []graphItem // sub-items to render
-	Title     string      // The title of this Subgraph.
+	id        string       // virtual ID for lookup
+	flowchart *Flowchart   // top lvl pointer
+	subgraph  *Subgraph    // containing Subgraph, nil for top level
+	items     []graphItem  // sub-items to render
+	Title     string       // The title of this Subgraph, ID if not set.
+	Styles    []*NodeStyle // Optional CSS styles (classes).
+	inline    *NodeStyle   // Optional one-off CSS style.
+	Comment   string       // Optional %% comment rendered above the Subgraph.
 }
 
 // ID provides access to the Subgraph's readonly field id.
@@ -27,19 +31,50 @@ func (sg *Subgraph) Flowchart() (topLevel *Flowchart) {
 	return sg.flowchart
 }
 
+// Subgraph provides access to the Subgraph that contains this Subgraph. If this
+// Subgraph is contained in the top level Flowchart itself, nil is returned.
+func (sg *Subgraph) Subgraph() (containingSubgraph *Subgraph) {
+	return sg.subgraph
+}
+
 // Implements graphItem, see String() for further details.
-func (sg *Subgraph) renderGraph() string {
-	text := fmt.Sprintln("subgraph", sg.Title)
+func (sg *Subgraph) writeGraph(w *graphWriter) {
+	w.comment(sg.Comment)
+	title := sg.id
+	if sg.Title != "" {
+		title = sg.Title
+	}
+	w.startLine()
+	fmt.Fprintf(w, "subgraph %s [\"%s\"]\n", sg.id, escapeQuotes(title))
+	w.level++
 	for _, item := range sg.items {
-		text += item.renderGraph()
+		item.writeGraph(w)
+	}
+	w.level--
+	w.startLine()
+	w.WriteString("end\n")
+	if !w.grouped {
+		sg.writeStyles(w)
+	}
+}
+
+// Implements graphItem, renders the class and style lines of the Subgraph.
+func (sg *Subgraph) writeStyles(w *graphWriter) {
+	if sg.flowchart.classMode() != ClassesBulk {
+		// there is no inline class syntax for subgraphs
+		writeClasses(w, sg.id, sg.Styles)
 	}
-	text += "end\n"
-	return text
+	writeInlineStyle(w, sg.id, sg.inline)
 }
 
 // String renders this graph element to a subgraph block.
+// If Styles member is set an additional class line will be created, unless
+// the Flowchart's ClassMode is ClassesBulk.
+// If an InlineStyle is defined an additional style line will be created.
+// If Comment member is set it is rendered as %% lines above.
+// Nested items are indented according to the Flowchart's Indent.
 func (sg *Subgraph) String() (renderedElement string) {
-	return sg.renderGraph()
+	return renderString(sg.flowchart.Indent, sg.writeGraph)
 }
 
 // AddSubgraph is used to add another nested Subgraph below this Subgraph layer.
@@ -51,7 +86,7 @@ func (sg *Subgraph) AddSubgraph(id string) (newSubgraph *Subgraph) {
 	if alreadyExists {
 		return nil
 	}
-	s := &Subgraph{id: id, flowchart: sg.flowchart}
+	s := &Subgraph{id: id, flowchart: sg.flowchart, subgraph: sg}
 	sg.flowchart.subgraphs[id] = s
 	sg.items = append(sg.items, s)
 	return s
@@ -65,8 +100,30 @@ func (sg *Subgraph) AddNode(id string) (newNode *Node) {
 	if alreadyExists {
 		return nil
 	}
-	n := &Node{id: id, Shape: NShapeRect}
+	n := &Node{id: id, flowchart: sg.flowchart, subgraph: sg,
+		Shape: NShapeRect}
 	sg.flowchart.nodes[id] = n
 	sg.items = append(sg.items, n)
 	return n
 }
+
+// AddStyles adds one or more NodeStyles to the Styles member.
+// Each NodeStyle is rendered as an additional class of this Subgraph.
+func (sg *Subgraph) AddStyles(styles ...*NodeStyle) {
+	sg.Styles = append(sg.Styles, styles...)
+}
+
+// InlineStyle is used to create or access this Subgraph's one-off NodeStyle.
+// Unlike NodeStyles assigned via Styles, it renders to a style line for this
+// Subgraph only instead of a named classDef.
+func (sg *Subgraph) InlineStyle() (style *NodeStyle) {
+	if sg.inline == nil {
+		sg.inline = &NodeStyle{StrokeWidth: 1}
+	}
+	return sg.inline
+}
+
+// RemoveInlineStyle drops this Subgraph's one-off NodeStyle, if any.
+func (sg *Subgraph) RemoveInlineStyle() {
+	sg.inline = nil
+}
@@ -25,12 +25,12 @@ func ExampleSubgraph() {
 	fmt.Print(f)
 	//Output:
 	//graph TB
-	//subgraph vpc-123
-	//subgraph AZ a
+	//subgraph sg1 ["vpc-123"]
+	//subgraph sg2 ["AZ a"]
 	//i-123["i-123"]
 	//mydb["mydb"]
 	//end
-	//subgraph AZ b
+	//subgraph sg3 ["AZ b"]
 	//i-456["i-456"]
 	//end
 	//end
@@ -63,3 +63,44 @@ func ExampleSubgraph_addDuplicate() {
 	fmt.Println(n, s)
 	//Output: <nil> <nil>
 }
+
+// Removing a Subgraph with nested Subgraphs
+func ExampleSubgraph_remove() {
+	f := flowchart.NewFlowchart()
+	sg1 := f.AddSubgraph("sg1")
+	sg2 := sg1.AddSubgraph("sg2")
+	sg3 := sg2.AddSubgraph("sg3")
+	sg3.Title = "sg3"
+	sg2.AddNode("n1")
+	sg3.AddNode("n2")
+	// without contents, nested items move up one level
+	f.RemoveSubgraph("sg2", false)
+	fmt.Println(sg3.Subgraph() == sg1, f.GetNode("n1").Subgraph() == sg1)
+	// with contents, nested Subgraphs and Nodes are gone, too
+	f.RemoveSubgraph("sg1", true)
+	fmt.Println(f.GetSubgraph("sg3"), f.GetNode("n2"), len(f.ListNodes()))
+	//Output:
+	//true true
+	//<nil> <nil> 0
+}
+
+// Styling a single Subgraph without defining a class
+func ExampleSubgraph_inlineStyle() {
+	f := flowchart.NewFlowchart()
+	sg := f.AddSubgraph("sg1")
+	sg.AddNode("n1")
+	sg.InlineStyle().Fill = flowchart.ColorCyan
+	fmt.Print(f)
+	// drop it again
+	sg.RemoveInlineStyle()
+	fmt.Print(sg)
+	//Output:
+	//graph TB
+	//subgraph sg1 ["sg1"]
+	//n1["n1"]
+	//end
+	//style sg1 fill:#0ff
+	//subgraph sg1 ["sg1"]
+	//n1["n1"]
+	//end
+}
@@ -0,0 +1,713 @@
+package flowchart
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/Heiko-san/mermaidgen"
+)
+
+////////// TextRenderer ////////////////////////////////////////////////////////
+
+// TextRenderer renders Flowcharts as text for terminals and log output, using
+// the same layered layout as SVGRenderer. Nodes are drawn as boxes, Edges as
+// lines with arrowheads and texts and Subgraphs as boxes with their title in
+// the top border, all honoring Direction. Unset (zero value) fields use the
+// defaults.
+// If the diagram is wider than Width, a more compact layout is tried and
+// lines that are still too long are cut off.
+type TextRenderer struct {
+	Width int  // Maximum line width in characters, 0 for no limit
+	ASCII bool // Use ASCII characters instead of Unicode box drawing
+	Color bool // Use ANSI colors derived from NodeStyles and EdgeStyles
+}
+
+// RenderDiagram writes the given Flowchart as text to w, other Diagrams yield
+// an error. Implements mermaidgen.Renderer.
+func (r *TextRenderer) RenderDiagram(ctx context.Context, d mermaidgen.Diagram,
+	w io.Writer) (err error) {
+	fc, ok := d.(*Flowchart)
+	if !ok {
+		return fmt.Errorf("RenderDiagram: %s diagrams are not supported",
+			d.Kind())
+	}
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+	_, err = w.Write(r.render(fc))
+	return
+}
+
+// Text renders the Flowchart as Unicode text using a TextRenderer with default
+// settings and writes it to w.
+func (fc *Flowchart) Text(w io.Writer) (err error) {
+	return (&TextRenderer{}).RenderDiagram(context.Background(), fc, w)
+}
+
+// Helperfunction to lay out and draw the whole Flowchart.
+func (r *TextRenderer) render(fc *Flowchart) (text []byte) {
+	l := fc.layout(textMetrics(fc, false))
+	if r.Width > 0 && l.width > float64(r.Width) {
+		l = fc.layout(textMetrics(fc, true))
+	}
+	c := newTextCanvas(fc, l, r)
+	for _, cl := range l.clusters {
+		c.drawCluster(cl)
+	}
+	c.assignPorts()
+	labels := make([][2]int, len(l.edges))
+	for i, le := range l.edges {
+		labels[i] = c.drawEdge(le)
+	}
+	for _, cl := range l.clusters {
+		c.drawTitle(cl)
+	}
+	for i, le := range l.edges {
+		c.drawLabel(le.edge, labels[i])
+	}
+	for _, n := range l.nodes {
+		c.drawNode(n)
+	}
+	return c.bytes(r.Width)
+}
+
+// Helperfunction to get the layoutMetrics in characters. The compact metrics
+// reduce the spacing that determines the width of the diagram.
+func textMetrics(fc *Flowchart, compact bool) (m layoutMetrics) {
+	m = layoutMetrics{
+		nodeSize: func(n *Node) (w, h float64) {
+			lines := nodeLines(n)
+			w = float64(textWidth(lines) + 4)
+			if n.Shape == NShapeFlagLeft {
+				w++
+			}
+			return w, float64(len(lines) + 2)
+		},
+		labelSize: func(e *Edge) (w, h float64) {
+			if len(e.Text) == 0 {
+				return 0, 0
+			}
+			return float64(textWidth(e.Text) + 2), float64(len(e.Text))
+		},
+		titleSize: func(sg *Subgraph) (w, h float64) {
+			// the title is part of the top border
+			return float64(utf8.RuneCountInString(subgraphTitle(sg)) + 2), 0
+		},
+		nodeSpacing:    4,
+		rankSpacing:    3,
+		clusterPadding: 2,
+	}
+	horizontal := fc.Direction == DirectionLeftRight ||
+		fc.Direction == DirectionRightLeft
+	if horizontal {
+		m.nodeSpacing, m.rankSpacing = 2, 6
+	}
+	if compact {
+		if horizontal {
+			m.rankSpacing = 4
+		} else {
+			m.nodeSpacing = 2
+		}
+	}
+	return
+}
+
+// Helperfunction to get the width of the longest line in characters.
+func textWidth(lines []string) (width int) {
+	for _, line := range lines {
+		if w := utf8.RuneCountInString(line); w > width {
+			width = w
+		}
+	}
+	return
+}
+
+////////// canvas //////////////////////////////////////////////////////////////
+
+// Directions of the lines leaving a cell, combined to a mask.
+const (
+	lineUp uint8 = 1 << iota
+	lineRight
+	lineDown
+	lineLeft
+)
+
+// Line weights, used as index into the line characters.
+const (
+	lineNormal = iota
+	lineDotted
+	lineThick
+)
+
+// Line characters by weight, indexed by mask.
+var (
+	unicodeLines = [3][]rune{[]rune(" │─└││┌├─┘─┴┐┤┬┼"),
+		[]rune(" ┆┄└┆┆┌├┄┘┄┴┐┤┬┼"), []rune(" ┃━┗┃┃┏┣━┛━┻┓┫┳╋")}
+	asciiLines = [3][]rune{[]rune(" |-+||++-+-+++++"),
+		[]rune(" :.+::++.+.+++++"), []rune(" #=+##++=+=+++++")}
+)
+
+// Border characters of the Node shapes in the order top left, top right,
+// bottom left, bottom right, top and bottom, left, right.
+var (
+	unicodeShapes = map[nodeShape][]rune{
+		NShapeRect:      []rune("┌┐└┘─││"),
+		NShapeRoundRect: []rune("╭╮╰╯─││"),
+		NShapeCircle:    []rune("╭╮╰╯─()"),
+		NShapeRhombus:   []rune("╱╲╲╱─<>"),
+		NShapeFlagLeft:  []rune("┌┐└┘─>│"),
+	}
+	asciiShapes = map[nodeShape][]rune{
+		NShapeRect:      []rune("++++-||"),
+		NShapeRoundRect: []rune("..''-||"),
+		NShapeCircle:    []rune("..''-()"),
+		NShapeRhombus:   []rune(`/\\/-<>`),
+		NShapeFlagLeft:  []rune("++++->|"),
+	}
+)
+
+// A character cell of a textCanvas.
+type textCell struct {
+	r      rune   // the character, 0 to draw lines according to mask
+	mask   uint8  // directions of the lines leaving the cell
+	weight int    // weight of the lines
+	fg, bg string // ANSI escape sequences
+}
+
+// State of a single text rendering.
+type textCanvas struct {
+	fc         *Flowchart
+	layout     *layout
+	cells      [][]textCell
+	lines      [3][]rune
+	shapes     map[nodeShape][]rune
+	arrows     map[uint8]rune // arrowheads by the direction they point to
+	ellipsis   rune
+	color      bool
+	horizontal bool
+	ports      map[textPortKey][2]int // spread Edge ends
+}
+
+// Identifies the start (or else the end) of an Edge.
+type textPortKey struct {
+	edge  *Edge
+	start bool
+}
+
+// Helperfunction to create a blank textCanvas that fits the layout.
+func newTextCanvas(fc *Flowchart, l *layout, r *TextRenderer) (c *textCanvas) {
+	c = &textCanvas{fc: fc, layout: l, lines: unicodeLines,
+		shapes: unicodeShapes, ellipsis: '…', color: r.Color,
+		arrows: map[uint8]rune{lineUp: '▲', lineRight: '►', lineDown: '▼',
+			lineLeft: '◄'},
+		horizontal: l.horizontal()}
+	if r.ASCII {
+		c.lines, c.shapes, c.ellipsis = asciiLines, asciiShapes, '~'
+		c.arrows = map[uint8]rune{lineUp: '^', lineRight: '>', lineDown: 'v',
+			lineLeft: '<'}
+	}
+	c.cells = make([][]textCell, int(math.Ceil(l.height))+1)
+	for y := range c.cells {
+		c.cells[y] = make([]textCell, int(math.Ceil(l.width))+3)
+		for x := range c.cells[y] {
+			c.cells[y][x].r = ' '
+		}
+	}
+	return
+}
+
+// Helperfunction to get a cell, nil if it is outside of the canvas.
+func (c *textCanvas) cell(p [2]int) (tc *textCell) {
+	if p[1] < 0 || p[1] >= len(c.cells) || p[0] < 0 ||
+		p[0] >= len(c.cells[p[1]]) {
+		return nil
+	}
+	return &c.cells[p[1]][p[0]]
+}
+
+// Helperfunction to set a character.
+func (c *textCanvas) put(p [2]int, r rune, fg, bg string) {
+	if tc := c.cell(p); tc != nil {
+		*tc = textCell{r: r, fg: fg, bg: bg}
+	}
+}
+
+// Helperfunction to write a line of text starting at p.
+func (c *textCanvas) write(p [2]int, text, fg, bg string) {
+	for _, r := range text {
+		c.put(p, r, fg, bg)
+		p[0]++
+	}
+}
+
+// Helperfunction to get the ANSI escape sequence for a CSS color value, empty
+// if colors are disabled or the value isn't a valid color.
+func (c *textCanvas) ansi(value string, background bool) (sequence string) {
+	if !c.color || value == "" {
+		return ""
+	}
+	return mermaidgen.Color(value).ANSI(background)
+}
+
+// Helperfunction to draw a straight line from a to b. Lines merge with other
+// lines to corners and junctions.
+func (c *textCanvas) line(a, b [2]int, weight int, fg string) {
+	dx, dy := sign(b[0]-a[0]), sign(b[1]-a[1])
+	forward, backward := lineDown, lineUp
+	switch {
+	case dx > 0:
+		forward, backward = lineRight, lineLeft
+	case dx < 0:
+		forward, backward = lineLeft, lineRight
+	case dy < 0:
+		forward, backward = lineUp, lineDown
+	}
+	for p := a; ; p = [2]int{p[0] + dx, p[1] + dy} {
+		if tc := c.cell(p); tc != nil {
+			if tc.r != 0 {
+				tc.r, tc.mask = 0, 0
+			}
+			if p != a {
+				tc.mask |= backward
+			}
+			if p != b {
+				tc.mask |= forward
+			}
+			tc.weight, tc.fg = weight, fg
+		}
+		if p == b {
+			return
+		}
+	}
+}
+
+// Helperfunction to get the sign of an int.
+func sign(v int) (s int) {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+// Helperfunction to render the canvas, optionally limited to width.
+func (c *textCanvas) bytes(width int) (text []byte) {
+	var b bytes.Buffer
+	blank := 0
+	for _, row := range c.cells {
+		end := 0
+		for x, tc := range row {
+			if tc.r != ' ' && (tc.r != 0 || tc.mask != 0) || tc.bg != "" {
+				end = x + 1
+			}
+		}
+		row = row[:end]
+		if end == 0 {
+			blank++
+			continue
+		}
+		for ; blank > 0; blank-- {
+			b.WriteByte('\n')
+		}
+		cut := width > 0 && len(row) > width
+		if cut {
+			row = row[:width]
+		}
+		current := ""
+		for x, tc := range row {
+			if sequence := tc.fg + tc.bg; sequence != current {
+				if current != "" {
+					b.WriteString("\x1b[0m")
+				}
+				b.WriteString(sequence)
+				current = sequence
+			}
+			r := tc.r
+			if r == 0 {
+				r = c.lines[tc.weight][tc.mask]
+			}
+			if cut && x == len(row)-1 {
+				r = c.ellipsis
+			}
+			b.WriteRune(r)
+		}
+		if current != "" {
+			b.WriteString("\x1b[0m")
+		}
+		b.WriteByte('\n')
+	}
+	return b.Bytes()
+}
+
+////////// elements ////////////////////////////////////////////////////////////
+
+// Helperfunction to get the cells covered by a Node, dummy or Subgraph block.
+func textBox(n *layoutNode) (x0, y0, x1, y1 int) {
+	x0 = int(math.Floor(n.x - n.w/2 + 0.5))
+	y0 = int(math.Floor(n.y - n.h/2 + 0.5))
+	return x0, y0, x0 + int(n.w) - 1, y0 + int(n.h) - 1
+}
+
+// Helperfunction to get the cell of a point.
+func textPoint(x, y float64) (p [2]int) {
+	return [2]int{int(math.Floor(x)), int(math.Floor(y))}
+}
+
+// Helperfunction to draw a Subgraph's box.
+func (c *textCanvas) drawCluster(cl *layoutCluster) {
+	style := c.fc.subgraphStyle(cl.subgraph)
+	fg := c.ansi(property(style.shape, "stroke"), false)
+	x0 := int(math.Floor(cl.x + 0.5))
+	y0 := int(math.Floor(cl.y + 0.5))
+	x1 := int(math.Floor(cl.x+cl.w+0.5)) - 1
+	y1 := int(math.Floor(cl.y+cl.h+0.5)) - 1
+	c.drawBox(x0, y0, x1, y1, c.shapes[NShapeRect], fg, "", false)
+}
+
+// Helperfunction to draw a Subgraph's title, after the Edges crossing it.
+func (c *textCanvas) drawTitle(cl *layoutCluster) {
+	style := c.fc.subgraphStyle(cl.subgraph)
+	c.write(textPoint(cl.x+2.5, cl.y+0.5), " "+subgraphTitle(cl.subgraph)+" ",
+		c.ansi(property(style.text, "fill"), false), "")
+}
+
+// Helperfunction to draw a box border, optionally filled.
+func (c *textCanvas) drawBox(x0, y0, x1, y1 int, shape []rune, fg, bg string,
+	fill bool) {
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			r := ' '
+			switch {
+			case y == y0 && x == x0:
+				r = shape[0]
+			case y == y0 && x == x1:
+				r = shape[1]
+			case y == y1 && x == x0:
+				r = shape[2]
+			case y == y1 && x == x1:
+				r = shape[3]
+			case y == y0 || y == y1:
+				r = shape[4]
+			case x == x0:
+				r = shape[5]
+			case x == x1:
+				r = shape[6]
+			case !fill:
+				continue
+			}
+			c.put([2]int{x, y}, r, fg, bg)
+		}
+	}
+}
+
+// Helperfunction to draw a Node with its text.
+func (c *textCanvas) drawNode(ln *layoutNode) {
+	n := ln.node
+	style := c.fc.nodeStyle(n)
+	bg := c.ansi(property(style.shape, "fill"), true)
+	shape, ok := c.shapes[n.Shape]
+	if !ok {
+		shape = c.shapes[NShapeRect]
+	}
+	x0, y0, x1, _ := textBox(ln)
+	c.drawBox(x0, y0, x1, y0+int(ln.h)-1, shape,
+		c.ansi(property(style.shape, "stroke"), false), bg, true)
+	fg := c.ansi(property(style.text, "fill"), false)
+	left := x0 + 2
+	if n.Shape == NShapeFlagLeft {
+		left++
+	}
+	for i, line := range nodeLines(n) {
+		offset := (x1 - 1 - left - utf8.RuneCountInString(line)) / 2
+		c.write([2]int{left + offset, y0 + 1 + i}, line, fg, bg)
+	}
+}
+
+// Helperfunction to find the side of a Node that faces p, as the direction
+// from a cell at that side to the Node.
+func (c *textCanvas) side(n *layoutNode, p [2]int) (side uint8) {
+	x0, y0, x1, y1 := textBox(n)
+	if c.horizontal {
+		switch {
+		case p[0] > x1:
+			return lineLeft
+		case p[0] < x0:
+			return lineRight
+		case p[1] > y1:
+			return lineUp
+		}
+		return lineDown
+	}
+	switch {
+	case p[1] > y1:
+		return lineUp
+	case p[1] < y0:
+		return lineDown
+	case p[0] > x1:
+		return lineLeft
+	}
+	return lineRight
+}
+
+// Helperfunction to get the cell next to a Node at the given side, along is
+// the coordinate along that side.
+func portCell(n *layoutNode, side uint8, along int) (cell [2]int) {
+	x0, y0, x1, y1 := textBox(n)
+	switch side {
+	case lineUp:
+		return [2]int{along, y1 + 1}
+	case lineDown:
+		return [2]int{along, y0 - 1}
+	case lineLeft:
+		return [2]int{x1 + 1, along}
+	}
+	return [2]int{x0 - 1, along}
+}
+
+// Helperfunction to find the cell next to a Node where an Edge towards p
+// starts or ends. It is on the side facing p, preferably in line with p.
+// side is the direction from the cell to the Node.
+func (c *textCanvas) port(n *layoutNode, p [2]int) (cell [2]int, side uint8) {
+	x0, y0, x1, y1 := textBox(n)
+	x, y := x0+(x1-x0)/2, y0+(y1-y0)/2
+	if x0 < p[0] && p[0] < x1 {
+		x = p[0]
+	}
+	if y0 < p[1] && p[1] < y1 {
+		y = p[1]
+	}
+	side = c.side(n, p)
+	if side == lineUp || side == lineDown {
+		return portCell(n, side, x), side
+	}
+	return portCell(n, side, y), side
+}
+
+// Helperfunction to get the dummy cells of an Edge from source to target.
+func (c *textCanvas) dummies(le *layoutEdge) (cells [][2]int) {
+	for i := range le.dummies {
+		d := le.dummies[i]
+		if le.reversed {
+			d = le.dummies[len(le.dummies)-1-i]
+		}
+		cells = append(cells, textPoint(d.x, d.y))
+	}
+	return
+}
+
+// Helperfunction to spread the ends of Edges sharing a side of a Node over
+// that side, ordered by where the Edges are going, so they don't overlap.
+func (c *textCanvas) assignPorts() {
+	type group struct {
+		node  *layoutNode
+		side  uint8
+		ports []textPortKey
+		along []int // coordinate of the other end of the Edge along the side
+	}
+	groups := make(map[[2]interface{}]*group)
+	var order []*group
+	add := func(n *layoutNode, p [2]int, key textPortKey) {
+		side := c.side(n, p)
+		g := groups[[2]interface{}{n, side}]
+		if g == nil {
+			g = &group{node: n, side: side}
+			groups[[2]interface{}{n, side}] = g
+			order = append(order, g)
+		}
+		along := p[1]
+		if side == lineUp || side == lineDown {
+			along = p[0]
+		}
+		g.ports = append(g.ports, key)
+		g.along = append(g.along, along)
+	}
+	for _, le := range c.layout.edges {
+		from, to := c.layout.byNode[le.edge.From], c.layout.byNode[le.edge.To]
+		if from == to {
+			continue
+		}
+		next, prev := textPoint(to.x, to.y), textPoint(from.x, from.y)
+		if dummies := c.dummies(le); len(dummies) > 0 {
+			next, prev = dummies[0], dummies[len(dummies)-1]
+		}
+		add(from, next, textPortKey{le.edge, true})
+		add(to, prev, textPortKey{le.edge, false})
+	}
+	c.ports = make(map[textPortKey][2]int)
+	for _, g := range order {
+		k := len(g.ports)
+		if k < 2 {
+			continue
+		}
+		indices := make([]int, k)
+		for i := range indices {
+			indices[i] = i
+		}
+		sort.SliceStable(indices, func(i, j int) bool {
+			return g.along[indices[i]] < g.along[indices[j]]
+		})
+		// top and bottom without the corners, left and right including them
+		x0, y0, x1, y1 := textBox(g.node)
+		first, n := y0, y1-y0+1
+		if g.side == lineUp || g.side == lineDown {
+			first, n = x0+1, x1-x0-1
+		}
+		for i, index := range indices {
+			c.ports[g.ports[index]] = portCell(g.node, g.side,
+				first+(2*i+1)*n/(2*k))
+		}
+	}
+}
+
+// Helperfunction to connect a and b with orthogonal lines. Routes leave a in
+// the level direction and turn right away, unless sideways is set.
+func (c *textCanvas) connect(a, b [2]int, sideways bool) (points [][2]int) {
+	if a[0] == b[0] || a[1] == b[1] {
+		return [][2]int{a, b}
+	}
+	level := 1 // index of the level coordinate
+	if c.horizontal {
+		level = 0
+	}
+	if sideways {
+		corner := b
+		corner[level] = a[level]
+		return [][2]int{a, corner, b}
+	}
+	first := a
+	first[level] += sign(b[level] - a[level])
+	if first[level] == b[level] {
+		return [][2]int{a, first, b}
+	}
+	second := b
+	second[level] = first[level]
+	return [][2]int{a, first, second, b}
+}
+
+// Helperfunction to draw an Edge, it returns the center of the Edge's text.
+func (c *textCanvas) drawEdge(le *layoutEdge) (label [2]int) {
+	e := le.edge
+	from, to := c.layout.byNode[e.From], c.layout.byNode[e.To]
+	fg := c.ansi(property(c.fc.edgeStyle(e).shape, "stroke"), false)
+	weight, arrow := lineNormal, false
+	switch e.Shape {
+	case EShapeArrow, "":
+		arrow = true
+	case EShapeDottedArrow:
+		weight, arrow = lineDotted, true
+	case EShapeThickArrow:
+		weight, arrow = lineThick, true
+	case EShapeDottedLine:
+		weight = lineDotted
+	case EShapeThickLine:
+		weight = lineThick
+	}
+	var points [][2]int
+	var exit, entry uint8
+	if from == to {
+		// loop at the right side of the Node
+		_, y0, x1, y1 := textBox(from)
+		y := y0 + (y1-y0)/2
+		points = [][2]int{{x1 + 1, y}, {x1 + 2, y}, {x1 + 2, y1 + 1},
+			{x1 - 1, y1 + 1}}
+		exit, entry = lineLeft, lineUp
+		label = [2]int{x1 + 4 + textWidth(e.Text)/2, y}
+	} else {
+		dummies := c.dummies(le)
+		next := textPoint(to.x, to.y)
+		if len(dummies) > 0 {
+			next = dummies[0]
+		}
+		var start, end [2]int
+		start, exit = c.port(from, next)
+		if cell, ok := c.ports[textPortKey{e, true}]; ok {
+			start = cell
+		}
+		prev := start
+		if len(dummies) > 0 {
+			prev = dummies[len(dummies)-1]
+		}
+		if cell, ok := c.ports[textPortKey{e, false}]; ok {
+			// spread ends were assigned by the source's center
+			if len(dummies) == 0 {
+				prev = textPoint(from.x, from.y)
+			}
+			_, entry = c.port(to, prev)
+			end = cell
+		} else {
+			end, entry = c.port(to, prev)
+		}
+		waypoints := append(append([][2]int{start}, dummies...), end)
+		points = [][2]int{start}
+		sideways := exit == lineLeft || exit == lineRight
+		if c.horizontal {
+			sideways = exit == lineUp || exit == lineDown
+		}
+		if le.reversed {
+			// turn next to the target, as if routed from there
+			points = [][2]int{end}
+			for i := len(waypoints) - 1; i > 0; i-- {
+				route := c.connect(waypoints[i], waypoints[i-1], false)
+				points = append(points, route[1:]...)
+			}
+			for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+				points[i], points[j] = points[j], points[i]
+			}
+		} else {
+			for i := 1; i < len(waypoints); i++ {
+				route := c.connect(waypoints[i-1], waypoints[i],
+					sideways && i == 1)
+				points = append(points, route[1:]...)
+			}
+		}
+		if len(le.dummies) > 0 {
+			mid := le.dummies[(len(le.dummies)-1)/2]
+			label = textPoint(mid.x, mid.y)
+		} else {
+			// the middle of the last straight line
+			last := points[len(points)-1]
+			for i := len(points) - 2; i >= 0 && points[i] == last; i-- {
+				points = points[:i+1]
+			}
+			a := points[0]
+			if len(points) > 1 {
+				a = points[len(points)-2]
+			}
+			label = [2]int{(a[0] + last[0]) / 2, (a[1] + last[1]) / 2}
+		}
+	}
+	for i := 1; i < len(points); i++ {
+		c.line(points[i-1], points[i], weight, fg)
+	}
+	if tc := c.cell(points[0]); tc != nil && tc.r == 0 {
+		tc.mask |= exit
+	}
+	end := points[len(points)-1]
+	if arrow {
+		c.put(end, c.arrows[entry], fg, "")
+	} else if tc := c.cell(end); tc != nil && tc.r == 0 {
+		tc.mask |= entry
+	}
+	return
+}
+
+// Helperfunction to draw an Edge's text centered at p.
+func (c *textCanvas) drawLabel(e *Edge, p [2]int) {
+	if len(e.Text) == 0 {
+		return
+	}
+	fg := c.ansi(property(c.fc.edgeStyle(e).text, "fill"), false)
+	top := p[1] - (len(e.Text)-1)/2
+	for i, line := range e.Text {
+		text := " " + line + " "
+		c.write([2]int{p[0] - utf8.RuneCountInString(text)/2, top + i}, text,
+			fg, "")
+	}
+}
@@ -0,0 +1,120 @@
+package flowchart_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Heiko-san/mermaidgen/flowchart"
+	"github.com/Heiko-san/mermaidgen/gantt"
+)
+
+// Rendering Flowcharts as text for terminals
+func ExampleFlowchart_Text() {
+	f, _ := flowchart.Parse(`flowchart TD
+	    start(Start) --> check{Ready?}
+	    check -->|yes| go[Deploy]
+	    check -.->|no| wait[Wait]
+	    wait --> check`)
+	fmt.Println("Release process:")
+	f.Text(os.Stdout)
+	//Output:
+	//Release process:
+	//          ╭───────╮
+	//          │ Start │
+	//          ╰───────╯
+	//              │
+	//              │
+	//              ▼
+	//          ╱────────╲
+	//          < Ready? >
+	//          ╲────────╱
+	//            │  ┆ ▲
+	//     ┌──────┘  └┄┼──┐
+	//    yes         no  │
+	//     ▼           ▼  │
+	//┌────────┐    ┌──────┐
+	//│ Deploy │    │ Wait │
+	//└────────┘    └──────┘
+}
+
+func TestTextRenderer_RenderDiagram(t *testing.T) {
+	f, err := flowchart.Parse(`flowchart LR
+	    a[Alpha] ==> b((Beta))
+	    b --- c>Gamma]
+	    subgraph sg [Group]
+	      c --> d{Delta}
+	    end
+	    d --> d
+	    style b fill:#f9f,stroke:#333,color:red
+	    linkStyle 0 stroke:blue`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, direction := range []string{"TB", "BT", "LR", "RL"} {
+		f.Direction = flowchart.DirectionTopDown
+		switch direction {
+		case "BT":
+			f.Direction = flowchart.DirectionBottomUp
+		case "LR":
+			f.Direction = flowchart.DirectionLeftRight
+		case "RL":
+			f.Direction = flowchart.DirectionRightLeft
+		}
+		var b bytes.Buffer
+		r := &flowchart.TextRenderer{ASCII: true}
+		if err = r.RenderDiagram(context.Background(), f, &b); err != nil {
+			t.Fatalf("%s: unexpected error: %s", direction, err)
+		}
+		text := b.String()
+		for _, expected := range []string{"Alpha", "Beta", "Gamma", "Delta",
+			"Group", "(", "<"} {
+			if !strings.Contains(text, expected) {
+				t.Errorf("%s: %q missing in\n%s", direction, expected, text)
+			}
+		}
+		for _, r := range text {
+			if r > utf8.RuneSelf {
+				t.Errorf("%s: non ASCII character %q in\n%s", direction, r, text)
+				break
+			}
+		}
+		if strings.Contains(text, "\x1b[") {
+			t.Errorf("%s: unexpected color in\n%s", direction, text)
+		}
+	}
+	f.Direction = flowchart.DirectionTopDown
+	var b bytes.Buffer
+	r := &flowchart.TextRenderer{Width: 12, Color: true}
+	if err = r.RenderDiagram(context.Background(), f, &b); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"\x1b[48;2;255;153;255m", // fill
+		"\x1b[38;2;51;51;51m",    // stroke
+		"\x1b[38;2;255;0;0m",     // color
+		"\x1b[38;2;0;0;255m",     // linkStyle
+	} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("%q missing", expected)
+		}
+	}
+	for _, line := range strings.Split(b.String(), "\n") {
+		plain := line
+		for strings.Contains(plain, "\x1b[") {
+			i := strings.Index(plain, "\x1b[")
+			plain = plain[:i] + plain[i+strings.Index(plain[i:], "m")+1:]
+		}
+		if utf8.RuneCountInString(plain) > 12 {
+			t.Errorf("line exceeds width: %q", plain)
+		}
+	}
+	g, _ := gantt.NewGantt()
+	if err = r.RenderDiagram(context.Background(), g, &b); err == nil {
+		t.Error("no error for a Gantt diagram")
+	}
+}
@@ -25,6 +25,11 @@ Which then creates:
 	myNodeId2["myNodeId2"]
 	myNodeId1 --> myNodeId2
 
+Large graphs are better streamed to a file or network connection directly, this
+takes time linear to the size of the graph.
+
+	chart.WriteTo(file)
+
 The package supports defining CSS styles, that can be assigned to Nodes and
 Edges.
 
@@ -34,8 +39,8 @@ Edges.
 	es1 := chart.EdgeStyle("myStyleId1")
 	es1.Stroke = "#f00"
 	es1.StrokeWidth = 2
-	node1.Style = ns1
-	node2.Style = chart.NodeStyle("myStyleId1")
+	node1.AddStyles(ns1)
+	node2.AddStyles(chart.NodeStyle("myStyleId1"))
 	edge1.Style = es1
 
 There are various useful constants and further styling options, too.
@@ -63,6 +68,32 @@ What we have by now is:
 	myNodeId1 === myNodeId2
 	linkStyle 0 stroke-width:2px,stroke:#f00
 
+Where mermaid itself isn't available, the Flowchart can be rendered to an SVG
+image in pure Go, too. For a quick look in the terminal, it can be drawn as
+text.
+
+	chart.SVG(file)
+	chart.Text(os.Stdout)
+
+Graphs from Graphviz tools can be imported with FromDOT, and any Flowchart can
+be exported as DOT for further processing.
+
+	chart, err := flowchart.FromDOT(dotFile)
+	chart.DOT(os.Stdout)
+
+Code that is committed to repositories gets more readable with indented
+Subgraphs, lines grouped by purpose and comments.
+
+	chart.Indent = "  "
+	chart.Grouped = true
+	node1.Comment = "explains myNodeId1"
+
+To store Flowcharts in files or exchange them between services, they can be
+encoded to JSON (or YAML using a YAML library) and decoded without any loss,
+see SchemaVersion.
+
+	data, err := json.Marshal(chart)
+
 And there is more. Just explore the package. Start at Flowchart and proceed to
 Subgraph.
 */
@@ -1,14 +1,14 @@
 package gantt
 
 import (
-	"bytes"
-	"compress/zlib"
-	"encoding/base64"
-	"encoding/json"
+	"bufio"
 	"fmt"
-	"os/exec"
-	"runtime"
+	"io"
 	"sort"
+	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/internal/counting"
 )
 
 ////////// AxisFormat //////////////////////////////////////////////////////////
@@ -42,6 +42,8 @@ type Gantt struct {
 	tasks       []*Task             // Section-less Task items
 	Title       string              // Title of the Gantt diagram
 	AxisFormat  axisFormat          // Optional time format for x axis
+	Config      *mermaidgen.Config  // Optional theme and configuration
+	Indent      string              // Optional indentation of Section's Tasks
 }
 
 // NewGantt is the constructor used to create a new Gantt object.
@@ -73,61 +75,119 @@ func NewGantt(init ...interface{}) (newGantt *Gantt, err error) {
 }
 
 // String recursively renders the whole diagram to mermaid code lines.
+// It is a shorthand for WriteTo with a strings.Builder.
 func (g *Gantt) String() (renderedElement string) {
-	renderedElement = "gantt\ndateFormat YYYY-MM-DDTHH:mm:ssZ\n"
+	return renderString("", g.writeDiagram)
+}
+
+// WriteTo renders the whole diagram to mermaid code lines in a single pass
+// over the Sections and Tasks and writes them to w, so the rendering time grows
+// linearly with the number of Tasks. The Tasks of Sections are indented by
+// Indent, e.g. "  " or "\t". Implements io.WriterTo.
+func (g *Gantt) WriteTo(w io.Writer) (n int64, err error) {
+	cw := &counting.Writer{W: w}
+	b := bufio.NewWriter(cw)
+	g.writeDiagram(&diagramWriter{Writer: b})
+	err = b.Flush()
+	return cw.N, err
+}
+
+// Helperfunction for WriteTo and String, renders the whole diagram to w.
+// Write errors are kept by w and reported by its Flush method.
+func (g *Gantt) writeDiagram(w *diagramWriter) {
+	w.indent = g.Indent
+	w.WriteString(g.Config.String())
+	w.WriteString("gantt\ndateFormat YYYY-MM-DDTHH:mm:ssZ\n")
 	if g.AxisFormat != "" {
-		renderedElement += fmt.Sprintln("axisFormat", g.AxisFormat)
+		fmt.Fprintln(w, "axisFormat", g.AxisFormat)
 	}
 	if g.Title != "" {
-		renderedElement += fmt.Sprintln("title", g.Title)
+		fmt.Fprintln(w, "title", g.Title)
 	}
 	for _, t := range g.tasks {
-		renderedElement += t.String()
+		t.writeDiagram(w)
 	}
 	for _, s := range g.sections {
-		renderedElement += s.String()
+		s.writeDiagram(w)
 	}
+}
+
+// Render writes the mermaid code of the whole diagram to w.
+// Implements mermaidgen.Diagram.
+func (g *Gantt) Render(w io.Writer) (err error) {
+	_, err = g.WriteTo(w)
 	return
 }
 
-// Structs for JSON encode
-type mermaidJSON struct {
-	Theme string `json:"theme"`
+// Kind returns mermaidgen.KindGantt. Implements mermaidgen.Diagram.
+func (g *Gantt) Kind() (kind mermaidgen.DiagramKind) {
+	return mermaidgen.KindGantt
+}
+
+// GetConfig returns the Gantt's Config, which may be nil.
+// Implements mermaidgen.Diagram.
+func (g *Gantt) GetConfig() (config *mermaidgen.Config) {
+	return g.Config
 }
 
-type dataJSON struct {
-	Code    string      `json:"code"`
-	Mermaid mermaidJSON `json:"mermaid"`
+// SetConfig replaces the Gantt's Config, nil removes it.
+// Implements mermaidgen.Diagram.
+func (g *Gantt) SetConfig(config *mermaidgen.Config) {
+	g.Config = config
 }
 
 // LiveURL renders the Gantt and generates a view URL for
-// https://mermaidjs.github.io/mermaid-live-editor from it.
+// https://mermaid.live from it, see mermaidgen.LiveURL for details.
 func (g *Gantt) LiveURL() (url string) {
-	liveURL := `https://mermaid.live/view/#pako:`
-	data, _ := json.Marshal(dataJSON{
-		Code: g.String(), Mermaid: mermaidJSON{Theme: "default"},
-	})
-	var b bytes.Buffer
-	w, _ := zlib.NewWriterLevel(&b, zlib.BestCompression)
-	w.Write(data)
-	w.Close()
-	return liveURL + base64.URLEncoding.EncodeToString(b.Bytes())
+	url, _ = mermaidgen.LiveURL(g)
+	return
 }
 
 // ViewInBrowser uses the URL generated by Gantt's LiveURL method and opens
-// that URL in the OS's default browser. It starts the browser command
-// non-blocking and eventually returns any error occured.
+// that URL in the OS's default browser via mermaidgen.ViewInBrowser. It
+// eventually returns any error occured.
 func (g *Gantt) ViewInBrowser() (err error) {
-	switch runtime.GOOS {
-	case "openbsd", "linux":
-		return exec.Command("xdg-open", g.LiveURL()).Start()
-	case "darwin":
-		return exec.Command("open", g.LiveURL()).Start()
-	case "windows":
-		return exec.Command("rundll32", "url.dll,FileProtocolHandler",
-			g.LiveURL()).Start()
-	default:
-		return fmt.Errorf("unsupported platform")
+	return mermaidgen.ViewInBrowser(g)
+}
+
+////////// rendering helpers ///////////////////////////////////////////////////
+
+// Helperfunction to deduplicate code, renders a single element to a string
+// using the given indentation of Section's Tasks.
+func renderString(indent string,
+	write func(w *diagramWriter)) (renderedElement string) {
+	var s strings.Builder
+	b := bufio.NewWriter(&s)
+	write(&diagramWriter{Writer: b, indent: indent})
+	b.Flush()
+	return s.String()
+}
+
+// Buffered writer used for rendering, it knows the indentation of the current
+// line.
+type diagramWriter struct {
+	*bufio.Writer
+	indent string // indentation per nesting level
+	level  int    // current nesting level
+}
+
+// Helperfunction to start a new line, writes the indentation of the current
+// nesting level.
+func (w *diagramWriter) startLine() {
+	for i := 0; i < w.level; i++ {
+		w.WriteString(w.indent)
+	}
+}
+
+// Helperfunction to write a comment attached to an item, each line of the
+// text becomes a %% line.
+func (w *diagramWriter) comment(text string) {
+	if text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		w.startLine()
+		w.WriteString(strings.TrimRight("%% "+line, " \r") + "\n")
 	}
 }
 
@@ -1,10 +1,14 @@
 package gantt_test
 
 import (
+	"bytes"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/Heiko-san/mermaidgen"
 	"github.com/Heiko-san/mermaidgen/gantt"
 )
 
@@ -105,7 +109,7 @@ func ExampleGantt_liveEditorLinks() {
 	g.AddTask("t2", "another task", "2h")
 	// you can also use g.ViewInBrowser() to open the URL in browser directly
 	fmt.Println(g.LiveURL())
-	// Output: https://mermaid.live/view/#pako:eNo0zbEKg0AMgOFXOTJ7EJVazCzFxc3lxCX00lranOClk_jupYWuPz98O1zXKEBw52Q2p8gml3VTNhdCCH4YfNeNfU-qlPM0J3bG-enIWVm4CsvWY-MrHLGl8kQ1ToWrG8Q8J06rLbL9_3P1q1CAyqb8iEA72CL61aPc-P0yOI5PAAAA___xki0z
+	// Output: https://mermaid.live/view/#pako:eNo0zbEKg0AMgOFXOTJ7EJVazCzFxc3lxCX00lranOClk_jupYWuPz98O1zXKEBw52Q2p8gml3VTNhdCCH4YfNeNfU-qlPM0J3bG-enIWVm4CsvWY-MrHLGl8kQ1ToWrG8Q8J06rLbL9_3P1q1CAyqb8iEA72CL61aPc-P0yOI7PAPGSLTM=
 }
 
 // The creation of Gantts, Sections and Tasks may yield errors
@@ -160,3 +164,101 @@ func TestGanttAddDuplicateSection(t *testing.T) {
 	assert(t, s2 == nil)
 	assert(t, err != nil)
 }
+
+// Defining a theme and further configuration
+func ExampleGantt_config() {
+	g, _ := gantt.NewGantt()
+	g.Config, _ = mermaidgen.NewConfig(mermaidgen.ThemeNeutral,
+		mermaidgen.SyntaxDirective)
+	g.Config.Gantt = &mermaidgen.GanttConfig{BarHeight: 30, LeftPadding: 100}
+	fmt.Print(g)
+	//Output:
+	//%%{init: {"theme":"neutral","gantt":{"barHeight":30,"leftPadding":100}}}%%
+	//gantt
+	//dateFormat YYYY-MM-DDTHH:mm:ssZ
+}
+
+// Streaming a diagram to a file or network connection
+func ExampleGantt_WriteTo() {
+	g, _ := gantt.NewGantt()
+	g.AddTask("t1", "Task 1")
+	n, err := g.WriteTo(os.Stdout)
+	fmt.Println(n, err)
+	//Output:
+	//gantt
+	//dateFormat YYYY-MM-DDTHH:mm:ssZ
+	//Task 1 : 1d
+	//50 <nil>
+}
+
+func TestGantt_WriteTo(t *testing.T) {
+	g := largeGantt(1000)
+	var b bytes.Buffer
+	n, err := g.WriteTo(&b)
+	assert(t, err == nil)
+	assert(t, n == int64(b.Len()))
+	assert(t, b.String() == g.String())
+}
+
+// Helperfunction to build a plan with the given number of chained Tasks spread
+// over Sections.
+func largeGantt(tasks int) (g *gantt.Gantt) {
+	g, _ = gantt.NewGantt("Release plan")
+	start := time.Date(2019, 6, 20, 0, 0, 0, 0, time.UTC)
+	var s *gantt.Section
+	var previous *gantt.Task
+	for i := 0; i < tasks; i++ {
+		if i%100 == 0 {
+			s, _ = g.AddSection(fmt.Sprintf("Milestone %d", i/100))
+		}
+		task, _ := s.AddTask(fmt.Sprintf("t%d", i), fmt.Sprintf("Task %d", i),
+			"36h")
+		if previous == nil {
+			task.Start = &start
+		} else {
+			task.After = previous
+		}
+		task.Critical = i%7 == 0
+		previous = task
+	}
+	return
+}
+
+// The ns/op of the sub-benchmarks grow linearly with the number of Tasks.
+func BenchmarkGantt_WriteTo(b *testing.B) {
+	for _, tasks := range []int{1000, 5000, 20000} {
+		g := largeGantt(tasks)
+		b.Run(fmt.Sprintf("tasks=%d", tasks), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				g.WriteTo(ioutil.Discard)
+			}
+		})
+	}
+}
+
+// Readable output with indentation and comments
+func ExampleGantt_prettyPrint() {
+	g, _ := gantt.NewGantt("Release")
+	g.Indent = "    "
+	s, _ := g.AddSection("Development")
+	s.Comment = "owned by the core team"
+	t1, _ := s.AddTask("code", "Coding", "48h")
+	t1.Comment = "including reviews"
+	t2, _ := s.AddTask("test", "Testing", "24h", t1)
+	t2.Comment = "manual tests\nand load tests"
+	fmt.Print(g)
+	parsed, _ := gantt.Parse(g.String())
+	fmt.Printf("%q\n", parsed.GetTask("test").Comment)
+	//Output:
+	//gantt
+	//dateFormat YYYY-MM-DDTHH:mm:ssZ
+	//title Release
+	//%% owned by the core team
+	//section Development
+	//     %% including reviews
+	//     Coding : 172800s
+	//     %% manual tests
+	//     %% and load tests
+	//     Testing : test, after code, 86400s
+	//"manual tests\nand load tests"
+}
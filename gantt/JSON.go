@@ -0,0 +1,207 @@
+package gantt
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Heiko-san/mermaidgen"
+)
+
+// SchemaVersion is the version of the JSON and YAML schema written by Gantt's
+// MarshalJSON and MarshalYAML methods. It is increased on incompatible
+// changes, documents of other versions are rejected.
+const SchemaVersion = 1
+
+// Schema of a Gantt diagram. Tasks are referenced by their IDs.
+type jsonGantt struct {
+	Version      int                `json:"version" yaml:"version"`
+	Kind         string             `json:"kind" yaml:"kind"`
+	Title        string             `json:"title,omitempty" yaml:"title,omitempty"`
+	AxisFormat   axisFormat         `json:"axisFormat,omitempty" yaml:"axisFormat,omitempty"`
+	Config       *mermaidgen.Config `json:"config,omitempty" yaml:"config,omitempty"`
+	ConfigSyntax string             `json:"configSyntax,omitempty" yaml:"configSyntax,omitempty"`
+	ConfigTitle  string             `json:"configTitle,omitempty" yaml:"configTitle,omitempty"`
+	Indent       string             `json:"indent,omitempty" yaml:"indent,omitempty"`
+	Tasks        []jsonTask         `json:"tasks,omitempty" yaml:"tasks,omitempty"`
+	Sections     []jsonSection      `json:"sections,omitempty" yaml:"sections,omitempty"`
+}
+
+// Schema of a Section.
+type jsonSection struct {
+	ID      string     `json:"id" yaml:"id"`
+	Tasks   []jsonTask `json:"tasks,omitempty" yaml:"tasks,omitempty"`
+	Comment string     `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+// Schema of a Task, Duration is written like "36h0m0s".
+type jsonTask struct {
+	ID       string     `json:"id" yaml:"id"`
+	Title    string     `json:"title,omitempty" yaml:"title,omitempty"`
+	Start    *time.Time `json:"start,omitempty" yaml:"start,omitempty"`
+	After    string     `json:"after,omitempty" yaml:"after,omitempty"`
+	Duration string     `json:"duration,omitempty" yaml:"duration,omitempty"`
+	Critical bool       `json:"critical,omitempty" yaml:"critical,omitempty"`
+	Active   bool       `json:"active,omitempty" yaml:"active,omitempty"`
+	Done     bool       `json:"done,omitempty" yaml:"done,omitempty"`
+	Comment  string     `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+////////// encode //////////////////////////////////////////////////////////////
+
+// MarshalJSON encodes the whole Gantt diagram to a versioned JSON document
+// (see SchemaVersion), which can be decoded by UnmarshalJSON without any loss,
+// including the IDs, the order of all items and the After relations between
+// Tasks. An error is returned if a Task starts after a Task that doesn't belong
+// to this Gantt diagram. Implements json.Marshaler.
+func (g *Gantt) MarshalJSON() (data []byte, err error) {
+	s, err := g.schema()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(s)
+}
+
+// MarshalYAML returns the same structure as MarshalJSON for YAML libraries
+// like gopkg.in/yaml.v2 and gopkg.in/yaml.v3, which call it automatically.
+func (g *Gantt) MarshalYAML() (document interface{}, err error) {
+	return g.schema()
+}
+
+// Helperfunction to convert the Gantt diagram to its schema.
+func (g *Gantt) schema() (s *jsonGantt, err error) {
+	s = &jsonGantt{Version: SchemaVersion, Kind: string(mermaidgen.KindGantt),
+		Title: g.Title, AxisFormat: g.AxisFormat, Config: g.Config,
+		Indent: g.Indent}
+	if g.Config != nil {
+		s.ConfigSyntax, s.ConfigTitle = string(g.Config.Syntax), g.Config.Title
+	}
+	if s.Tasks, err = g.schemaTasks(g.tasks); err != nil {
+		return nil, err
+	}
+	for _, section := range g.sections {
+		js := jsonSection{ID: section.id, Comment: section.Comment}
+		if js.Tasks, err = g.schemaTasks(section.tasks); err != nil {
+			return nil, err
+		}
+		s.Sections = append(s.Sections, js)
+	}
+	return s, nil
+}
+
+// Helperfunction to convert Tasks.
+func (g *Gantt) schemaTasks(tasks []*Task) (schema []jsonTask, err error) {
+	for _, t := range tasks {
+		jt := jsonTask{ID: t.id, Title: t.Title, Start: t.Start,
+			Critical: t.Critical, Active: t.Active, Done: t.Done,
+			Comment: t.Comment}
+		if t.After != nil {
+			if g.tasksMap[t.After.id] != t.After {
+				return nil, fmt.Errorf("MarshalJSON: Task %q starts after a "+
+					"Task that doesn't belong to the Gantt diagram", t.id)
+			}
+			jt.After = t.After.id
+		}
+		if t.Duration != nil {
+			jt.Duration = t.Duration.String()
+		}
+		schema = append(schema, jt)
+	}
+	return
+}
+
+////////// decode //////////////////////////////////////////////////////////////
+
+// UnmarshalJSON replaces the Gantt diagram with the one decoded from a JSON
+// document as written by MarshalJSON. Unlike other Gantt diagrams, a zero value
+// Gantt may be used as target. An error is returned if the document has
+// another SchemaVersion or kind, or if it contains invalid or duplicate IDs or
+// unknown references. Implements json.Unmarshaler.
+func (g *Gantt) UnmarshalJSON(data []byte) (err error) {
+	var s jsonGantt
+	if err = json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("UnmarshalJSON: %s", err)
+	}
+	return g.fromSchema(&s)
+}
+
+// UnmarshalYAML is the counterpart of MarshalYAML for YAML libraries like
+// gopkg.in/yaml.v2 and gopkg.in/yaml.v3, see UnmarshalJSON for details.
+func (g *Gantt) UnmarshalYAML(unmarshal func(interface{}) error) (err error) {
+	var s jsonGantt
+	if err = unmarshal(&s); err != nil {
+		return err
+	}
+	return g.fromSchema(&s)
+}
+
+// Helperfunction to replace the Gantt diagram with the one described by a
+// schema.
+func (g *Gantt) fromSchema(s *jsonGantt) (err error) {
+	if s.Version != SchemaVersion {
+		return fmt.Errorf("UnmarshalJSON: unsupported schema version %d",
+			s.Version)
+	}
+	if s.Kind != string(mermaidgen.KindGantt) {
+		return fmt.Errorf("UnmarshalJSON: unexpected kind %q", s.Kind)
+	}
+	fresh, _ := NewGantt(s.Title, s.AxisFormat)
+	*g = *fresh
+	g.Config, g.Indent = s.Config, s.Indent
+	if g.Config != nil {
+		// the constructor converts the string to the unexported type
+		syntax, _ := mermaidgen.NewConfig(mermaidgen.ThemeDefault,
+			s.ConfigSyntax)
+		g.Config.Syntax, g.Config.Title = syntax.Syntax, s.ConfigTitle
+	}
+	after := make(map[*Task]string)
+	add := func(section *Section, jt jsonTask) (err error) {
+		var t *Task
+		if section == nil {
+			t, err = g.AddTask(jt.ID)
+		} else {
+			t, err = section.AddTask(jt.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("UnmarshalJSON: Task %q: %s", jt.ID, err)
+		}
+		t.Title, t.Start, t.Comment = jt.Title, jt.Start, jt.Comment
+		t.Critical, t.Active, t.Done = jt.Critical, jt.Active, jt.Done
+		if jt.Duration != "" {
+			d, err := time.ParseDuration(jt.Duration)
+			if err != nil {
+				return fmt.Errorf("UnmarshalJSON: Task %q: %s", jt.ID, err)
+			}
+			t.Duration = &d
+		}
+		if jt.After != "" {
+			after[t] = jt.After
+		}
+		return nil
+	}
+	for _, jt := range s.Tasks {
+		if err = add(nil, jt); err != nil {
+			return err
+		}
+	}
+	for _, js := range s.Sections {
+		section, err := g.AddSection(js.ID)
+		if err != nil {
+			return fmt.Errorf("UnmarshalJSON: Section %q: %s", js.ID, err)
+		}
+		section.Comment = js.Comment
+		for _, jt := range js.Tasks {
+			if err = add(section, jt); err != nil {
+				return err
+			}
+		}
+	}
+	// After may reference Tasks defined later
+	for t, id := range after {
+		if t.After = g.tasksMap[id]; t.After == nil {
+			return fmt.Errorf("UnmarshalJSON: Task %q starts after unknown "+
+				"Task %q", t.id, id)
+		}
+	}
+	return nil
+}
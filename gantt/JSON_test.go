@@ -0,0 +1,108 @@
+package gantt_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/gantt"
+)
+
+// Storing Gantt diagrams as JSON
+func ExampleGantt_MarshalJSON() {
+	g, _ := gantt.NewGantt("Release")
+	s, _ := g.AddSection("Development")
+	t1, _ := s.AddTask("code", "Write code", "48h",
+		time.Date(2019, 6, 20, 9, 0, 0, 0, time.UTC))
+	s.AddTask("review", "Review", "4h", t1)
+	data, _ := json.MarshalIndent(g, "", "  ")
+	fmt.Println(string(data))
+	//Output:
+	//{
+	//   "version": 1,
+	//   "kind": "gantt",
+	//   "title": "Release",
+	//   "sections": [
+	//     {
+	//       "id": "Development",
+	//       "tasks": [
+	//         {
+	//           "id": "code",
+	//           "title": "Write code",
+	//           "start": "2019-06-20T09:00:00Z",
+	//           "duration": "48h0m0s"
+	//         },
+	//         {
+	//           "id": "review",
+	//           "title": "Review",
+	//           "after": "code",
+	//           "duration": "4h0m0s"
+	//         }
+	//       ]
+	//     }
+	//   ]
+	//}
+}
+
+func TestGantt_UnmarshalJSON(t *testing.T) {
+	g, _ := gantt.NewGantt("Title", gantt.FormatTime24)
+	g.Config, _ = mermaidgen.NewConfig(mermaidgen.ThemeForest,
+		mermaidgen.SyntaxDirective)
+	g.Config.Title = "My chart"
+	t0, _ := g.AddTask("t0", "Top level", "2h",
+		time.Date(2019, 6, 20, 9, 15, 30, 0, time.UTC), true, true, true)
+	s1, _ := g.AddSection("Section 1")
+	t1, _ := s1.AddTask("t1", "", "90m")
+	s2, _ := g.AddSection("Section 2")
+	t2, _ := s2.AddTask("t2", "after a later Task")
+	t1.After = t0
+	t2.After = t1
+	t0.After = t2 // overridden by Start but kept
+	g.Indent = "  "
+	s2.Comment = "second\nsection"
+	t1.Comment = "depends on t0"
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var h gantt.Gantt
+	if err = json.Unmarshal(data, &h); err != nil {
+		t.Fatal(err)
+	}
+	if g.String() != h.String() {
+		t.Errorf("JSON round trip changed the diagram\n%s\n%s", g, &h)
+	}
+	if h.GetTask("t0").After != h.GetTask("t2") ||
+		h.GetTask("t1").Section() != h.GetSection("Section 1") ||
+		h.Config.Syntax != mermaidgen.SyntaxDirective ||
+		h.Config.Title != "My chart" {
+		t.Error("relations were not restored")
+	}
+	// YAML libraries hand the document to UnmarshalYAML via a callback
+	document, err := g.MarshalYAML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ = json.Marshal(document)
+	var y gantt.Gantt
+	err = y.UnmarshalYAML(func(v interface{}) error {
+		return json.Unmarshal(data, v)
+	})
+	if err != nil || g.String() != y.String() {
+		t.Errorf("YAML round trip changed the diagram (%v)\n%s\n%s", err, g, &y)
+	}
+	for _, doc := range []string{
+		`{"version": 0, "kind": "gantt"}`,
+		`{"version": 1, "kind": "flowchart"}`,
+		`{"version": 1, "kind": "gantt", "tasks": [{"id": "a b"}]}`,
+		`{"version": 1, "kind": "gantt", "tasks": [{"id": "a", "duration": "1x"}]}`,
+		`{"version": 1, "kind": "gantt", "tasks": [{"id": "a", "after": "b"}]}`,
+		`{"version": 1, "kind": "gantt", "sections": [{"id": "s"}, {"id": "s"}]}`,
+	} {
+		if err := json.Unmarshal([]byte(doc), &h); err == nil {
+			t.Errorf("%s: expected an error", doc)
+		}
+	}
+}
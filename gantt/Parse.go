@@ -0,0 +1,313 @@
+package gantt
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Heiko-san/mermaidgen"
+)
+
+// moment.js tokens as used by mermaid's dateFormat and their Go equivalents,
+// longer tokens need to be checked first
+var parseDateTokens = []struct{ moment, golang string }{
+	{"YYYY", "2006"}, {"YY", "06"},
+	{"MMMM", "January"}, {"MMM", "Jan"}, {"MM", "01"}, {"M", "1"},
+	{"DD", "02"}, {"D", "2"}, {"dddd", "Monday"}, {"ddd", "Mon"},
+	{"HH", "15"}, {"hh", "03"}, {"h", "3"}, {"mm", "04"}, {"m", "4"},
+	{"ss", "05"}, {"s", "5"}, {"SSS", "000"},
+	{"ZZ", "-0700"}, {"Z", "Z07:00"}, {"A", "PM"}, {"a", "pm"},
+}
+
+// regular expression for mermaid durations
+var parseDuration = regexp.MustCompile(`^([0-9]*\.?[0-9]+)(ms|s|m|h|d|w)$`)
+
+// Settings mermaid supports but Gantt doesn't model, they are ignored.
+var parseIgnored = map[string]bool{
+	"excludes": true, "includes": true, "todayMarker": true,
+	"tickInterval": true, "weekday": true, "inclusiveEndDates": true,
+	"topAxis": true, "displayMode": true, "accTitle": true,
+	"accDescr": true, "click": true,
+}
+
+// A parsed task line, Tasks are created after all lines are known so
+// generated IDs don't collide and After may refer to later Tasks.
+type parseTask struct {
+	line     int
+	section  string
+	id       string
+	title    string
+	flags    []string
+	start    string
+	duration string
+	comment  string
+}
+
+// Parse creates a Gantt diagram from mermaid code, e.g. to validate or modify
+// existing diagrams. It supports title, axisFormat, dateFormat, sections and
+// tasks with the flags crit, active and done, as well as a leading frontmatter
+// or init directive which is stored as Config. %% comment lines directly above
+// a section or task are stored as its Comment. Tasks without ID use their
+// title as ID if possible, otherwise they get generated IDs. Settings that
+// Gantt doesn't model, such as excludes, are ignored.
+// Unsupported syntax, such as milestones or "until", yields an error containing
+// the line number.
+func Parse(code string) (newGantt *Gantt, err error) {
+	body, config, err := mermaidgen.SplitConfig(code)
+	if err != nil {
+		return nil, fmt.Errorf("Parse: %s", err)
+	}
+	// line numbers count the lines of the configuration too
+	offset := strings.Count(code[:len(code)-len(body)], "\n")
+	g, _ := NewGantt()
+	g.Config = config
+	layout := momentToLayout("YYYY-MM-DD")
+	header := false
+	section := ""
+	var tasks []parseTask
+	var comments []string
+	explicitIDs := map[string]bool{}
+	for i, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			comments = nil
+			continue
+		}
+		if strings.HasPrefix(line, "%%") {
+			comments = append(comments, strings.TrimSpace(line[2:]))
+			continue
+		}
+		comment := strings.Join(comments, "\n")
+		comments = nil
+		errorf := func(format string, args ...interface{}) error {
+			return fmt.Errorf("Parse: line %d: %s", offset+i+1,
+				fmt.Sprintf(format, args...))
+		}
+		if !header {
+			if line != "gantt" {
+				return nil, errorf("expected gantt header")
+			}
+			header = true
+			continue
+		}
+		keyword := strings.Fields(line)[0]
+		args := strings.TrimSpace(strings.TrimPrefix(line, keyword))
+		switch {
+		case keyword == "title":
+			g.Title = args
+		case keyword == "axisFormat":
+			g.AxisFormat = axisFormat(args)
+		case keyword == "dateFormat":
+			layout = momentToLayout(args)
+		case keyword == "section":
+			s, err := g.AddSection(args)
+			if err != nil {
+				return nil, errorf("section %q: %s", args, err)
+			}
+			s.Comment = comment
+			section = args
+		case parseIgnored[strings.TrimSuffix(keyword, ":")]:
+		case strings.Contains(line, ":"):
+			t, err := parseTaskLine(line, section)
+			if err != nil {
+				return nil, errorf("%s", err)
+			}
+			t.line, t.comment = offset+i+1, comment
+			if t.id != "" {
+				explicitIDs[t.id] = true
+			}
+			tasks = append(tasks, t)
+		default:
+			return nil, errorf("unsupported statement %q", line)
+		}
+	}
+	if !header {
+		return nil, fmt.Errorf("Parse: no gantt header found")
+	}
+	return g, createTasks(g, tasks, explicitIDs, layout)
+}
+
+// ParseLiveURL decodes a live editor URL via mermaidgen.DecodeLiveURL and
+// parses the contained code via Parse. The Config from the URL is used unless
+// the code itself contains one.
+func ParseLiveURL(liveURL string) (newGantt *Gantt, err error) {
+	code, config, err := mermaidgen.DecodeLiveURL(liveURL)
+	if err != nil {
+		return nil, err
+	}
+	newGantt, err = Parse(code)
+	if err != nil {
+		return nil, err
+	}
+	if newGantt.Config == nil {
+		newGantt.Config = config
+	}
+	return
+}
+
+// Helperfunction to convert a moment.js date format to a Go time layout.
+// Text in square brackets is kept literally.
+func momentToLayout(format string) (layout string) {
+	for len(format) > 0 {
+		if format[0] == '[' {
+			if end := strings.Index(format, "]"); end > 0 {
+				layout += format[1:end]
+				format = format[end+1:]
+				continue
+			}
+		}
+		matched := false
+		for _, token := range parseDateTokens {
+			if strings.HasPrefix(format, token.moment) {
+				layout += token.golang
+				format = format[len(token.moment):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			layout += format[:1]
+			format = format[1:]
+		}
+	}
+	return
+}
+
+// Helperfunction to split a task line into its parts.
+func parseTaskLine(line, section string) (t parseTask, err error) {
+	colon := strings.Index(line, ":")
+	t.title = strings.TrimSpace(line[:colon])
+	t.section = section
+	var tokens []string
+	for _, token := range strings.Split(line[colon+1:], ",") {
+		token = strings.TrimSpace(token)
+		switch {
+		case len(tokens) > 0:
+			tokens = append(tokens, token)
+		case token == "crit" || token == "active" || token == "done":
+			t.flags = append(t.flags, token)
+		case token == "milestone":
+			return t, fmt.Errorf("milestones are not supported")
+		default:
+			tokens = append(tokens, token)
+		}
+	}
+	switch len(tokens) {
+	case 1:
+		t.duration = tokens[0]
+	case 2:
+		t.start, t.duration = tokens[0], tokens[1]
+	case 3:
+		t.id, t.start, t.duration = tokens[0], tokens[1], tokens[2]
+	default:
+		return t, fmt.Errorf("task %q needs 1 to 3 values after flags", t.title)
+	}
+	if strings.HasPrefix(t.duration, "until ") {
+		return t, fmt.Errorf("until is not supported")
+	}
+	return t, nil
+}
+
+// Helperfunction to create all parsed Tasks.
+func createTasks(g *Gantt, tasks []parseTask, explicitIDs map[string]bool,
+	layout string) (err error) {
+	generated := 0
+	created := make([]*Task, len(tasks))
+	for i, t := range tasks {
+		id := t.id
+		free := func(id string) bool {
+			return IsValidID(id) && !explicitIDs[id] && g.GetTask(id) == nil
+		}
+		if id == "" && free(t.title) {
+			// Task renders its ID as title if the Title is unset
+			id = t.title
+		}
+		for id == "" {
+			generated++
+			if candidate := "task" + strconv.Itoa(generated); free(candidate) {
+				id = candidate
+			}
+		}
+		var task *Task
+		if t.section == "" {
+			task, err = g.AddTask(id)
+		} else {
+			task, err = g.GetSection(t.section).AddTask(id)
+		}
+		if err != nil {
+			return fmt.Errorf("Parse: line %d: task %q: %s", t.line, id, err)
+		}
+		if t.title != id {
+			task.Title = t.title
+		}
+		task.Comment = t.comment
+		for _, flag := range t.flags {
+			switch flag {
+			case "crit":
+				task.Critical = true
+			case "active":
+				task.Active = true
+			case "done":
+				task.Done = true
+			}
+		}
+		created[i] = task
+	}
+	// Start and Duration may refer to any Task
+	for i, t := range tasks {
+		task := created[i]
+		if err = parseStart(task, t.start, layout); err == nil {
+			err = parseTaskDuration(task, t.duration, layout)
+		}
+		if err != nil {
+			return fmt.Errorf("Parse: line %d: %s", t.line, err)
+		}
+	}
+	return nil
+}
+
+// Helperfunction to set Start or After from a mermaid start definition.
+func parseStart(task *Task, start, layout string) (err error) {
+	if start == "" {
+		return nil
+	}
+	if strings.HasPrefix(start, "after ") {
+		// mermaid supports multiple IDs, the first one is used
+		id := strings.Fields(start)[1]
+		after := task.gantt.GetTask(id)
+		if after == nil {
+			return fmt.Errorf("unknown task %q", id)
+		}
+		return task.SetStart(after)
+	}
+	startTime, err := time.Parse(layout, start)
+	if err != nil {
+		return fmt.Errorf("%q doesn't match dateFormat", start)
+	}
+	return task.SetStart(startTime)
+}
+
+// Helperfunction to set Duration from a mermaid duration or end date.
+func parseTaskDuration(task *Task, duration, layout string) (err error) {
+	m := parseDuration.FindStringSubmatch(duration)
+	if m == nil {
+		end, err := time.Parse(layout, duration)
+		if err != nil {
+			return fmt.Errorf("%q is neither a duration nor matches dateFormat",
+				duration)
+		}
+		return task.SetDuration(end)
+	}
+	if duration == "1d" {
+		// the default, leave Duration unset to render the same way
+		return nil
+	}
+	value, _ := strconv.ParseFloat(m[1], 64)
+	unit := map[string]time.Duration{
+		"ms": time.Millisecond, "s": time.Second, "m": time.Minute,
+		"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour,
+	}[m[2]]
+	return task.SetDuration(time.Duration(value * float64(unit)))
+}
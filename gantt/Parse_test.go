@@ -0,0 +1,92 @@
+package gantt_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Heiko-san/mermaidgen/gantt"
+)
+
+// Parsing mermaid code into a Gantt diagram
+func ExampleParse() {
+	g, err := gantt.Parse(`gantt
+    title A Gantt Diagram
+    dateFormat YYYY-MM-DD
+    excludes weekends
+    section Section
+    A task       : a1, 2014-01-01, 30d
+    Another task : after a1, 2d
+    section Another
+    Task in sec  : crit, 2014-01-12, 12h
+    done task    : done, des1, 2014-01-06, 2014-01-08`)
+	fmt.Println(err)
+	// Tasks without ID get generated IDs
+	fmt.Println(g.GetTask("task1").Title, g.GetTask("des1").Duration)
+	fmt.Print(g)
+	//Output:
+	//<nil>
+	//Another task 48h0m0s
+	//gantt
+	//dateFormat YYYY-MM-DDTHH:mm:ssZ
+	//title A Gantt Diagram
+	//section Section
+	//A task : a1, 2014-01-01T00:00:00Z, 2592000s
+	//Another task : task1, after a1, 172800s
+	//section Another
+	//Task in sec : crit, task2, 2014-01-12T00:00:00Z, 43200s
+	//done task : done, des1, 2014-01-06T00:00:00Z, 172800s
+}
+
+func TestParse_roundTrip(t *testing.T) {
+	g, _ := gantt.NewGantt("title", gantt.FormatDateTime24)
+	start := time.Date(2019, 6, 20, 9, 15, 30, 0, time.UTC)
+	g.AddTask("t1", "first", "90m", start, true, true, true)
+	s, _ := g.AddSection("my section")
+	s.AddTask("t2", "second", "2h", "t1")
+	s.AddTask("t3")
+	s.AddTask("t4", "fourth", "1h", "t3", false, false, true)
+	code := g.String()
+	parsed, err := gantt.Parse(code)
+	if err != nil {
+		t.Fatalf("unexpected error: %s\n%s", err, code)
+	}
+	if parsed.String() != code {
+		t.Errorf("round trip failed, expected:\n%s\ngot:\n%s", code,
+			parsed.String())
+	}
+}
+
+func TestParse_errorLines(t *testing.T) {
+	for _, code := range []string{
+		"---\nconfig:\n  theme: dark\n---\ngantt\nsomething",
+		"---\ntitle: x\n---\ngantt\nsection s\nt1 : after nope, 1d\n",
+	} {
+		_, err := gantt.Parse(code)
+		if err == nil || !strings.Contains(err.Error(), "line 6:") {
+			t.Errorf("%q: unexpected error %v", code, err)
+		}
+	}
+}
+
+func TestParse_errors(t *testing.T) {
+	for _, code := range []string{
+		"",
+		"graph TB",
+		"gantt\nsomething",
+		"gantt\nsection s\nsection s",
+		"gantt\nt1 : a, b, c, d",
+		"gantt\nt1 : milestone, 2014-01-01, 0d",
+		"gantt\nt1 : t1, 2014-01-01, until t2",
+		"gantt\nt1 : t1, after t0, 1d",
+		"gantt\nt1 : t1, 01.01.2014, 1d",
+		"gantt\nt1 : t1, 2014-01-01, 1y",
+		"gantt\nt1 : t1, 1d\nt2 : t1, 1d",
+		"gantt\nt1 : bad id, 2014-01-01, 1d",
+	} {
+		if _, err := gantt.Parse(code); err == nil {
+			t.Errorf("%q: no error returned", code)
+		}
+	}
+}
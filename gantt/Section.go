@@ -9,9 +9,10 @@ import (
 // instances directly. Already defined IDs can be looked up via Gantt's
 // GetSection method or iterated over via its ListSections method.
 type Section struct {
-	id    string
-	gantt *Gantt
-	tasks []*Task
+	id      string
+	gantt   *Gantt
+	tasks   []*Task
+	Comment string // Optional %% comment rendered above the Section
 }
 
 // Private constructor for use in Add-functions.
@@ -38,12 +39,23 @@ func (s *Section) Gantt() (topLevel *Gantt) {
 }
 
 // String renders this diagram element to a section definition line.
+// If Comment member is set it is rendered as %% lines above. The Tasks are
+// indented according to the Gantt diagram's Indent.
 func (s *Section) String() (renderedElement string) {
-	renderedElement = fmt.Sprintln("section", s.id)
+	return renderString(s.gantt.Indent, s.writeDiagram)
+}
+
+// Helperfunction for String and Gantt's WriteTo, renders the Section and its
+// Tasks to w.
+func (s *Section) writeDiagram(w *diagramWriter) {
+	w.comment(s.Comment)
+	w.startLine()
+	w.WriteString("section " + s.id + "\n")
+	w.level++
 	for _, task := range s.tasks {
-		renderedElement += task.String()
+		task.writeDiagram(w)
 	}
-	return
+	w.level--
 }
 
 // AddTask is used to add a new Task to this Section. If the provided ID already
@@ -28,6 +28,7 @@ type Task struct {
 	Critical bool           // The crit flag
 	Active   bool           // The active flag
 	Done     bool           // The done flag
+	Comment  string         // Optional %% comment rendered above the Task
 }
 
 // Private constructor for use in Add-functions.
@@ -87,6 +88,7 @@ func (t *Task) CopyFields(task *Task) {
 		t.Active = task.Active
 		t.Done = task.Done
 		t.Title = task.Title
+		t.Comment = task.Comment
 		// After should be copied as pointer to the same object
 		t.After = task.After
 		t.SetDuration(task)
@@ -118,7 +120,13 @@ func (t *Task) Section() (containingSection *Section) {
 }
 
 // String renders this diagram element to a task definition line.
+// If Comment member is set it is rendered as %% lines above.
 func (t *Task) String() (renderedElement string) {
+	return renderString("", t.writeDiagram)
+}
+
+// Helperfunction for String and Gantt's WriteTo, renders the Task to w.
+func (t *Task) writeDiagram(w *diagramWriter) {
 	title := t.Title
 	if title == "" {
 		title = t.id
@@ -147,8 +155,9 @@ func (t *Task) String() (renderedElement string) {
 		duration = fmt.Sprintf("%ds", int(math.Abs(t.Duration.Seconds())))
 	}
 	tokens = append(tokens, duration)
-	renderedElement = fmt.Sprintf("%s : %s\n", title, strings.Join(tokens, ", "))
-	return
+	w.comment(t.Comment)
+	w.startLine()
+	w.WriteString(title + " : " + strings.Join(tokens, ", ") + "\n")
 }
 
 // SetStart takes a time.Time or a pointer to it, a Task pointer or a string
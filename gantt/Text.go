@@ -0,0 +1,289 @@
+package gantt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Heiko-san/mermaidgen"
+)
+
+////////// TextRenderer ////////////////////////////////////////////////////////
+
+// TextRenderer renders Gantt diagrams as text timelines for terminals and log
+// output. Sections are rendered as headlines, every Task as a line with its
+// title, markers for the crit (!), active (>) and done (✓) flags and a bar
+// scaled to Width. The axis shows the first and last date in AxisFormat if at
+// least one Task has a Start time. Unset (zero value) fields use the defaults.
+type TextRenderer struct {
+	Width int  // Maximum line width in characters, 80 if not set
+	ASCII bool // Use ASCII characters instead of Unicode block elements
+	Color bool // Use ANSI colors derived from the Config's theme variables
+}
+
+// Bar colors as used by mermaid's default theme, they can be overridden by
+// the Config's ThemeVariables with the same names.
+var textColors = map[string]mermaidgen.Color{
+	"taskBkgColor":       "#8a90dd",
+	"activeTaskBkgColor": "#bfc7ff",
+	"doneTaskBkgColor":   "lightgrey",
+	"critBkgColor":       "red",
+	"critBorderColor":    "#ff8888",
+}
+
+// RenderDiagram writes the given Gantt diagram as text to w, other Diagrams
+// yield an error. Implements mermaidgen.Renderer.
+func (r *TextRenderer) RenderDiagram(ctx context.Context, d mermaidgen.Diagram,
+	w io.Writer) (err error) {
+	g, ok := d.(*Gantt)
+	if !ok {
+		return fmt.Errorf("RenderDiagram: %s diagrams are not supported",
+			d.Kind())
+	}
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+	_, err = w.Write(r.render(g))
+	return
+}
+
+// Text renders the Gantt diagram as Unicode text using a TextRenderer with
+// default settings and writes it to w.
+func (g *Gantt) Text(w io.Writer) (err error) {
+	return (&TextRenderer{}).RenderDiagram(context.Background(), g, w)
+}
+
+// Helperfunction to render the whole timeline.
+func (r *TextRenderer) render(g *Gantt) (text []byte) {
+	width := r.Width
+	if width <= 0 {
+		width = 80
+	}
+	tasks := append([]*Task{}, g.tasks...)
+	for _, s := range g.sections {
+		tasks = append(tasks, s.tasks...)
+	}
+	starts, ends, anchored := g.schedule(tasks)
+	first, last := time.Time{}, time.Time{}
+	for i, t := range tasks {
+		if i == 0 || starts[t].Before(first) {
+			first = starts[t]
+		}
+		if i == 0 || ends[t].After(last) {
+			last = ends[t]
+		}
+	}
+	// "  title !>✓ │bar│"
+	labelW := 0
+	for _, t := range tasks {
+		if w := utf8.RuneCountInString(taskTitle(t)); w > labelW {
+			labelW = w
+		}
+	}
+	if max := width - 9 - width/2; labelW > max {
+		labelW = max
+	}
+	if labelW < 1 {
+		labelW = 1
+	}
+	barW := width - 9 - labelW
+	if barW < 10 {
+		barW = 10
+	}
+	blocks := []rune("█▓░│✓…")
+	if r.ASCII {
+		blocks = []rune("#=-|x~")
+	}
+	colors := make(map[string]string)
+	for name, color := range textColors {
+		if g.Config != nil {
+			if v, ok := g.Config.ThemeVariables[name]; ok {
+				color = mermaidgen.Color(v)
+			}
+		}
+		if r.Color {
+			colors[name] = color.ANSI(false)
+		}
+	}
+	var b bytes.Buffer
+	if g.Title != "" {
+		b.WriteString(truncate(g.Title, width, blocks[5]) + "\n")
+	}
+	if anchored && len(tasks) > 0 {
+		format := g.AxisFormat
+		if format == "" {
+			format = FormatDate
+		}
+		from, to := strftime(first, string(format)), strftime(last, string(format))
+		axis := from
+		if gap := barW - utf8.RuneCountInString(from) -
+			utf8.RuneCountInString(to); gap > 0 {
+			axis += strings.Repeat(" ", gap) + to
+		}
+		b.WriteString(strings.Repeat(" ", labelW+8) +
+			truncate(axis, barW, blocks[5]) + "\n")
+	}
+	span := last.Sub(first)
+	line := func(t *Task) {
+		markers := []rune("   ")
+		if t.Critical {
+			markers[0] = '!'
+		}
+		if t.Active {
+			markers[1] = '>'
+		}
+		if t.Done {
+			markers[2] = blocks[4]
+		}
+		title := truncate(taskTitle(t), labelW, blocks[5])
+		b.WriteString("  " + title + strings.Repeat(" ",
+			labelW-utf8.RuneCountInString(title)) + " ")
+		if t.Critical && colors["critBorderColor"] != "" {
+			b.WriteString(colors["critBorderColor"] + string(markers) +
+				"\x1b[0m")
+		} else {
+			b.WriteString(string(markers))
+		}
+		block, color := blocks[0], colors["taskBkgColor"]
+		switch {
+		case t.Active:
+			// active > done when rendering
+			block, color = blocks[1], colors["activeTaskBkgColor"]
+		case t.Done:
+			block, color = blocks[2], colors["doneTaskBkgColor"]
+		case t.Critical:
+			color = colors["critBkgColor"]
+		}
+		from, to := 0, barW
+		if span > 0 {
+			from = int(float64(starts[t].Sub(first)) / float64(span) *
+				float64(barW))
+			to = int(math.Ceil(float64(ends[t].Sub(first)) / float64(span) *
+				float64(barW)))
+		}
+		if from >= barW {
+			from = barW - 1
+		}
+		if to <= from {
+			to = from + 1
+		}
+		bar := strings.Repeat(string(block), to-from)
+		if color != "" {
+			bar = color + bar + "\x1b[0m"
+		}
+		b.WriteString(" " + string(blocks[3]) + strings.Repeat(" ", from) +
+			bar + strings.Repeat(" ", barW-to) + string(blocks[3]) + "\n")
+	}
+	for _, t := range g.tasks {
+		line(t)
+	}
+	for _, s := range g.sections {
+		b.WriteString(truncate(s.id, width, blocks[5]) + "\n")
+		for _, t := range s.tasks {
+			line(t)
+		}
+	}
+	return b.Bytes()
+}
+
+// Helperfunction to compute the start and end times of the given Tasks the way
+// mermaid does: Start wins over After, Tasks without both start when the
+// previous one ends. anchored is false if no Task has a Start time, in this
+// case the times are relative to the zero time.
+func (g *Gantt) schedule(tasks []*Task) (starts, ends map[*Task]time.Time,
+	anchored bool) {
+	starts = make(map[*Task]time.Time)
+	ends = make(map[*Task]time.Time)
+	previous := make(map[*Task]*Task)
+	for i, t := range tasks {
+		if i > 0 {
+			previous[t] = tasks[i-1]
+		}
+		anchored = anchored || t.Start != nil
+	}
+	visiting := make(map[*Task]bool)
+	var resolve func(t *Task) time.Time
+	resolve = func(t *Task) time.Time {
+		if end, ok := ends[t]; ok {
+			return end
+		}
+		var start time.Time
+		if visiting[t] {
+			// cyclic After relations start at the zero time
+			return start
+		}
+		visiting[t] = true
+		switch {
+		case t.Start != nil:
+			start = *t.Start
+		case t.After != nil:
+			start = resolve(t.After)
+		case previous[t] != nil:
+			start = resolve(previous[t])
+		}
+		duration := 24 * time.Hour
+		if t.Duration != nil {
+			duration = time.Duration(math.Abs(float64(*t.Duration)))
+		}
+		starts[t], ends[t] = start, start.Add(duration)
+		return ends[t]
+	}
+	for _, t := range tasks {
+		resolve(t)
+	}
+	return
+}
+
+// Helperfunction to get the displayed title of a Task.
+func taskTitle(t *Task) (title string) {
+	if t.Title != "" {
+		return t.Title
+	}
+	return t.id
+}
+
+// Helperfunction to limit a text to width characters, the last one is
+// replaced by ellipsis if the text is cut.
+func truncate(text string, width int, ellipsis rune) (truncated string) {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	if width < 1 {
+		return ""
+	}
+	return string(runes[:width-1]) + string(ellipsis)
+}
+
+// Replacements of the strftime directives used by axisFormat, see
+// https://github.com/d3/d3-time-format.
+var strftimeLayouts = map[byte]string{
+	'Y': "2006", 'y': "06", 'm': "01", 'd': "02", 'e': "_2", 'H': "15",
+	'I': "03", 'p': "PM", 'M': "04", 'S': "05", 'a': "Mon",
+	'A': "Monday", 'b': "Jan", 'B': "January", 'Z': "-0700",
+}
+
+// Helperfunction to format a time according to an axisFormat.
+func strftime(t time.Time, format string) (formatted string) {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			b.WriteByte(format[i])
+			continue
+		}
+		i++
+		if layout, ok := strftimeLayouts[format[i]]; ok {
+			b.WriteString(t.Format(layout))
+		} else if format[i] == 'j' {
+			fmt.Fprintf(&b, "%03d", t.YearDay())
+		} else {
+			b.WriteByte(format[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,83 @@
+package gantt_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/flowchart"
+	"github.com/Heiko-san/mermaidgen/gantt"
+)
+
+// Rendering Gantt diagrams as text timelines for terminals
+func ExampleGantt_Text() {
+	g, _ := gantt.Parse(`gantt
+	    title Release 1.0
+	    dateFormat YYYY-MM-DD
+	    section Development
+	    Design         :done, design, 2019-03-01, 5d
+	    Implementation :active, impl, after design, 10d
+	    section Release
+	    Testing        :crit, after impl, 4d
+	    Documentation  :3d`)
+	(&gantt.TextRenderer{Width: 60}).RenderDiagram(context.Background(), g,
+		os.Stdout)
+	//Output:
+	//Release 1.0
+	//                       2019-03-01                 2019-03-23
+	//Development
+	//   Design           ✓ │░░░░░░░░░                            │
+	//   Implementation  >  │        ▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓▓           │
+	//Release
+	//   Testing        !   │                         ███████     │
+	//   Documentation      │                               ██████│
+}
+
+func TestTextRenderer_RenderDiagram(t *testing.T) {
+	g, _ := gantt.NewGantt("Plan")
+	a, _ := g.AddTask("a", "First task with a long title", "48h")
+	s, _ := g.AddSection("Section")
+	s.AddTask("b", "Second", "24h", a, true, false, true)
+	s.AddTask("c")
+	r := &gantt.TextRenderer{Width: 40, ASCII: true}
+	var b bytes.Buffer
+	if err := r.RenderDiagram(context.Background(), g, &b); err != nil {
+		t.Fatal(err)
+	}
+	// no Start times -> no axis
+	expected := `Plan
+  First task~     |##########          |
+Section
+  Second      ! x |          -----     |
+  c               |               #####|
+`
+	if b.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, b.String())
+	}
+	for _, line := range strings.Split(b.String(), "\n") {
+		if utf8.RuneCountInString(line) > 40 {
+			t.Errorf("line exceeds width: %q", line)
+		}
+	}
+	g.Config = &mermaidgen.Config{
+		ThemeVariables: map[string]string{"doneTaskBkgColor": "#00ff00"}}
+	r.Color = true
+	b.Reset()
+	r.RenderDiagram(context.Background(), g, &b)
+	for _, sequence := range []string{"\x1b[38;2;0;255;0m", // done
+		"\x1b[38;2;138;144;221m", // default
+		"\x1b[38;2;255;136;136m", // crit marker
+	} {
+		if !strings.Contains(b.String(), sequence) {
+			t.Errorf("%q missing in\n%s", sequence, b.String())
+		}
+	}
+	if err := r.RenderDiagram(context.Background(),
+		flowchart.NewFlowchart(), &b); err == nil {
+		t.Error("no error for a Flowchart")
+	}
+}
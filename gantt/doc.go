@@ -4,6 +4,11 @@ defined at https://mermaidjs.github.io/gantt.html and render them to mermaid
 code.
 
 Start exploring the Gantt type and the example "Gantt (Basics)", then proceed
-with the other examples.
+with the other examples. Large diagrams can be streamed to a file or network
+connection via WriteTo instead of being stringified. Indent and the Comment
+fields of Sections and Tasks make the code more readable. To preview a Gantt
+diagram in the terminal, render it as text timeline via its Text method or a
+TextRenderer. Gantt diagrams can be stored as JSON or YAML without any loss,
+see SchemaVersion.
 */
 package gantt
@@ -0,0 +1,29 @@
+package gitgraph
+
+// Branch represents a branch of the GitGraph. Create an instance of Branch via
+// GitGraph's Branch method, do not create instances directly, the main Branch
+// is created by GitGraph's constructor. Already defined names can be looked up
+// via GitGraph's GetBranch method or iterated over via its ListBranches method.
+type Branch struct {
+	name    string
+	diagram *GitGraph // top lvl pointer
+	head    *Commit   // the latest Commit of the Branch
+	Order   int       // Optional position of the Branch, rendered if > 0
+}
+
+// Name provides access to the Branch's readonly field name.
+func (b *Branch) Name() (name string) {
+	return b.name
+}
+
+// GitGraph provides access to the top level GitGraph to be able to access
+// operation, Getter and Lister methods.
+func (b *Branch) GitGraph() (topLevel *GitGraph) {
+	return b.diagram
+}
+
+// Head returns the latest Commit of the Branch, nil if the Branch has no
+// Commits yet.
+func (b *Branch) Head() (head *Commit) {
+	return b.head
+}
@@ -0,0 +1,84 @@
+package gitgraph
+
+import (
+	"fmt"
+)
+
+type commitType string
+
+// Type definitions for Commits as described at
+// https://mermaid.js.org/syntax/gitgraph.html#modifying-commit-type.
+// The default is CommitNormal.
+const (
+	CommitNormal    commitType = `NORMAL`
+	CommitReverse   commitType = `REVERSE`
+	CommitHighlight commitType = `HIGHLIGHT`
+)
+
+// Commit represents a commit, a merge commit or a cherry-pick of the GitGraph.
+// Create an instance of Commit via GitGraph's Commit, Merge or CherryPick
+// method, do not create instances directly. Commits with ID can be looked up
+// via GitGraph's GetCommit method, all Commits can be iterated over via its
+// ListCommits method.
+type Commit struct {
+	id           string
+	branch       *Branch    // Branch the Commit was created on
+	parents      []*Commit  // parent Commits, 2 for merge Commits
+	Type         commitType // Optional type, not rendered for cherry-picks
+	Tag          string     // Optional tag
+	CherryPicked *Commit    // The copied Commit if this is a cherry-pick
+}
+
+// ID provides access to the Commit's readonly field id.
+func (c *Commit) ID() (id string) {
+	return c.id
+}
+
+// Branch provides access to the Branch the Commit was created on.
+func (c *Commit) Branch() (branch *Branch) {
+	return c.branch
+}
+
+// Parents returns the parent Commits, the first one is the previous head of
+// the Commit's Branch, merge Commits have the merged Branch's head as second
+// parent.
+func (c *Commit) Parents() (parents []*Commit) {
+	parents = make([]*Commit, len(c.parents))
+	copy(parents, c.parents)
+	return
+}
+
+// Helperfunction to check whether a Commit is (a descendant of) another one.
+func (c *Commit) isDescendantOf(ancestor *Commit) bool {
+	seen := make(map[*Commit]bool)
+	queue := []*Commit{c}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == ancestor {
+			return true
+		}
+		for _, p := range current.parents {
+			if p != nil && !seen[p] {
+				seen[p] = true
+				queue = append(queue, p)
+			}
+		}
+	}
+	return false
+}
+
+// Helperfunction to render the id, type and tag attributes of commit and merge
+// lines.
+func (c *Commit) attributes() (renderedAttributes string) {
+	if c.id != "" {
+		renderedAttributes += fmt.Sprintf(` id: "%s"`, c.id)
+	}
+	if c.Type != "" && c.Type != CommitNormal {
+		renderedAttributes += " type: " + string(c.Type)
+	}
+	if c.Tag != "" {
+		renderedAttributes += fmt.Sprintf(` tag: "%s"`, quote(c.Tag))
+	}
+	return
+}
@@ -0,0 +1,364 @@
+package gitgraph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/internal/counting"
+)
+
+////////// DiagramDirection ////////////////////////////////////////////////////
+
+type diagramDirection string
+
+// Direction definitions for GitGraphs as described at
+// https://mermaid.js.org/syntax/gitgraph.html#orientation-v10-3-0.
+// The default is no direction statement which results in DirectionLeftRight.
+const (
+	DirectionLeftRight diagramDirection = `LR`
+	DirectionTopDown   diagramDirection = `TB`
+	DirectionBottomUp  diagramDirection = `BT`
+)
+
+// IsValidBranchName is used to check if Branch names are valid:
+// IsValidBranchName(string) bool.
+var IsValidBranchName = regexp.MustCompile(
+	`^[a-zA-Z0-9_][a-zA-Z0-9_./-]*$`).MatchString
+
+// DefaultMainBranch is the name of the main branch if none is given.
+const DefaultMainBranch = "main"
+
+////////// GitGraph ////////////////////////////////////////////////////////////
+
+// GitGraph objects are the entrypoints to this package, the whole diagram is
+// constructed around a GitGraph object. Create an instance of GitGraph via
+// GitGraph's constructor NewGitGraph, do not create instances directly.
+//
+// A GitGraph is a sequence of git operations like commit, branch, checkout,
+// merge and cherry-pick. GitGraph tracks the state of the repository, like
+// the checked out Branch and the head Commit of each Branch, to reject
+// operations mermaid can't render.
+type GitGraph struct {
+	operations  []*operation       // operations for ordered rendering
+	branchesMap map[string]*Branch // lookup table for existing Branches
+	branches    []*Branch          // Branches in the order of creation
+	commitsMap  map[string]*Commit // lookup table for Commits with ID
+	commits     []*Commit          // Commits in the order of creation
+	current     *Branch            // the checked out Branch
+	Direction   diagramDirection   // Optional direction of the diagram
+	Config      *mermaidgen.Config // Optional theme and configuration
+	Indent      string             // Optional indentation of the operations
+}
+
+// Kinds of operations.
+const (
+	opCommit     = 'c'
+	opBranch     = 'b'
+	opCheckout   = 'o'
+	opMerge      = 'm'
+	opCherryPick = 'p'
+)
+
+// An operation of the GitGraph.
+type operation struct {
+	kind   byte
+	commit *Commit // commits, merges and cherry-picks
+	branch *Branch // branches, checkouts and merges
+}
+
+// NewGitGraph is the constructor used to create a new GitGraph object.
+// This object is the entrypoint for any further interactions with your diagram.
+// Always use the constructor, don't create GitGraph objects directly.
+// An optional initializer parameter can be given for the name of the main
+// Branch, which is checked out, DefaultMainBranch if not given.
+func NewGitGraph(init ...interface{}) (newGitGraph *GitGraph, err error) {
+	gg := &GitGraph{}
+	gg.branchesMap = make(map[string]*Branch)
+	gg.commitsMap = make(map[string]*Commit)
+	name := DefaultMainBranch
+	if len(init) > 0 {
+		var ok bool
+		if name, ok = init[0].(string); !ok {
+			return nil, fmt.Errorf("value for main Branch was no string")
+		}
+		if !IsValidBranchName(name) {
+			return nil, fmt.Errorf("invalid main Branch name")
+		}
+	}
+	gg.current = &Branch{name: name, diagram: gg}
+	gg.branchesMap[name] = gg.current
+	gg.branches = append(gg.branches, gg.current)
+	return gg, nil
+}
+
+// String recursively renders the whole diagram to mermaid code lines.
+// It is a shorthand for WriteTo with a strings.Builder.
+func (gg *GitGraph) String() (renderedElement string) {
+	var s strings.Builder
+	gg.WriteTo(&s)
+	return s.String()
+}
+
+// WriteTo writes the mermaid code of the whole graph to w, the operations
+// indented by Indent. If the main Branch isn't DefaultMainBranch, its name is
+// added to the rendered Config. Implements io.WriterTo.
+func (gg *GitGraph) WriteTo(w io.Writer) (n int64, err error) {
+	cw := &counting.Writer{W: w}
+	b := bufio.NewWriter(cw)
+	b.WriteString(gg.renderConfig().String())
+	b.WriteString("gitGraph")
+	if gg.Direction != "" {
+		b.WriteString(" " + string(gg.Direction) + ":")
+	}
+	b.WriteString("\n")
+	for _, op := range gg.operations {
+		b.WriteString(gg.Indent)
+		switch op.kind {
+		case opCommit:
+			b.WriteString("commit" + op.commit.attributes() + "\n")
+		case opBranch:
+			b.WriteString("branch " + op.branch.name)
+			if op.branch.Order > 0 {
+				fmt.Fprintf(b, " order: %d", op.branch.Order)
+			}
+			b.WriteString("\n")
+		case opCheckout:
+			b.WriteString("checkout " + op.branch.name + "\n")
+		case opMerge:
+			b.WriteString("merge " + op.branch.name +
+				op.commit.attributes() + "\n")
+		case opCherryPick:
+			fmt.Fprintf(b, "cherry-pick id: \"%s\"", op.commit.CherryPicked.id)
+			if op.commit.Tag != "" {
+				fmt.Fprintf(b, " tag: \"%s\"", quote(op.commit.Tag))
+			}
+			b.WriteString("\n")
+		}
+	}
+	err = b.Flush()
+	return cw.N, err
+}
+
+// Helperfunction to get the Config to render, a copy of Config with the name
+// of the main Branch if it isn't the default.
+func (gg *GitGraph) renderConfig() (config *mermaidgen.Config) {
+	main := gg.branches[0].name
+	if main == DefaultMainBranch {
+		return gg.Config
+	}
+	if gg.Config == nil {
+		config = &mermaidgen.Config{Syntax: mermaidgen.SyntaxFrontmatter}
+	} else {
+		c := *gg.Config
+		config = &c
+	}
+	settings := mermaidgen.GitGraphConfig{}
+	if config.GitGraph != nil {
+		settings = *config.GitGraph
+	}
+	settings.MainBranchName = main
+	config.GitGraph = &settings
+	return
+}
+
+// Render writes the mermaid code of the whole diagram to w.
+// Implements mermaidgen.Diagram.
+func (gg *GitGraph) Render(w io.Writer) (err error) {
+	_, err = gg.WriteTo(w)
+	return
+}
+
+// Kind returns mermaidgen.KindGitGraph. Implements mermaidgen.Diagram.
+func (gg *GitGraph) Kind() (kind mermaidgen.DiagramKind) {
+	return mermaidgen.KindGitGraph
+}
+
+// GetConfig returns the GitGraph's Config, which may be nil.
+// Implements mermaidgen.Diagram.
+func (gg *GitGraph) GetConfig() (config *mermaidgen.Config) {
+	return gg.Config
+}
+
+// SetConfig replaces the GitGraph's Config, nil removes it.
+// Implements mermaidgen.Diagram.
+func (gg *GitGraph) SetConfig(config *mermaidgen.Config) {
+	gg.Config = config
+}
+
+// LiveURL renders the GitGraph and generates a view URL for
+// https://mermaid.live from it, see mermaidgen.LiveURL for details.
+func (gg *GitGraph) LiveURL() (url string) {
+	url, _ = mermaidgen.LiveURL(gg)
+	return
+}
+
+// ViewInBrowser uses the URL generated by GitGraph's LiveURL method and opens
+// that URL in the OS's default browser via mermaidgen.ViewInBrowser. It
+// eventually returns any error occured.
+func (gg *GitGraph) ViewInBrowser() (err error) {
+	return mermaidgen.ViewInBrowser(gg)
+}
+
+////////// git operations //////////////////////////////////////////////////////
+
+// Helperfunction to create a Commit on the checked out Branch.
+func (gg *GitGraph) newCommit(id string, parents ...*Commit) (*Commit, error) {
+	if strings.Contains(id, `"`) {
+		return nil, fmt.Errorf("invalid id")
+	}
+	if _, alreadyExists := gg.commitsMap[id]; alreadyExists && id != "" {
+		return nil, fmt.Errorf("id already exists")
+	}
+	c := &Commit{id: id, branch: gg.current, parents: parents}
+	if id != "" {
+		gg.commitsMap[id] = c
+	}
+	gg.commits = append(gg.commits, c)
+	gg.current.head = c
+	return c, nil
+}
+
+// Commit adds a new Commit to the checked out Branch. If the provided ID
+// already exists or contains a double quote, no Commit is created and an error
+// is returned. An empty ID lets mermaid generate one, such Commits can't be
+// cherry-picked.
+func (gg *GitGraph) Commit(id string) (newCommit *Commit, err error) {
+	newCommit, err = gg.newCommit(id, gg.current.head)
+	if err == nil {
+		gg.operations = append(gg.operations,
+			&operation{kind: opCommit, commit: newCommit})
+	}
+	return
+}
+
+// Branch creates a new Branch starting at the head of the checked out Branch
+// and checks it out, like git checkout -b does. If the provided name already
+// exists or is invalid, no Branch is created and an error is returned.
+func (gg *GitGraph) Branch(name string) (newBranch *Branch, err error) {
+	if !IsValidBranchName(name) {
+		return nil, fmt.Errorf("invalid Branch name")
+	}
+	if _, alreadyExists := gg.branchesMap[name]; alreadyExists {
+		return nil, fmt.Errorf("Branch already exists")
+	}
+	newBranch = &Branch{name: name, diagram: gg, head: gg.current.head}
+	gg.branchesMap[name] = newBranch
+	gg.branches = append(gg.branches, newBranch)
+	gg.current = newBranch
+	gg.operations = append(gg.operations,
+		&operation{kind: opBranch, branch: newBranch})
+	return
+}
+
+// Checkout switches to the Branch with the given name. An error is returned if
+// the Branch doesn't exist. Checking out the current Branch does nothing.
+func (gg *GitGraph) Checkout(name string) (err error) {
+	branch := gg.branchesMap[name]
+	if branch == nil {
+		return fmt.Errorf("Checkout: unknown Branch %s", name)
+	}
+	if branch != gg.current {
+		gg.current = branch
+		gg.operations = append(gg.operations,
+			&operation{kind: opCheckout, branch: branch})
+	}
+	return nil
+}
+
+// Merge merges the Branch with the given name into the checked out Branch by
+// adding a merge Commit, see Commit for the id. An error is returned if the
+// Branch doesn't exist, is the checked out Branch, either of both has no
+// Commits or the Branch is already merged (its head is an ancestor of the
+// checked out Branch's head).
+func (gg *GitGraph) Merge(name, id string) (mergeCommit *Commit, err error) {
+	branch := gg.branchesMap[name]
+	switch {
+	case branch == nil:
+		return nil, fmt.Errorf("Merge: unknown Branch %s", name)
+	case branch == gg.current:
+		return nil, fmt.Errorf("Merge: can't merge Branch %s into itself", name)
+	case branch.head == nil || gg.current.head == nil:
+		return nil, fmt.Errorf("Merge: Branch without Commits")
+	case gg.current.head.isDescendantOf(branch.head):
+		return nil, fmt.Errorf("Merge: Branch %s is already merged", name)
+	}
+	mergeCommit, err = gg.newCommit(id, gg.current.head, branch.head)
+	if err != nil {
+		return nil, fmt.Errorf("Merge: %v", err)
+	}
+	gg.operations = append(gg.operations,
+		&operation{kind: opMerge, commit: mergeCommit, branch: branch})
+	return
+}
+
+// CherryPick copies the Commit with the given ID to the checked out Branch by
+// adding a new Commit, which has no ID of its own. An error is returned if the
+// Commit doesn't exist, is a merge Commit or is already part of the checked
+// out Branch.
+func (gg *GitGraph) CherryPick(id string) (newCommit *Commit, err error) {
+	picked := gg.commitsMap[id]
+	switch {
+	case picked == nil:
+		return nil, fmt.Errorf("CherryPick: unknown Commit %s", id)
+	case len(picked.parents) > 1:
+		return nil, fmt.Errorf("CherryPick: %s is a merge Commit", id)
+	case gg.current.head != nil && gg.current.head.isDescendantOf(picked):
+		return nil, fmt.Errorf("CherryPick: %s is already on the Branch", id)
+	}
+	newCommit, _ = gg.newCommit("", gg.current.head)
+	newCommit.CherryPicked = picked
+	gg.operations = append(gg.operations,
+		&operation{kind: opCherryPick, commit: newCommit})
+	return
+}
+
+////////// get Items ///////////////////////////////////////////////////////////
+
+// GetBranch looks up a previously defined Branch by its name.
+// If this name doesn't exist, nil is returned.
+func (gg *GitGraph) GetBranch(name string) (existingBranch *Branch) {
+	// if not found -> nil
+	return gg.branchesMap[name]
+}
+
+// GetCommit looks up a previously defined Commit by its ID.
+// If this ID doesn't exist, nil is returned.
+func (gg *GitGraph) GetCommit(id string) (existingCommit *Commit) {
+	// if not found -> nil
+	return gg.commitsMap[id]
+}
+
+// CurrentBranch returns the checked out Branch.
+func (gg *GitGraph) CurrentBranch() (checkedOut *Branch) {
+	return gg.current
+}
+
+////////// list Items //////////////////////////////////////////////////////////
+
+// ListBranches returns a slice of all Branches of this GitGraph in the order
+// they were created, starting with the main Branch.
+func (gg *GitGraph) ListBranches() (allBranches []*Branch) {
+	allBranches = make([]*Branch, len(gg.branches))
+	copy(allBranches, gg.branches)
+	return
+}
+
+// ListCommits returns a slice of all Commits of this GitGraph (merge Commits
+// and cherry-picks included) in the order they were created.
+func (gg *GitGraph) ListCommits() (allCommits []*Commit) {
+	allCommits = make([]*Commit, len(gg.commits))
+	copy(allCommits, gg.commits)
+	return
+}
+
+////////// rendering helpers ///////////////////////////////////////////////////
+
+// Helperfunction to replace double quotes, which can't be escaped in mermaid
+// strings.
+func quote(text string) string {
+	return strings.Replace(text, `"`, `'`, -1)
+}
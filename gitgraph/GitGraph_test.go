@@ -0,0 +1,147 @@
+package gitgraph_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/gitgraph"
+)
+
+// Defining and rendering a git graph
+func ExampleGitGraph() {
+	gg, _ := gitgraph.NewGitGraph()
+	gg.Commit("init")
+	gg.Branch("develop")
+	gg.Commit("feature")
+	fix, _ := gg.Commit("fix")
+	fix.Type = gitgraph.CommitHighlight
+	gg.Checkout("main")
+	merge, _ := gg.Merge("develop", "")
+	merge.Tag = "v1.0"
+	hotfix, _ := gg.Branch("hotfix")
+	hotfix.Order = 3
+	gg.Commit("hot")
+	gg.Checkout("develop")
+	gg.CherryPick("hot")
+	fmt.Print(gg)
+	//Output:
+	//gitGraph
+	//commit id: "init"
+	//branch develop
+	//commit id: "feature"
+	//commit id: "fix" type: HIGHLIGHT
+	//checkout main
+	//merge develop tag: "v1.0"
+	//branch hotfix order: 3
+	//commit id: "hot"
+	//checkout develop
+	//cherry-pick id: "hot"
+}
+
+// Renaming the main branch, which is configured for mermaid
+func ExampleNewGitGraph() {
+	gg, _ := gitgraph.NewGitGraph("trunk")
+	gg.Direction = gitgraph.DirectionTopDown
+	gg.Indent = "  "
+	gg.Commit("")
+	fmt.Print(gg)
+	//Output:
+	//---
+	//config:
+	//   gitGraph:
+	//     mainBranchName: trunk
+	//---
+	//gitGraph TB:
+	//   commit
+}
+
+func TestGitGraph_Merge(t *testing.T) {
+	gg, _ := gitgraph.NewGitGraph()
+	if _, err := gg.Merge("main", ""); err == nil {
+		t.Error("merge into itself not detected")
+	}
+	if _, err := gg.Merge("nope", ""); err == nil {
+		t.Error("unknown Branch not detected")
+	}
+	gg.Branch("empty")
+	if _, err := gg.Merge("main", ""); err == nil {
+		t.Error("merge without Commits not detected")
+	}
+	gg.Checkout("main")
+	gg.Commit("a")
+	gg.Checkout("empty")
+	gg.Commit("b")
+	if _, err := gg.Merge("main", "m"); err != nil {
+		t.Error(err)
+	}
+	if _, err := gg.Merge("main", ""); err == nil {
+		t.Error("merge of merged Branch not detected")
+	}
+	if _, err := gg.Merge("main", `"`); err == nil {
+		t.Error("invalid id not detected")
+	}
+	m := gg.GetCommit("m")
+	if len(m.Parents()) != 2 || m.Branch().Name() != "empty" {
+		t.Error("unexpected merge Commit")
+	}
+}
+
+func TestGitGraph_CherryPick(t *testing.T) {
+	gg, _ := gitgraph.NewGitGraph()
+	gg.Commit("a")
+	if _, err := gg.CherryPick("a"); err == nil {
+		t.Error("pick of own Commit not detected")
+	}
+	if _, err := gg.CherryPick("b"); err == nil {
+		t.Error("unknown Commit not detected")
+	}
+	gg.Branch("other")
+	gg.Commit("b")
+	gg.Checkout("main")
+	gg.Commit("c")
+	gg.Merge("other", "m")
+	gg.Checkout("other")
+	if _, err := gg.CherryPick("m"); err == nil {
+		t.Error("pick of merge Commit not detected")
+	}
+	if _, err := gg.CherryPick("c"); err != nil {
+		t.Error(err)
+	}
+	if len(gg.ListCommits()) != 5 || gg.CurrentBranch().Head().ID() != "" {
+		t.Error("unexpected Commits")
+	}
+}
+
+func TestGitGraph_Branch(t *testing.T) {
+	if _, err := gitgraph.NewGitGraph("-x"); err == nil {
+		t.Error("invalid main Branch not detected")
+	}
+	if _, err := gitgraph.NewGitGraph(1); err == nil {
+		t.Error("invalid init not detected")
+	}
+	gg, _ := gitgraph.NewGitGraph()
+	for _, name := range []string{"main", "a b", ""} {
+		if _, err := gg.Branch(name); err == nil {
+			t.Errorf("invalid Branch %q not detected", name)
+		}
+	}
+	if err := gg.Checkout("nope"); err == nil {
+		t.Error("unknown Branch not detected")
+	}
+	if _, err := gg.Commit("a"); err != nil {
+		t.Error(err)
+	}
+	if _, err := gg.Commit("a"); err == nil {
+		t.Error("duplicate id not detected")
+	}
+	b, _ := gg.Branch("release/1.0")
+	if b.Head() != gg.GetCommit("a") || b.GitGraph() != gg ||
+		gg.GetBranch("release/1.0") != b || len(gg.ListBranches()) != 2 {
+		t.Error("unexpected Branch")
+	}
+	var d mermaidgen.Diagram = gg
+	if d.Kind() != mermaidgen.KindGitGraph || d.GetConfig() != nil {
+		t.Error("unexpected Diagram")
+	}
+}
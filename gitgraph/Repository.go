@@ -0,0 +1,267 @@
+package gitgraph
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+////////// repository import ///////////////////////////////////////////////////
+
+// A commit read from the repository.
+type repoCommit struct {
+	hash, short, subject string
+	parents              []*repoCommit // parents within the limit only
+	branch               *repoBranch   // the Branch the commit is drawn on
+	tags                 []string
+}
+
+// A branch of the repository, or a synthetic one for unnamed history.
+type repoBranch struct {
+	name   string
+	head   *repoCommit
+	forkAt *repoCommit // the commit the branch starts at, nil for roots
+}
+
+// Subjects of merge commits that tell the name of the merged branch.
+var repoMergeSubject = regexp.MustCompile(`^Merge (?:remote-tracking )?` +
+	`branch(?:es)? '([^']+)'|^Merge pull request #\d+ from [^/\s]+/(\S+)`)
+
+// Characters not allowed in Branch names.
+var repoInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_./-]+`)
+
+// FromRepository creates a GitGraph from the history of the local git
+// repository at path (any directory inside the work tree or a bare
+// repository). It runs the git command found in PATH, no network access is
+// done. Only the newest limit commits of all local branches and tags are read,
+// all commits if limit is <= 0, older parents are cut off.
+//
+// The main Branch is main or master if one of them exists, otherwise the
+// checked out branch. Each commit is drawn on the first branch (main first,
+// the others by name) which reaches it by following first parents from its
+// head, the way git log --first-parent shows them. Commits no branch reaches
+// this way get a Branch named by the merge commit's subject, e.g. "Merge
+// branch 'feature'", or by their short hash otherwise. Branch names are
+// sanitized to match IsValidBranchName.
+//
+// Commits get their abbreviated hash as ID and their tags joined by ", " as
+// Tag. Merge commits with a parent on another Branch become merges of that
+// Branch, if mermaid is able to draw them.
+func FromRepository(path string, limit int) (newGitGraph *GitGraph, err error) {
+	refs, err := repoGit(path, "for-each-ref", "--format=%(objectname) "+
+		"%(*objectname) %(refname)", "refs/heads", "refs/tags")
+	if err != nil {
+		return nil, err
+	}
+	var commits []*repoCommit
+	byHash := make(map[string]*repoCommit)
+	// git log fails without any refs, e.g. in a new repository
+	if refs != "" {
+		if commits, byHash, err = repoLog(path, limit); err != nil {
+			return nil, err
+		}
+	}
+	heads := make(map[string]*repoCommit)
+	var names []string
+	for _, line := range strings.Split(refs, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		// annotated tags are peeled to their commit
+		ref, c := fields[len(fields)-1], byHash[fields[len(fields)-2]]
+		if c == nil {
+			continue
+		}
+		if name := strings.TrimPrefix(ref, "refs/tags/"); name != ref {
+			c.tags = append(c.tags, name)
+		} else if name := strings.TrimPrefix(ref, "refs/heads/"); name != ref {
+			heads[name] = c
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	main := ""
+	for _, name := range []string{"main", "master"} {
+		if heads[name] != nil {
+			main = name
+			break
+		}
+	}
+	if main == "" {
+		// unborn or detached HEADs have no branch
+		head, _ := repoGit(path, "symbolic-ref", "--quiet", "--short", "HEAD")
+		if heads[head] != nil {
+			main = head
+		} else if len(names) > 0 {
+			main = names[0]
+		} else {
+			main = DefaultMainBranch
+		}
+	}
+	branches := repoBranches(commits, heads, names, main)
+	gg, err := NewGitGraph(branches[0].name)
+	if err != nil {
+		return nil, fmt.Errorf("FromRepository: %v", err)
+	}
+	forks := make(map[*repoCommit][]*repoBranch)
+	for _, b := range branches[1:] {
+		if b.forkAt == nil {
+			// root branches of their own start off the empty main Branch
+			gg.Branch(b.name)
+		} else {
+			forks[b.forkAt] = append(forks[b.forkAt], b)
+		}
+	}
+	for _, c := range commits {
+		gg.Checkout(c.branch.name)
+		var commit *Commit
+		if len(c.parents) > 1 && c.parents[1].branch != c.branch {
+			commit, _ = gg.Merge(c.parents[1].branch.name, c.short)
+		}
+		if commit == nil {
+			if commit, err = gg.Commit(c.short); err != nil {
+				return nil, fmt.Errorf("FromRepository: %s: %v", c.short, err)
+			}
+		}
+		commit.Tag = strings.Join(c.tags, ", ")
+		for _, b := range forks[c] {
+			gg.Checkout(c.branch.name)
+			gg.Branch(b.name)
+		}
+	}
+	return gg, nil
+}
+
+// Helperfunction to run git in the repository at path.
+func repoGit(path string, args ...string) (output string, err error) {
+	cmd := exec.Command("git", append([]string{"-C", path}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = err.Error()
+		}
+		return "", fmt.Errorf("FromRepository: git %s: %s", args[0], message)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Helperfunction to read the commits of all branches and tags, the oldest
+// first, parents before their children.
+func repoLog(path string, limit int) (commits []*repoCommit,
+	byHash map[string]*repoCommit, err error) {
+	byHash = make(map[string]*repoCommit)
+	args := []string{"log", "--branches", "--tags", "--topo-order",
+		"--format=%H%x00%h%x00%P%x00%s"}
+	if limit > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(limit))
+	}
+	out, err := repoGit(path, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	var parents [][]string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\x00", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		c := &repoCommit{hash: fields[0], short: fields[1], subject: fields[3]}
+		byHash[c.hash] = c
+		commits = append(commits, c)
+		parents = append(parents, strings.Fields(fields[2]))
+	}
+	for i, c := range commits {
+		for j, hash := range parents[i] {
+			p := byHash[hash]
+			if p == nil && j == 0 {
+				// without its first parent a commit starts a new line
+				break
+			}
+			if p != nil {
+				c.parents = append(c.parents, p)
+			}
+		}
+	}
+	// git log lists children before their parents
+	for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+		commits[i], commits[j] = commits[j], commits[i]
+	}
+	return commits, byHash, nil
+}
+
+// Helperfunction to assign each commit to a branch, the main branch first.
+func repoBranches(commits []*repoCommit, heads map[string]*repoCommit,
+	names []string, main string) (branches []*repoBranch) {
+	used := make(map[string]bool)
+	add := func(name string, head *repoCommit) {
+		name = repoInvalidChars.ReplaceAllString(name, "-")
+		if !IsValidBranchName(name) {
+			name = "_" + name
+		}
+		unique := name
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s-%d", name, i)
+		}
+		used[unique] = true
+		b := &repoBranch{name: unique, head: head}
+		branches = append(branches, b)
+		// follow the first parents until reaching a commit of another branch
+		c := head
+		for ; c != nil && c.branch == nil; c = firstParent(c) {
+			c.branch = b
+		}
+		b.forkAt = c
+	}
+	add(main, heads[main])
+	for _, name := range names {
+		if name != main {
+			add(name, heads[name])
+		}
+	}
+	// unnamed history, the newest first to get the longest branches
+	for i := len(commits) - 1; i >= 0; i-- {
+		c := commits[i]
+		if c.branch != nil {
+			continue
+		}
+		add(repoUnnamedBranch(c, commits), c)
+	}
+	// branches ending at a commit of another branch have no commits of their own
+	var withCommits []*repoBranch
+	for i, b := range branches {
+		if i == 0 || b.head != nil && b.head.branch == b {
+			withCommits = append(withCommits, b)
+		}
+	}
+	return withCommits
+}
+
+// Helperfunction to name a branch of unnamed history by the subject of the
+// commit merging it, its short hash otherwise.
+func repoUnnamedBranch(head *repoCommit, commits []*repoCommit) string {
+	for _, c := range commits {
+		if len(c.parents) < 2 || c.parents[1] != head {
+			continue
+		}
+		if m := repoMergeSubject.FindStringSubmatch(c.subject); m != nil {
+			return m[1] + m[2]
+		}
+	}
+	return "branch-" + head.short
+}
+
+// Helperfunction to get the first parent of a commit, nil if it has none.
+func firstParent(c *repoCommit) *repoCommit {
+	if len(c.parents) == 0 {
+		return nil
+	}
+	return c.parents[0]
+}
@@ -0,0 +1,89 @@
+package gitgraph_test
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen/gitgraph"
+)
+
+// Helperfunction to create a repository with merged and unmerged branches.
+func gitRepository(t *testing.T) (path string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	path = t.TempDir()
+	env := append(os.Environ(), "GIT_AUTHOR_NAME=test",
+		"GIT_AUTHOR_EMAIL=test@example.com", "GIT_COMMITTER_NAME=test",
+		"GIT_COMMITTER_EMAIL=test@example.com", "GIT_CONFIG_NOSYSTEM=1",
+		"HOME="+path)
+	for _, command := range []string{
+		"init -q -b master",
+		"commit -q --allow-empty -m one",
+		"tag -a v0.1 -m release",
+		"checkout -q -b feature",
+		"commit -q --allow-empty -m two",
+		"commit -q --allow-empty -m three",
+		"checkout -q master",
+		"commit -q --allow-empty -m four",
+		"merge -q --no-ff --no-edit feature",
+		"branch -q -D feature",
+		"checkout -q -b dev",
+		"commit -q --allow-empty -m five",
+		"tag light",
+		"checkout -q master",
+	} {
+		cmd := exec.Command("git", strings.Fields(command)...)
+		cmd.Dir, cmd.Env = path, env
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v: %s", command, err, out)
+		}
+	}
+	return
+}
+
+func TestFromRepository(t *testing.T) {
+	gg, err := gitgraph.FromRepository(gitRepository(t), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, b := range gg.ListBranches() {
+		names = append(names, b.Name())
+	}
+	if strings.Join(names, " ") != "master feature dev" {
+		t.Errorf("unexpected Branches %v", names)
+	}
+	commits := gg.ListCommits()
+	if len(commits) != 6 {
+		t.Fatalf("unexpected Commits %v", commits)
+	}
+	if commits[0].Tag != "v0.1" || commits[5].Tag != "light" {
+		t.Error("unexpected tags")
+	}
+	merge := gg.GetBranch("master").Head()
+	if len(merge.Parents()) != 2 ||
+		merge.Parents()[1] != gg.GetBranch("feature").Head() {
+		t.Error("unexpected merge")
+	}
+	if !strings.Contains(gg.String(), "\nmerge feature id: \""+merge.ID()+
+		"\"\n") || !strings.HasPrefix(gg.String(), "---\n") {
+		t.Errorf("unexpected rendering\n%s", gg)
+	}
+}
+
+func TestFromRepository_limit(t *testing.T) {
+	gg, err := gitgraph.FromRepository(gitRepository(t), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the merge and dev's commit, without their parents
+	if len(gg.ListCommits()) != 2 || len(gg.ListBranches()) != 2 {
+		t.Errorf("unexpected GitGraph\n%s", gg)
+	}
+	if _, err := gitgraph.FromRepository(t.TempDir(), 0); err == nil {
+		t.Error("missing repository not detected")
+	}
+}
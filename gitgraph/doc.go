@@ -0,0 +1,36 @@
+/*
+Package gitgraph is an object oriented approach to define mermaid git graphs as
+defined at https://mermaid.js.org/syntax/gitgraph.html and render them to
+mermaid code.
+
+You use the constructor NewGitGraph to create a new GitGraph object, optionally
+with the name of the main branch.
+
+	gg, _ := gitgraph.NewGitGraph()
+
+This object is used to perform git operations, which are checked against the
+state of the graph, e.g. merging a branch without commits returns an error.
+
+	gg.Commit("init")
+	gg.Branch("feature")
+	gg.Commit("work")
+	gg.Checkout("main")
+	merge, _ := gg.Merge("feature", "")
+	merge.Tag = "v1.0"
+
+Once the diagram is completely defined, it can be "rendered" to mermaid code by
+stringifying the GitGraph object or streamed via WriteTo.
+
+	gitGraph
+	commit id: "init"
+	branch feature
+	commit id: "work"
+	checkout main
+	merge feature tag: "v1.0"
+
+Instead of defining it manually, a GitGraph can be generated from the history
+of a local git repository via FromRepository, limited to the newest commits.
+
+	gg, err := gitgraph.FromRepository(".", 50)
+*/
+package gitgraph
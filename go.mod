@@ -1,3 +1,3 @@
 module github.com/Heiko-san/mermaidgen
 
-go 1.12
+go 1.18
@@ -0,0 +1,20 @@
+// Package counting provides an io.Writer that counts the bytes written, used
+// by the diagram packages to return the byte count from WriteTo.
+package counting
+
+import (
+	"io"
+)
+
+// Writer counts the bytes written to the underlying io.Writer.
+type Writer struct {
+	W io.Writer // The underlying io.Writer
+	N int64     // The number of bytes written so far
+}
+
+// Write implements io.Writer.
+func (cw *Writer) Write(p []byte) (n int, err error) {
+	n, err = cw.W.Write(p)
+	cw.N += int64(n)
+	return
+}
@@ -0,0 +1,18 @@
+package counting_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen/internal/counting"
+)
+
+func TestWriter(t *testing.T) {
+	var s strings.Builder
+	cw := &counting.Writer{W: &s}
+	cw.Write([]byte("graph"))
+	cw.Write([]byte(" TB\n"))
+	if cw.N != 9 || s.String() != "graph TB\n" {
+		t.Errorf("unexpected count %d for %q", cw.N, s.String())
+	}
+}
@@ -0,0 +1,222 @@
+package journey
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/internal/counting"
+)
+
+////////// Journey /////////////////////////////////////////////////////////////
+
+// Journey objects are the entrypoints to this package, the whole diagram is
+// constructed around a Journey object. Create an instance of Journey via
+// Journey's constructor NewJourney, do not create instances directly.
+//
+// A Journey consists of Tasks with a satisfaction score and the actors taking
+// part in them. Tasks can be grouped into Sections, Tasks without Section are
+// rendered first.
+type Journey struct {
+	taskList                        // Tasks without Section
+	sectionsMap map[string]*Section // lookup table for existing Sections
+	sections    []*Section          // Sections for ordered rendering
+	Title       string              // Optional title of the Journey
+	Config      *mermaidgen.Config  // Optional theme and configuration
+	Indent      string              // Optional indentation per level
+}
+
+// NewJourney is the constructor used to create a new Journey object.
+// This object is the entrypoint for any further interactions with your diagram.
+// Always use the constructor, don't create Journey objects directly.
+func NewJourney(title string) (newJourney *Journey) {
+	j := &Journey{Title: title}
+	j.taskList.journey = j
+	j.tasksMap = make(map[string]*Task)
+	j.sectionsMap = make(map[string]*Section)
+	return j
+}
+
+// String renders the whole diagram to mermaid code lines.
+// It is a shorthand for WriteTo with a strings.Builder.
+func (j *Journey) String() (renderedElement string) {
+	var s strings.Builder
+	j.WriteTo(&s)
+	return s.String()
+}
+
+// WriteTo writes the mermaid code of the whole Journey to w. The title,
+// Sections and Tasks are indented by Indent, Tasks of Sections twice.
+// Implements io.WriterTo.
+func (j *Journey) WriteTo(w io.Writer) (n int64, err error) {
+	cw := &counting.Writer{W: w}
+	b := bufio.NewWriter(cw)
+	b.WriteString(j.Config.String())
+	b.WriteString("journey\n")
+	if j.Title != "" {
+		b.WriteString(j.Indent + "title " + text(j.Title) + "\n")
+	}
+	j.render(b, j.Indent)
+	for _, s := range j.sections {
+		b.WriteString(j.Indent + "section " + text(s.title) + "\n")
+		s.render(b, j.Indent+j.Indent)
+	}
+	err = b.Flush()
+	return cw.N, err
+}
+
+// Render writes the mermaid code of the whole diagram to w.
+// Implements mermaidgen.Diagram.
+func (j *Journey) Render(w io.Writer) (err error) {
+	_, err = j.WriteTo(w)
+	return
+}
+
+// Kind returns mermaidgen.KindJourney. Implements mermaidgen.Diagram.
+func (j *Journey) Kind() (kind mermaidgen.DiagramKind) {
+	return mermaidgen.KindJourney
+}
+
+// GetConfig returns the Journey's Config, which may be nil.
+// Implements mermaidgen.Diagram.
+func (j *Journey) GetConfig() (config *mermaidgen.Config) {
+	return j.Config
+}
+
+// SetConfig replaces the Journey's Config, nil removes it.
+// Implements mermaidgen.Diagram.
+func (j *Journey) SetConfig(config *mermaidgen.Config) {
+	j.Config = config
+}
+
+// LiveURL renders the Journey and generates a view URL for
+// https://mermaid.live from it, see mermaidgen.LiveURL for details.
+func (j *Journey) LiveURL() (url string) {
+	url, _ = mermaidgen.LiveURL(j)
+	return
+}
+
+// ViewInBrowser uses the URL generated by Journey's LiveURL method and opens
+// that URL in the OS's default browser via mermaidgen.ViewInBrowser. It
+// eventually returns any error occured.
+func (j *Journey) ViewInBrowser() (err error) {
+	return mermaidgen.ViewInBrowser(j)
+}
+
+////////// add Items ///////////////////////////////////////////////////////////
+
+// AddSection is used to add a new Section with the given title to the
+// Journey. If a Section with this title already exists, it is returned
+// instead.
+func (j *Journey) AddSection(title string) (section *Section) {
+	section = j.sectionsMap[title]
+	if section == nil {
+		section = &Section{title: title}
+		section.taskList.journey = j
+		section.tasksMap = make(map[string]*Task)
+		j.sectionsMap[title] = section
+		j.sections = append(j.sections, section)
+	}
+	return
+}
+
+// AddTask is used to add a new Task without Section to the Journey, see
+// Section's AddTask for details.
+func (j *Journey) AddTask(name string, score int,
+	actors ...string) (newTask *Task, err error) {
+	return j.addTask(name, score, actors, nil)
+}
+
+////////// get Items ///////////////////////////////////////////////////////////
+
+// GetSection looks up a previously defined Section by its title.
+// If this title doesn't exist, nil is returned.
+// Use Journey's AddSection to create new Sections.
+func (j *Journey) GetSection(title string) (existingSection *Section) {
+	// if not found -> nil
+	return j.sectionsMap[title]
+}
+
+// GetTask looks up a previously defined Task without Section by its name.
+// If this name doesn't exist, nil is returned.
+// Use Journey's AddTask to create new Tasks.
+func (j *Journey) GetTask(name string) (existingTask *Task) {
+	// if not found -> nil
+	return j.tasksMap[name]
+}
+
+////////// list Items //////////////////////////////////////////////////////////
+
+// ListSections returns a slice of all Sections previously added to this
+// Journey in the order they are rendered.
+func (j *Journey) ListSections() (allSections []*Section) {
+	allSections = make([]*Section, len(j.sections))
+	copy(allSections, j.sections)
+	return
+}
+
+// ListTasks returns a slice of all Tasks without Section previously added to
+// this Journey in the order they are rendered.
+func (j *Journey) ListTasks() (allTasks []*Task) {
+	return j.listTasks()
+}
+
+////////// tasks ///////////////////////////////////////////////////////////////
+
+// The Tasks of a Journey or a Section.
+type taskList struct {
+	journey  *Journey         // top lvl pointer
+	tasksMap map[string]*Task // lookup table for existing Tasks
+	tasks    []*Task          // Tasks for ordered rendering
+}
+
+// Helperfunction to add a Task.
+func (tl *taskList) addTask(name string, score int, actors []string,
+	s *Section) (*Task, error) {
+	if !IsValidScore(score) {
+		return nil, fmt.Errorf("invalid score %d", score)
+	}
+	if _, alreadyExists := tl.tasksMap[name]; alreadyExists {
+		return nil, fmt.Errorf("task already exists")
+	}
+	t := &Task{name: name, journey: tl.journey, section: s, score: score,
+		Actors: actors}
+	tl.tasksMap[name] = t
+	tl.tasks = append(tl.tasks, t)
+	return t, nil
+}
+
+// Helperfunction to copy the Tasks.
+func (tl *taskList) listTasks() (allTasks []*Task) {
+	allTasks = make([]*Task, len(tl.tasks))
+	copy(allTasks, tl.tasks)
+	return
+}
+
+// Helperfunction to render the Tasks with the given indentation.
+func (tl *taskList) render(b *bufio.Writer, indentation string) {
+	for _, t := range tl.tasks {
+		b.WriteString(indentation + t.String())
+	}
+}
+
+////////// rendering helpers ///////////////////////////////////////////////////
+
+// Replacements of characters mermaid uses as separators or for comments in
+// journeys, which can't be escaped: they are replaced by their full width
+// forms.
+var textReplacer = strings.NewReplacer("#", "＃", ":", "：", ";", "；",
+	"\r\n", "<br>", "\n", "<br>")
+
+// Helperfunction to replace the separator characters of texts.
+func text(s string) string {
+	return textReplacer.Replace(s)
+}
+
+// Helperfunction to replace the separator characters of actors, which are
+// separated by commas in addition.
+func actor(s string) string {
+	return strings.Replace(textReplacer.Replace(s), ",", "，", -1)
+}
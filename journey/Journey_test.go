@@ -0,0 +1,72 @@
+package journey_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/journey"
+)
+
+// Defining and rendering a user journey
+func ExampleJourney() {
+	j := journey.NewJourney("My working day")
+	j.Indent = "  "
+	work := j.AddSection("Go to work")
+	work.AddTask("Make tea", 5, "Me")
+	work.AddTask("Go upstairs", 3, "Me")
+	work.AddTask("Do work", 1, "Me", "Cat")
+	home := j.AddSection("Go home")
+	home.AddTask("Go downstairs", 5, "Me")
+	sit, _ := home.AddTask("Sit down", 5)
+	sit.SetScore(4)
+	fmt.Print(j)
+	//Output:
+	//journey
+	//   title My working day
+	//   section Go to work
+	//     Make tea: 5: Me
+	//     Go upstairs: 3: Me
+	//     Do work: 1: Me, Cat
+	//   section Go home
+	//     Go downstairs: 5: Me
+	//     Sit down: 4
+}
+
+func TestJourney(t *testing.T) {
+	j := journey.NewJourney("")
+	s := j.AddSection("s")
+	for _, score := range []int{0, 6} {
+		if _, err := s.AddTask("t", score); err == nil {
+			t.Errorf("invalid score %d not detected", score)
+		}
+	}
+	task, err := s.AddTask("t", journey.ScoreNeutral)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddTask("t", 1); err == nil {
+		t.Error("duplicate Task not detected")
+	}
+	if err := task.SetScore(7); err == nil || task.Score() != 3 {
+		t.Error("invalid score not detected")
+	}
+	if j.AddSection("s") != s || j.GetSection("s") != s || s.GetTask("t") !=
+		task || task.Section() != s || task.Journey() != j || s.Journey() != j {
+		t.Error("unexpected lookup")
+	}
+	other, _ := j.AddTask("a: b, c; #1", 2, "x,y")
+	if other.Section() != nil || j.GetTask(other.Name()) != other ||
+		len(j.ListTasks()) != 1 || len(j.ListSections()) != 1 ||
+		len(s.ListTasks()) != 1 || s.Title() != "s" {
+		t.Error("unexpected lists")
+	}
+	want := "journey\na： b, c； ＃1: 2: x，y\nsection s\nt: 3\n"
+	if j.String() != want {
+		t.Errorf("unexpected rendering\n%s", j)
+	}
+	var d mermaidgen.Diagram = j
+	if d.Kind() != mermaidgen.KindJourney || d.GetConfig() != nil {
+		t.Error("unexpected Diagram")
+	}
+}
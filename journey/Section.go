@@ -0,0 +1,130 @@
+package journey
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Satisfaction scores of Tasks, mermaid draws a sad face below ScoreNeutral
+// and a happy one above.
+const (
+	ScoreMin     = 1
+	ScoreNeutral = 3
+	ScoreMax     = 5
+)
+
+// IsValidScore is used to check if satisfaction scores are within ScoreMin
+// and ScoreMax.
+func IsValidScore(score int) bool {
+	return score >= ScoreMin && score <= ScoreMax
+}
+
+////////// Section /////////////////////////////////////////////////////////////
+
+// Section represents a titled group of Tasks of the Journey, e.g. a step of
+// the user journey. Create an instance of Section via Journey's AddSection
+// method, do not create instances directly. Already defined titles can be
+// looked up via Journey's GetSection method or iterated over via its
+// ListSections method.
+type Section struct {
+	taskList
+	title string
+}
+
+// Title provides access to the Section's readonly field title.
+func (s *Section) Title() (title string) {
+	return s.title
+}
+
+// Journey provides access to the top level Journey to be able to access
+// Adder, Getter and Lister methods.
+func (s *Section) Journey() (topLevel *Journey) {
+	return s.journey
+}
+
+// AddTask is used to add a new Task with the given name, satisfaction score
+// and optional actors to the Section. If the score is invalid (see
+// IsValidScore) or the name already exists in the Section, no Task is created
+// and an error is returned.
+func (s *Section) AddTask(name string, score int,
+	actors ...string) (newTask *Task, err error) {
+	return s.addTask(name, score, actors, s)
+}
+
+// GetTask looks up a previously defined Task of the Section by its name.
+// If this name doesn't exist, nil is returned.
+// Use Section's AddTask to create new Tasks.
+func (s *Section) GetTask(name string) (existingTask *Task) {
+	// if not found -> nil
+	return s.tasksMap[name]
+}
+
+// ListTasks returns a slice of all Tasks previously added to this Section in
+// the order they are rendered.
+func (s *Section) ListTasks() (allTasks []*Task) {
+	return s.listTasks()
+}
+
+////////// Task ////////////////////////////////////////////////////////////////
+
+// Task represents a task of the Journey with the satisfaction score of the
+// user. Create an instance of Task via Journey's or Section's AddTask method,
+// do not create instances directly. Already defined names can be looked up via
+// GetTask or iterated over via ListTasks of the same type.
+type Task struct {
+	name    string
+	journey *Journey // top lvl pointer
+	section *Section // nil for Tasks without Section
+	score   int
+	Actors  []string // Optional actors taking part in the Task
+}
+
+// Name provides access to the Task's readonly field name.
+func (t *Task) Name() (name string) {
+	return t.name
+}
+
+// Journey provides access to the top level Journey to be able to access
+// Adder, Getter and Lister methods.
+func (t *Task) Journey() (topLevel *Journey) {
+	return t.journey
+}
+
+// Section returns the Section the Task belongs to, nil if it has none.
+func (t *Task) Section() (section *Section) {
+	return t.section
+}
+
+// Score returns the satisfaction score of the Task.
+func (t *Task) Score() (score int) {
+	return t.score
+}
+
+// SetScore changes the satisfaction score of the Task. If the score is invalid
+// (see IsValidScore), it isn't changed and an error is returned.
+func (t *Task) SetScore(score int) (err error) {
+	if !IsValidScore(score) {
+		return fmt.Errorf("invalid score %d", score)
+	}
+	t.score = score
+	return nil
+}
+
+// String renders this diagram element to a task line. Characters mermaid
+// uses as separators (#:; and , in actors) are replaced by their full width
+// forms.
+func (t *Task) String() (renderedElement string) {
+	var b strings.Builder
+	b.WriteString(text(t.name) + ": " + strconv.Itoa(t.score))
+	for i, a := range t.Actors {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString(", ")
+		}
+		b.WriteString(actor(a))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,136 @@
+package journey
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+////////// survey responses ////////////////////////////////////////////////////
+
+// Response is the satisfaction score one participant of a survey gave a Task,
+// passed to FromResponses.
+type Response struct {
+	Section string // Optional Section of the Task
+	Task    string // Name of the Task
+	Score   int    // Satisfaction score, see IsValidScore
+	Actor   string // Optional actor, e.g. the participant or a persona
+}
+
+// The aggregated Responses of a Task.
+type responseSum struct {
+	section, task string
+	sum, count    int
+	actors        []string
+}
+
+// FromResponses creates a Journey with the given title from survey responses.
+// The Responses for the same Task of the same Section are aggregated into one
+// Task, its score is the average score rounded to the nearest integer and its
+// actors are all distinct actors in order of appearance. Sections and Tasks
+// are ordered by their first Response. An error is returned if a Response has
+// no Task or an invalid score.
+func FromResponses(title string,
+	responses []Response) (newJourney *Journey, err error) {
+	sums := make(map[[2]string]*responseSum)
+	var order []*responseSum
+	for i, r := range responses {
+		if r.Task == "" {
+			return nil, fmt.Errorf("FromResponses: response %d has no task", i)
+		}
+		if !IsValidScore(r.Score) {
+			return nil, fmt.Errorf("FromResponses: response %d: invalid score %d",
+				i, r.Score)
+		}
+		key := [2]string{r.Section, r.Task}
+		s := sums[key]
+		if s == nil {
+			s = &responseSum{section: r.Section, task: r.Task}
+			sums[key] = s
+			order = append(order, s)
+		}
+		s.sum += r.Score
+		s.count++
+		if r.Actor != "" && !contains(s.actors, r.Actor) {
+			s.actors = append(s.actors, r.Actor)
+		}
+	}
+	j := NewJourney(title)
+	for _, s := range order {
+		score := int(math.Round(float64(s.sum) / float64(s.count)))
+		if s.section == "" {
+			j.AddTask(s.task, score, s.actors...)
+		} else {
+			j.AddSection(s.section).AddTask(s.task, score, s.actors...)
+		}
+	}
+	return j, nil
+}
+
+// FromCSV creates a Journey with the given title from survey responses in CSV
+// format, see FromResponses for the aggregation. The first row names the
+// columns, task and score are required, section and actor are optional, other
+// columns (e.g. a timestamp or comments) are ignored. Rows with an empty score
+// are skipped as unanswered.
+//
+//	section,task,score,actor
+//	Go to work,Make tea,5,Me
+//	Go to work,Make tea,4,Cat
+func FromCSV(title string, r io.Reader) (newJourney *Journey, err error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	cr.FieldsPerRecord = -1
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("FromCSV: %v", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("FromCSV: no header row found")
+	}
+	columns := make(map[string]int)
+	for i, name := range rows[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"task", "score"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("FromCSV: missing column %q", name)
+		}
+	}
+	var responses []Response
+	for n, row := range rows[1:] {
+		line := n + 2
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		if field("score") == "" {
+			continue
+		}
+		score, err := strconv.Atoi(field("score"))
+		if err != nil || !IsValidScore(score) {
+			return nil, fmt.Errorf("FromCSV: line %d: invalid score %q", line,
+				field("score"))
+		}
+		if field("task") == "" {
+			return nil, fmt.Errorf("FromCSV: line %d: no task", line)
+		}
+		responses = append(responses, Response{Section: field("section"),
+			Task: field("task"), Score: score, Actor: field("actor")})
+	}
+	return FromResponses(title, responses)
+}
+
+// Helperfunction to check whether a slice contains a string.
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
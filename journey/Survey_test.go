@@ -0,0 +1,69 @@
+package journey_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen/journey"
+)
+
+// Aggregating survey responses from a CSV file
+func ExampleFromCSV() {
+	survey := `Timestamp,Section,Task,Score,Actor
+2024-05-01 10:00,Sign up,Open the app,5,Alice
+2024-05-01 10:05,Sign up,Open the app,4,Bob
+2024-05-01 10:05,Sign up,Verify email,2,Bob
+2024-05-02 09:00,Sign up,Verify email,,Carol
+2024-05-02 09:00,Sign up,Verify email,1,Carol
+2024-05-02 09:01,Checkout,Pay,3,Alice
+2024-05-02 09:02,Checkout,Pay,4,Alice
+`
+	j, _ := journey.FromCSV("Onboarding", strings.NewReader(survey))
+	fmt.Print(j)
+	//Output:
+	//journey
+	//title Onboarding
+	//section Sign up
+	//Open the app: 5: Alice, Bob
+	//Verify email: 2: Bob, Carol
+	//section Checkout
+	//Pay: 4: Alice
+}
+
+func TestFromResponses(t *testing.T) {
+	j, err := journey.FromResponses("", []journey.Response{
+		{Task: "a", Score: 2}, {Task: "a", Score: 3, Actor: "x"},
+		{Section: "s", Task: "a", Score: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.String() != "journey\na: 3: x\nsection s\na: 1\n" {
+		t.Errorf("unexpected Journey\n%s", j)
+	}
+	if _, err := journey.FromResponses("", []journey.Response{
+		{Task: "a", Score: 9}}); err == nil {
+		t.Error("invalid score not detected")
+	}
+	if _, err := journey.FromResponses("", []journey.Response{
+		{Score: 1}}); err == nil {
+		t.Error("missing task not detected")
+	}
+}
+
+func TestFromCSV(t *testing.T) {
+	j, err := journey.FromCSV("", strings.NewReader(
+		"task,score,actor\n\"Login: SSO\",4,\"Doe, Jane\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.String() != "journey\nLogin： SSO: 4: Doe， Jane\n" {
+		t.Errorf("unexpected Journey\n%s", j)
+	}
+	for _, survey := range []string{"", "task\n", "task,score\na,x\n",
+		"task,score\na,0\n", "task,score\n,1\n", "task,score\n\"a\n"} {
+		if _, err := journey.FromCSV("", strings.NewReader(survey)); err == nil {
+			t.Errorf("invalid CSV %q not detected", survey)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+/*
+Package journey is an object oriented approach to define mermaid user journey
+diagrams as defined at https://mermaid.js.org/syntax/userJourney.html and render
+them to mermaid code.
+
+You use the constructor NewJourney to create a new Journey object with its
+title.
+
+	j := journey.NewJourney("My working day")
+
+This object is used to add Tasks with a satisfaction score from 1 to 5 and the
+actors taking part, optionally grouped into Sections. Invalid scores are
+rejected.
+
+	work := j.AddSection("Go to work")
+	work.AddTask("Make tea", 5, "Me")
+	work.AddTask("Do work", 1, "Me", "Cat")
+
+Once the diagram is completely defined, it can be "rendered" to mermaid code by
+stringifying the Journey object or streamed via WriteTo.
+
+	journey
+	title My working day
+	section Go to work
+	Make tea: 5: Me
+	Do work: 1: Me, Cat
+
+Instead of defining it manually, a Journey can be generated from survey
+responses via FromResponses, which averages the scores per Task, or from a CSV
+file of such responses via FromCSV.
+
+	f, _ := os.Open("survey.csv")
+	j, err := journey.FromCSV("Onboarding", f)
+*/
+package journey
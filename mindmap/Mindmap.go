@@ -0,0 +1,123 @@
+package mindmap
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/internal/counting"
+)
+
+////////// Mindmap /////////////////////////////////////////////////////////////
+
+// Mindmap objects are the entrypoints to this package, the whole diagram is
+// constructed around a Mindmap object. Create an instance of Mindmap via
+// Mindmap's constructor NewMindmap, do not create instances directly.
+//
+// A Mindmap is a tree of Nodes, starting at its root Node. mermaid derives the
+// hierarchy from the indentation of the Nodes, so each level is indented by
+// one more Indent.
+type Mindmap struct {
+	root   *Node              // the root of the tree
+	Config *mermaidgen.Config // Optional theme and configuration
+	Indent string             // Indentation per level, "  " if empty
+}
+
+// NewMindmap is the constructor used to create a new Mindmap object.
+// This object is the entrypoint for any further interactions with your diagram.
+// Always use the constructor, don't create Mindmap objects directly.
+// The text of the root Node has to be given.
+func NewMindmap(root string) (newMindmap *Mindmap) {
+	m := &Mindmap{Indent: "  "}
+	m.root = &Node{mindmap: m, Text: root}
+	return m
+}
+
+// String renders the whole diagram to mermaid code lines.
+// It is a shorthand for WriteTo with a strings.Builder.
+func (m *Mindmap) String() (renderedElement string) {
+	var s strings.Builder
+	m.WriteTo(&s)
+	return s.String()
+}
+
+// WriteTo writes the mermaid code of the whole Node tree to w. Each Node is
+// indented by Indent once per level, the root Node included.
+// Implements io.WriterTo.
+func (m *Mindmap) WriteTo(w io.Writer) (n int64, err error) {
+	cw := &counting.Writer{W: w}
+	b := bufio.NewWriter(cw)
+	b.WriteString(m.Config.String())
+	b.WriteString("mindmap\n")
+	indent := m.Indent
+	if indent == "" {
+		indent = "  "
+	}
+	id := 0
+	m.root.render(b, indent, indent, &id)
+	err = b.Flush()
+	return cw.N, err
+}
+
+// Render writes the mermaid code of the whole diagram to w.
+// Implements mermaidgen.Diagram.
+func (m *Mindmap) Render(w io.Writer) (err error) {
+	_, err = m.WriteTo(w)
+	return
+}
+
+// Kind returns mermaidgen.KindMindmap. Implements mermaidgen.Diagram.
+func (m *Mindmap) Kind() (kind mermaidgen.DiagramKind) {
+	return mermaidgen.KindMindmap
+}
+
+// GetConfig returns the Mindmap's Config, which may be nil.
+// Implements mermaidgen.Diagram.
+func (m *Mindmap) GetConfig() (config *mermaidgen.Config) {
+	return m.Config
+}
+
+// SetConfig replaces the Mindmap's Config, nil removes it.
+// Implements mermaidgen.Diagram.
+func (m *Mindmap) SetConfig(config *mermaidgen.Config) {
+	m.Config = config
+}
+
+// LiveURL renders the Mindmap and generates a view URL for
+// https://mermaid.live from it, see mermaidgen.LiveURL for details.
+func (m *Mindmap) LiveURL() (url string) {
+	url, _ = mermaidgen.LiveURL(m)
+	return
+}
+
+// ViewInBrowser uses the URL generated by Mindmap's LiveURL method and opens
+// that URL in the OS's default browser via mermaidgen.ViewInBrowser. It
+// eventually returns any error occured.
+func (m *Mindmap) ViewInBrowser() (err error) {
+	return mermaidgen.ViewInBrowser(m)
+}
+
+////////// get Items ///////////////////////////////////////////////////////////
+
+// Root returns the root Node of the Mindmap. Use Node's AddChild to grow the
+// tree.
+func (m *Mindmap) Root() (root *Node) {
+	return m.root
+}
+
+////////// list Items //////////////////////////////////////////////////////////
+
+// ListNodes returns a slice of all Nodes of this Mindmap in the order they are
+// rendered, depth first starting with the root Node.
+func (m *Mindmap) ListNodes() (allNodes []*Node) {
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		allNodes = append(allNodes, n)
+		for _, c := range n.children {
+			walk(c)
+		}
+	}
+	walk(m.root)
+	return
+}
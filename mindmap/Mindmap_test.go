@@ -0,0 +1,70 @@
+package mindmap_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/mindmap"
+)
+
+// Defining and rendering a mindmap
+func ExampleMindmap() {
+	m := mindmap.NewMindmap("mindmap")
+	m.Root().Shape = mindmap.ShapeCircle
+	origins := m.Root().AddChild("Origins")
+	history := origins.AddChild("Long history")
+	history.Icon = "fa fa-book"
+	origins.AddChild("Popularisation").AddChild("Tony Buzan")
+	research := m.Root().AddChild("Research")
+	research.Shape = mindmap.ShapeCloud
+	research.Classes = []string{"urgent", "large"}
+	research.AddChild("On effectiveness\nand features").Shape =
+		mindmap.ShapeHexagon
+	m.Root().AddChild("Tools (pen and paper)")
+	fmt.Print(m)
+	//Output:
+	//mindmap
+	//   n1((mindmap))
+	//     Origins
+	//       Long history
+	//       ::icon(fa fa-book)
+	//       Popularisation
+	//         Tony Buzan
+	//     n2)Research(
+	//     :::urgent large
+	//       n3{{On effectiveness<br>and features}}
+	//     n4["Tools (pen and paper)"]
+}
+
+func TestNode(t *testing.T) {
+	m := mindmap.NewMindmap("root")
+	a := m.Root().AddChild("a")
+	b := a.AddChild("b")
+	c := m.Root().AddChild("c")
+	if b.Depth() != 2 || b.Parent() != a || m.Root().Parent() != nil ||
+		b.Mindmap() != m {
+		t.Error("unexpected Node")
+	}
+	children := m.Root().ListChildren()
+	if len(children) != 2 || children[1] != c {
+		t.Error("unexpected children")
+	}
+	nodes := m.ListNodes()
+	if len(nodes) != 4 || nodes[2] != b || nodes[3] != c {
+		t.Error("unexpected Nodes")
+	}
+	m.Indent = ""
+	b.Text = `say "hi"`
+	b.Shape = mindmap.ShapeBang
+	c.Text = "::x"
+	want := "mindmap\n  root\n    a\n      n1))\"say #quot;hi#quot;\"((\n" +
+		"    n2[\"::x\"]\n"
+	if m.String() != want {
+		t.Errorf("unexpected rendering\n%s", m)
+	}
+	var d mermaidgen.Diagram = m
+	if d.Kind() != mermaidgen.KindMindmap || d.GetConfig() != nil {
+		t.Error("unexpected Diagram")
+	}
+}
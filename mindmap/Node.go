@@ -0,0 +1,106 @@
+package mindmap
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+)
+
+type nodeShape string
+
+// Shape definitions for Nodes as described at
+// https://mermaid.js.org/syntax/mindmap.html#different-shapes.
+// The default is ShapeDefault.
+const (
+	ShapeDefault nodeShape = ``
+	ShapeSquare  nodeShape = `[]`
+	ShapeRounded nodeShape = `()`
+	ShapeCircle  nodeShape = `(())`
+	ShapeBang    nodeShape = `))((`
+	ShapeCloud   nodeShape = `)(`
+	ShapeHexagon nodeShape = `{{}}`
+)
+
+// Node represents a node of the Mindmap's tree. Create an instance of Node via
+// Node's AddChild method, do not create instances directly, the root Node is
+// created by Mindmap's constructor. All Nodes can be iterated over via
+// Mindmap's ListNodes method.
+type Node struct {
+	mindmap  *Mindmap  // top lvl pointer
+	parent   *Node     // nil for the root Node
+	children []*Node   // child Nodes for ordered rendering
+	Text     string    // The text, may contain line breaks
+	Shape    nodeShape // Optional shape, ShapeSquare if Text needs quoting
+	Icon     string    // Optional icon classes, e.g. "fa fa-book"
+	Classes  []string  // Optional CSS classes of the Node
+}
+
+// Mindmap provides access to the top level Mindmap to be able to access the
+// root Node and Lister methods.
+func (n *Node) Mindmap() (topLevel *Mindmap) {
+	return n.mindmap
+}
+
+// Parent returns the Node this Node is a child of, nil for the root Node.
+func (n *Node) Parent() (parent *Node) {
+	return n.parent
+}
+
+// Depth returns the level of the Node in the tree, 0 for the root Node.
+func (n *Node) Depth() (depth int) {
+	for p := n.parent; p != nil; p = p.parent {
+		depth++
+	}
+	return
+}
+
+// AddChild is used to add a new Node with the given text as last child of this
+// Node.
+func (n *Node) AddChild(text string) (newNode *Node) {
+	newNode = &Node{mindmap: n.mindmap, parent: n, Text: text}
+	n.children = append(n.children, newNode)
+	return
+}
+
+// ListChildren returns a slice of the Node's children in the order they are
+// rendered.
+func (n *Node) ListChildren() (children []*Node) {
+	children = make([]*Node, len(n.children))
+	copy(children, n.children)
+	return
+}
+
+// Helperfunction to render the Node and its children, each level indented by
+// one more indent. Shapes need an ID, which is generated from the counter id.
+func (n *Node) render(b *bufio.Writer, indentation, indent string, id *int) {
+	text := strings.Replace(n.Text, "\n", "<br>", -1)
+	shape := n.Shape
+	// characters of the shape delimiters have to be quoted
+	needsQuotes := strings.ContainsAny(text, `()[]{}"`) || text == "" ||
+		strings.HasPrefix(text, "::")
+	if needsQuotes {
+		text = `"` + strings.Replace(text, `"`, "#quot;", -1) + `"`
+		if shape == ShapeDefault {
+			shape = ShapeSquare
+		}
+	}
+	b.WriteString(indentation)
+	if shape != ShapeDefault {
+		*id++
+		half := len(shape) / 2
+		b.WriteString("n" + strconv.Itoa(*id) + string(shape[:half]) + text +
+			string(shape[half:]))
+	} else {
+		b.WriteString(text)
+	}
+	b.WriteString("\n")
+	if n.Icon != "" {
+		b.WriteString(indentation + "::icon(" + n.Icon + ")\n")
+	}
+	if len(n.Classes) > 0 {
+		b.WriteString(indentation + ":::" + strings.Join(n.Classes, " ") + "\n")
+	}
+	for _, c := range n.children {
+		c.render(b, indentation+indent, indent, id)
+	}
+}
@@ -0,0 +1,182 @@
+package mindmap
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+////////// file system /////////////////////////////////////////////////////////
+
+// FSOptions control which entries of a file system FromFS puts into the
+// Mindmap. A nil *FSOptions uses the zero value.
+type FSOptions struct {
+	MaxDepth int  // Levels of entries below root, all if <= 0.
+	Hidden   bool // Include entries whose names start with a dot.
+}
+
+// FromFS creates a Mindmap from the directory tree at root of the file system
+// fsys, e.g. os.DirFS("."), to visualize a project structure. The root Node
+// gets the base name of root, directories become children with ShapeSquare,
+// files children with ShapeDefault, both ordered by name. Directories at
+// MaxDepth are added without their entries.
+func FromFS(fsys fs.FS, root string, options *FSOptions) (newMindmap *Mindmap,
+	err error) {
+	if options == nil {
+		options = &FSOptions{}
+	}
+	m := NewMindmap(path.Base(root))
+	var walk func(n *Node, dir string, depth int) error
+	walk = func(n *Node, dir string, depth int) error {
+		entries, err := fs.ReadDir(fsys, dir)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if strings.HasPrefix(e.Name(), ".") && !options.Hidden {
+				continue
+			}
+			child := n.AddChild(e.Name())
+			if !e.IsDir() {
+				continue
+			}
+			child.Shape = ShapeSquare
+			if options.MaxDepth <= 0 || depth < options.MaxDepth {
+				err := walk(child, path.Join(dir, e.Name()), depth+1)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err = walk(m.root, root, 1); err != nil {
+		return nil, fmt.Errorf("FromFS: %v", err)
+	}
+	return m, nil
+}
+
+////////// nested maps /////////////////////////////////////////////////////////
+
+// FromMap creates a Mindmap with the given root text from a nested map, e.g.
+// unmarshaled JSON or YAML. Each key becomes a Node, ordered by key, with its
+// value as children: maps add their keys, slices and arrays their elements,
+// other values become a single child formatted via fmt.Sprint, nil adds
+// nothing. Maps within slices add their keys directly. An error is returned if
+// tree is no map.
+func FromMap(root string, tree interface{}) (newMindmap *Mindmap, err error) {
+	v := reflect.ValueOf(tree)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Map {
+		return nil, fmt.Errorf("FromMap: %T is no map", tree)
+	}
+	m := NewMindmap(root)
+	mapChildren(m.root, v)
+	return m, nil
+}
+
+// Helperfunction to add a value of a nested map as children of n.
+func mapChildren(n *Node, v reflect.Value) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Invalid:
+	case reflect.Map:
+		keys := v.MapKeys()
+		texts := make([]string, len(keys))
+		for i, k := range keys {
+			texts[i] = fmt.Sprint(k.Interface())
+		}
+		order := make([]int, len(keys))
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(i, j int) bool {
+			return texts[order[i]] < texts[order[j]]
+		})
+		for _, i := range order {
+			mapChildren(n.AddChild(texts[i]), v.MapIndex(keys[i]))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			mapChildren(n, v.Index(i))
+		}
+	default:
+		n.AddChild(fmt.Sprint(v.Interface()))
+	}
+}
+
+////////// markdown ////////////////////////////////////////////////////////////
+
+var (
+	mdHeading = regexp.MustCompile(
+		`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	mdListItem = regexp.MustCompile(
+		`^([ \t]*)(?:[-*+]|\d{1,9}[.)])[ \t]+(.*?)[ \t]*$`)
+	mdFence = regexp.MustCompile("^ {0,3}(```|~~~)")
+)
+
+// FromMarkdown creates a Mindmap from the outline of a markdown document, e.g.
+// meeting notes. Headings become Nodes nested by their level, list items
+// become Nodes nested by their indentation below the preceding heading. Other
+// lines, like paragraphs and code blocks, are skipped. If the outline has a
+// single top level item (usually a # heading), it becomes the root Node,
+// otherwise a root Node with the given root text is added.
+func FromMarkdown(r io.Reader, root string) (newMindmap *Mindmap, err error) {
+	m := NewMindmap(root)
+	// open Nodes with their levels, headings 1-6, list items deeper
+	type level struct {
+		depth int
+		node  *Node
+	}
+	stack := []level{{0, m.root}}
+	add := func(depth int, text string) {
+		for stack[len(stack)-1].depth >= depth {
+			stack = stack[:len(stack)-1]
+		}
+		n := stack[len(stack)-1].node.AddChild(text)
+		stack = append(stack, level{depth, n})
+	}
+	fence := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if match := mdFence.FindStringSubmatch(line); match != nil {
+			if fence == "" {
+				fence = match[1]
+			} else if fence == match[1] {
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+		if match := mdHeading.FindStringSubmatch(line); match != nil {
+			add(len(match[1]), match[2])
+		} else if match := mdListItem.FindStringSubmatch(line); match != nil {
+			indent := strings.Replace(match[1], "\t", "    ", -1)
+			add(7+len(indent), match[2])
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("FromMarkdown: %v", err)
+	}
+	if len(m.root.children) == 1 {
+		m.root = m.root.children[0]
+		m.root.parent = nil
+	}
+	return m, nil
+}
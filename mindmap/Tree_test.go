@@ -0,0 +1,128 @@
+package mindmap_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/Heiko-san/mermaidgen/mindmap"
+)
+
+// Visualizing a project structure
+func ExampleFromFS() {
+	fsys := fstest.MapFS{
+		"project/go.mod":             {},
+		"project/.git/config":        {},
+		"project/cmd/tool/main.go":   {},
+		"project/internal/db/db.go":  {},
+		"project/internal/db/sql.go": {},
+		"project/README.md":          {},
+	}
+	m, _ := mindmap.FromFS(fsys, "project", &mindmap.FSOptions{MaxDepth: 2})
+	fmt.Print(m)
+	//Output:
+	//mindmap
+	//   project
+	//     README.md
+	//     n1[cmd]
+	//       n2[tool]
+	//     go.mod
+	//     n3[internal]
+	//       n4[db]
+}
+
+// Converting a nested map, e.g. from YAML
+func ExampleFromMap() {
+	tree := map[string]interface{}{
+		"Backend":  []string{"Go", "PostgreSQL"},
+		"Frontend": map[string]interface{}{"Web": "TypeScript", "Apps": nil},
+		"Ops": []interface{}{"Kubernetes",
+			map[string]interface{}{"CI": []int{1, 2}}},
+	}
+	m, _ := mindmap.FromMap("Stack", tree)
+	fmt.Print(m)
+	//Output:
+	//mindmap
+	//   Stack
+	//     Backend
+	//       Go
+	//       PostgreSQL
+	//     Frontend
+	//       Apps
+	//       Web
+	//         TypeScript
+	//     Ops
+	//       Kubernetes
+	//       CI
+	//         1
+	//         2
+}
+
+// Converting the outline of meeting notes
+func ExampleFromMarkdown() {
+	notes := `# Weekly sync
+
+Some introduction, which is skipped.
+
+## Decisions
+- Release on Friday
+  - after the freeze
+- Drop support for v1
+
+## Action items
+1. Update docs
+2. Ping the team
+` + "```" + `
+# no heading
+` + "```"
+	m, _ := mindmap.FromMarkdown(strings.NewReader(notes), "Notes")
+	fmt.Print(m)
+	//Output:
+	//mindmap
+	//   Weekly sync
+	//     Decisions
+	//       Release on Friday
+	//         after the freeze
+	//       Drop support for v1
+	//     Action items
+	//       Update docs
+	//       Ping the team
+}
+
+func TestFromFS(t *testing.T) {
+	fsys := fstest.MapFS{"a/.hidden/x": {}, "a/b/c/d": {}}
+	m, err := mindmap.FromFS(fsys, "a", &mindmap.FSOptions{Hidden: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.ListNodes()) != 6 || m.Root().Text != "a" {
+		t.Errorf("unexpected Mindmap\n%s", m)
+	}
+	if _, err := mindmap.FromFS(fsys, "nope", nil); err == nil {
+		t.Error("missing directory not detected")
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	if _, err := mindmap.FromMap("x", []string{"a"}); err == nil {
+		t.Error("no map not detected")
+	}
+	m, err := mindmap.FromMap("x", &map[int]bool{2: true, 1: false})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "mindmap\n  x\n    1\n      false\n    2\n      true\n"
+	if m.String() != want {
+		t.Errorf("unexpected Mindmap\n%s", m)
+	}
+}
+
+func TestFromMarkdown(t *testing.T) {
+	m, _ := mindmap.FromMarkdown(strings.NewReader(
+		"# A\n# B ##\n- b1\n\t- b2\n### C\n"), "root")
+	want := "mindmap\n  root\n    A\n    B\n      b1\n        b2\n      C\n"
+	if m.String() != want {
+		t.Errorf("unexpected Mindmap\n%s", m)
+	}
+}
@@ -0,0 +1,34 @@
+/*
+Package mindmap is an object oriented approach to define mermaid mindmaps as
+defined at https://mermaid.js.org/syntax/mindmap.html and render them to
+mermaid code.
+
+You use the constructor NewMindmap to create a new Mindmap object with the text
+of its root Node.
+
+	m := mindmap.NewMindmap("Project")
+
+The tree is grown by adding children to the root Node and to their children.
+Nodes can have a shape, an icon and CSS classes.
+
+	docs := m.Root().AddChild("Docs")
+	docs.Shape = mindmap.ShapeCloud
+	docs.AddChild("README").Icon = "fa fa-book"
+
+Once the diagram is completely defined, it can be "rendered" to mermaid code by
+stringifying the Mindmap object or streamed via WriteTo. The levels of the tree
+are indented, mermaid derives the hierarchy from the indentation.
+
+	mindmap
+	  Project
+	    n1)Docs(
+	      README
+	      ::icon(fa fa-book)
+
+Instead of defining it manually, a Mindmap can be generated from a directory
+tree via FromFS, from a nested map, e.g. unmarshaled YAML, via FromMap or from
+the headings and lists of a markdown document via FromMarkdown.
+
+	m, err := mindmap.FromFS(os.DirFS("."), ".", &mindmap.FSOptions{MaxDepth: 2})
+*/
+package mindmap
@@ -0,0 +1,44 @@
+package pie
+
+import (
+	"fmt"
+)
+
+// FromMap creates a Pie with a Slice for each entry of values, sorted by value
+// (see Pie's SortByValue) to get a stable output. An error is returned if a
+// value is invalid, see Pie's AddSlice.
+func FromMap(title string, values map[string]float64) (newPie *Pie, err error) {
+	p := NewPie(title)
+	for label, value := range values {
+		if _, err = p.AddSlice(label, value); err != nil {
+			return nil, fmt.Errorf("FromMap: %q: %v", label, err)
+		}
+	}
+	p.SortByValue()
+	return p, nil
+}
+
+// CountBy creates a Pie that counts the items per label, key returns the label
+// of an item. The Slices are ordered by the first occurrence of their labels.
+func CountBy[T any](title string, items []T, key func(T) string) (newPie *Pie) {
+	p := NewPie(title)
+	for _, item := range items {
+		p.AddSlice(key(item), 1)
+	}
+	return p
+}
+
+// SumBy creates a Pie that sums up the values of the items per label, key
+// returns the label and value the value of an item. The Slices are ordered by
+// the first occurrence of their labels. An error is returned if a value is
+// invalid, see Pie's AddSlice.
+func SumBy[T any](title string, items []T, key func(T) string,
+	value func(T) float64) (newPie *Pie, err error) {
+	p := NewPie(title)
+	for i, item := range items {
+		if _, err = p.AddSlice(key(item), value(item)); err != nil {
+			return nil, fmt.Errorf("SumBy: item %d: %v", i, err)
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,60 @@
+package pie_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen/pie"
+)
+
+// Aggregating values from a map
+func ExampleFromMap() {
+	chart, _ := pie.FromMap("Hours", map[string]float64{
+		"Meetings": 6, "Coding": 22.5, "Review": 6, "Support": 4})
+	fmt.Print(chart)
+	//Output:
+	//pie
+	//title Hours
+	//"Coding" : 22.5
+	//"Meetings" : 6
+	//"Review" : 6
+	//"Support" : 4
+}
+
+// Aggregating a slice of records
+func ExampleSumBy() {
+	type ticket struct {
+		Team  string
+		Hours float64
+	}
+	tickets := []ticket{{"ops", 3}, {"dev", 5}, {"ops", 1.5}, {"qa", 2}}
+	byTeam := func(t ticket) string { return t.Team }
+	hours, _ := pie.SumBy("Hours per team", tickets, byTeam,
+		func(t ticket) float64 { return t.Hours })
+	fmt.Print(hours)
+	fmt.Print(pie.CountBy("Tickets per team", tickets, byTeam))
+	//Output:
+	//pie
+	//title Hours per team
+	//"ops" : 4.5
+	//"dev" : 5
+	//"qa" : 2
+	//pie
+	//title Tickets per team
+	//"ops" : 2
+	//"dev" : 1
+	//"qa" : 1
+}
+
+func TestAggregate_errors(t *testing.T) {
+	if _, err := pie.FromMap("", map[string]float64{"x": -1}); err == nil {
+		t.Error("invalid value not detected")
+	}
+	_, err := pie.SumBy("", []float64{1, math.NaN()},
+		func(float64) string { return "x" },
+		func(f float64) float64 { return f })
+	if err == nil || err.Error() != "SumBy: item 1: AddSlice: invalid value NaN" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package pie
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/internal/counting"
+)
+
+////////// Pie /////////////////////////////////////////////////////////////////
+
+// Pie objects are the entrypoints to this package, the whole chart is
+// constructed around a Pie object. Create an instance of Pie via Pie's
+// constructor NewPie, do not create instances directly.
+type Pie struct {
+	slicesMap map[string]*Slice  // lookup table for existing Slices
+	slices    []*Slice           // Slices for ordered rendering
+	Title     string             // Optional title of the Pie chart
+	ShowData  bool               // Render the values next to the legend
+	Config    *mermaidgen.Config // Optional theme and configuration
+	Indent    string             // Optional indentation of title and Slices
+}
+
+// NewPie is the constructor used to create a new Pie object.
+// This object is the entrypoint for any further interactions with your chart.
+// Always use the constructor, don't create Pie objects directly.
+func NewPie(title string) (newPie *Pie) {
+	p := &Pie{Title: title}
+	p.slicesMap = make(map[string]*Slice)
+	return p
+}
+
+// String renders the whole chart to mermaid code lines.
+// It is a shorthand for WriteTo with a strings.Builder.
+func (p *Pie) String() (renderedElement string) {
+	var s strings.Builder
+	p.WriteTo(&s)
+	return s.String()
+}
+
+// WriteTo writes the mermaid code of the chart to w, the title and the Slices
+// indented by Indent. Implements io.WriterTo.
+func (p *Pie) WriteTo(w io.Writer) (n int64, err error) {
+	cw := &counting.Writer{W: w}
+	b := bufio.NewWriter(cw)
+	b.WriteString(p.Config.String())
+	b.WriteString("pie")
+	if p.ShowData {
+		b.WriteString(" showData")
+	}
+	b.WriteString("\n")
+	if p.Title != "" {
+		b.WriteString(p.Indent + "title " + p.Title + "\n")
+	}
+	for _, s := range p.slices {
+		b.WriteString(p.Indent + s.String())
+	}
+	err = b.Flush()
+	return cw.N, err
+}
+
+// Render writes the mermaid code of the whole chart to w.
+// Implements mermaidgen.Diagram.
+func (p *Pie) Render(w io.Writer) (err error) {
+	_, err = p.WriteTo(w)
+	return
+}
+
+// Kind returns mermaidgen.KindPie. Implements mermaidgen.Diagram.
+func (p *Pie) Kind() (kind mermaidgen.DiagramKind) {
+	return mermaidgen.KindPie
+}
+
+// GetConfig returns the Pie's Config, which may be nil.
+// Implements mermaidgen.Diagram.
+func (p *Pie) GetConfig() (config *mermaidgen.Config) {
+	return p.Config
+}
+
+// SetConfig replaces the Pie's Config, nil removes it.
+// Implements mermaidgen.Diagram.
+func (p *Pie) SetConfig(config *mermaidgen.Config) {
+	p.Config = config
+}
+
+// LiveURL renders the Pie and generates a view URL for https://mermaid.live
+// from it, see mermaidgen.LiveURL for details.
+func (p *Pie) LiveURL() (url string) {
+	url, _ = mermaidgen.LiveURL(p)
+	return
+}
+
+// ViewInBrowser uses the URL generated by Pie's LiveURL method and opens that
+// URL in the OS's default browser via mermaidgen.ViewInBrowser. It eventually
+// returns any error occured.
+func (p *Pie) ViewInBrowser() (err error) {
+	return mermaidgen.ViewInBrowser(p)
+}
+
+////////// add Items ///////////////////////////////////////////////////////////
+
+// AddSlice is used to add a value to the Pie. If a Slice with this label
+// already exists, the value is added to it, otherwise a new Slice is appended.
+// An error is returned if the value is negative, infinite or NaN, mermaid
+// doesn't support them.
+func (p *Pie) AddSlice(label string, value float64) (slice *Slice, err error) {
+	if value < 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+		return nil, fmt.Errorf("AddSlice: invalid value %v", value)
+	}
+	slice = p.slicesMap[label]
+	if slice == nil {
+		slice = &Slice{label: label, pie: p}
+		p.slicesMap[label] = slice
+		p.slices = append(p.slices, slice)
+	}
+	slice.Value += value
+	return slice, nil
+}
+
+////////// get Items ///////////////////////////////////////////////////////////
+
+// GetSlice looks up a previously defined Slice by its label.
+// If this label doesn't exist, nil is returned.
+// Use Pie's AddSlice to create new Slices.
+func (p *Pie) GetSlice(label string) (existingSlice *Slice) {
+	// if not found -> nil
+	return p.slicesMap[label]
+}
+
+////////// list Items //////////////////////////////////////////////////////////
+
+// ListSlices returns a slice of all Slices previously added to this Pie in
+// the order they are rendered.
+func (p *Pie) ListSlices() (allSlices []*Slice) {
+	allSlices = make([]*Slice, len(p.slices))
+	copy(allSlices, p.slices)
+	return
+}
+
+// Total returns the sum of the values of all Slices.
+func (p *Pie) Total() (total float64) {
+	for _, s := range p.slices {
+		total += s.Value
+	}
+	return
+}
+
+////////// ordering ////////////////////////////////////////////////////////////
+
+// SortByValue orders the Slices by their values, the largest first. Slices
+// with equal values are ordered by label.
+func (p *Pie) SortByValue() {
+	sort.SliceStable(p.slices, func(i, j int) bool {
+		if p.slices[i].Value != p.slices[j].Value {
+			return p.slices[i].Value > p.slices[j].Value
+		}
+		return p.slices[i].label < p.slices[j].label
+	})
+}
+
+// SortByLabel orders the Slices alphabetically by their labels.
+func (p *Pie) SortByLabel() {
+	sort.SliceStable(p.slices, func(i, j int) bool {
+		return p.slices[i].label < p.slices[j].label
+	})
+}
+
+// Limit reduces the Pie to the n largest Slices (see SortByValue), the values
+// of all other Slices are added to the Slice labeled other, which is appended
+// unless it is among the n largest. Nothing changes if there are no more than
+// n Slices, the Slices are sorted by value otherwise.
+func (p *Pie) Limit(n int, other string) {
+	if n < 0 || len(p.slices) <= n {
+		return
+	}
+	p.SortByValue()
+	rest := p.slices[n:]
+	p.slices = p.slices[:n:n]
+	var sum float64
+	for _, s := range rest {
+		delete(p.slicesMap, s.label)
+		s.pie = nil
+		sum += s.Value
+	}
+	// an existing other Slice is part of the rest or of the top n
+	p.AddSlice(other, sum)
+}
+
+////////// rendering helpers ///////////////////////////////////////////////////
+
+// Helperfunction to format a value without unneeded decimals.
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,93 @@
+package pie_test
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen/pie"
+)
+
+// Defining and rendering a pie chart
+func ExamplePie() {
+	chart := pie.NewPie("Pets adopted")
+	chart.ShowData = true
+	chart.AddSlice("Dogs", 386)
+	chart.AddSlice("Cats", 85.5)
+	chart.AddSlice("Rats", 15)
+	// values of existing labels are summed up
+	chart.AddSlice("Dogs", 14)
+	fmt.Print(chart)
+	fmt.Printf("%.1f%%\n", chart.GetSlice("Dogs").Percentage())
+	//Output:
+	//pie showData
+	//title Pets adopted
+	//"Dogs" : 400
+	//"Cats" : 85.5
+	//"Rats" : 15
+	//79.9%
+}
+
+// Showing the top N and an other bucket
+func ExamplePie_Limit() {
+	chart := pie.NewPie("Languages")
+	chart.Indent = "  "
+	for _, l := range []string{"Go", "Rust", "C", "Java", "Zig"} {
+		chart.AddSlice(l, float64(len(l)))
+	}
+	chart.Limit(2, "Other")
+	fmt.Print(chart)
+	//Output:
+	//pie
+	//   title Languages
+	//   "Java" : 4
+	//   "Rust" : 4
+	//   "Other" : 6
+}
+
+func TestPie_AddSlice(t *testing.T) {
+	chart := pie.NewPie("")
+	for _, v := range []float64{-1, math.NaN(), math.Inf(1)} {
+		if _, err := chart.AddSlice("x", v); err == nil {
+			t.Errorf("invalid value %v not detected", v)
+		}
+	}
+	s, _ := chart.AddSlice(`say "hi"`, 0)
+	if s.Percentage() != 0 || s.Label() != `say "hi"` || s.Pie() != chart {
+		t.Error("unexpected Slice")
+	}
+	var b strings.Builder
+	n, err := chart.WriteTo(&b)
+	expected := "pie\n\"say 'hi'\" : 0\n"
+	if err != nil || b.String() != expected || n != int64(len(expected)) {
+		t.Errorf("unexpected %d %v %q", n, err, b.String())
+	}
+}
+
+func TestPie_Limit(t *testing.T) {
+	chart := pie.NewPie("")
+	chart.AddSlice("b", 1)
+	chart.AddSlice("other", 5)
+	chart.AddSlice("a", 1)
+	c, _ := chart.AddSlice("c", 2)
+	chart.Limit(3, "other")
+	chart.Limit(3, "other")
+	var labels []string
+	for _, s := range chart.ListSlices() {
+		labels = append(labels, fmt.Sprint(s.Label(), "=", s.Value))
+	}
+	if fmt.Sprint(labels) != "[other=6 c=2 a=1]" {
+		t.Errorf("unexpected Slices %v", labels)
+	}
+	chart.Limit(1, "rest")
+	chart.SortByLabel()
+	labels = nil
+	for _, s := range chart.ListSlices() {
+		labels = append(labels, s.Label())
+	}
+	if fmt.Sprint(labels) != "[other rest]" || chart.GetSlice("c") != nil ||
+		c.Pie() != nil || chart.Total() != 9 {
+		t.Errorf("unexpected Slices %v", labels)
+	}
+}
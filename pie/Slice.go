@@ -0,0 +1,45 @@
+package pie
+
+import (
+	"strings"
+)
+
+// Slice represents a single, labeled value of the Pie. Create an instance of
+// Slice via Pie's AddSlice method, do not create instances directly. Already
+// defined labels can be looked up via Pie's GetSlice method or iterated over
+// via its ListSlices method.
+type Slice struct {
+	label string
+	pie   *Pie    // top lvl pointer
+	Value float64 // The value, it must not be negative
+}
+
+// Label provides access to the Slice's readonly field label.
+func (s *Slice) Label() (label string) {
+	return s.label
+}
+
+// Pie provides access to the top level Pie to be able to access Adder, Getter
+// and Lister methods. If the Slice was merged into another one by Pie's Limit
+// method, nil is returned.
+func (s *Slice) Pie() (topLevel *Pie) {
+	return s.pie
+}
+
+// Percentage returns the share of the Slice in the Pie's Total in percent.
+// It returns 0 if the Slice isn't part of a Pie or the Total is 0.
+func (s *Slice) Percentage() (percentage float64) {
+	if s.pie == nil {
+		return 0
+	}
+	if total := s.pie.Total(); total > 0 {
+		return s.Value / total * 100
+	}
+	return 0
+}
+
+// String renders this diagram element to a slice definition line.
+func (s *Slice) String() (renderedElement string) {
+	return `"` + strings.Replace(s.label, `"`, `'`, -1) + `" : ` +
+		formatValue(s.Value) + "\n"
+}
@@ -0,0 +1,24 @@
+/*
+Package pie is an object oriented approach to define mermaid pie charts as
+defined at https://mermaid.js.org/syntax/pie.html and render them to mermaid
+code.
+
+You use the constructor NewPie to create a new Pie object and add Slices to it.
+
+	chart := pie.NewPie("Pets")
+	chart.AddSlice("Dogs", 386)
+	chart.AddSlice("Cats", 85.5)
+
+Once the chart is completely defined, it can be "rendered" to mermaid code by
+stringifying the Pie object or streamed via WriteTo.
+
+	pie
+	title Pets
+	"Dogs" : 386
+	"Cats" : 85.5
+
+Reports are usually built from raw data: FromMap, CountBy and SumBy aggregate
+the values per label, Pie's SortByValue and SortByLabel order the Slices and
+Limit keeps the largest Slices and merges the others into one.
+*/
+package pie
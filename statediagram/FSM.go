@@ -0,0 +1,84 @@
+package statediagram
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Matches all characters not allowed in State IDs.
+var invalidIDChars = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// FromTransitionTable creates a StateDiagram from the transition table of a
+// finite state machine, which maps each state and event to the next state.
+// The diagram starts with a Transition from [*] to initial, States without
+// outgoing Transitions get a Transition to [*].
+//
+// States and events are converted to text via fmt.Sprint, States and the
+// Transitions of each State are sorted by that text to get a stable output.
+// If the text isn't a valid ID, the State gets an ID derived from it and the
+// text as Label. Use StateDiagram's Unreachable method to detect States the
+// machine can never enter.
+func FromTransitionTable[S, E comparable](table map[S]map[E]S,
+	initial S) (newStateDiagram *StateDiagram, err error) {
+	sd, _ := NewStateDiagram()
+	// collect all states, including those only used as targets
+	known := map[S]bool{initial: true}
+	for from, events := range table {
+		known[from] = true
+		for _, to := range events {
+			known[to] = true
+		}
+	}
+	states := make([]S, 0, len(known))
+	for s := range known {
+		states = append(states, s)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return fmt.Sprint(states[i]) < fmt.Sprint(states[j])
+	})
+	lookup := make(map[S]*State, len(states))
+	for _, s := range states {
+		text := fmt.Sprint(s)
+		id := fsmID(sd, text)
+		state, err := sd.AddState(id)
+		if err != nil {
+			return nil, fmt.Errorf("FromTransitionTable: %v", err)
+		}
+		if id != text {
+			state.Label = text
+		}
+		lookup[s] = state
+	}
+	sd.AddTransition(nil, lookup[initial])
+	for _, from := range states {
+		events := make([]E, 0, len(table[from]))
+		for e := range table[from] {
+			events = append(events, e)
+		}
+		sort.Slice(events, func(i, j int) bool {
+			return fmt.Sprint(events[i]) < fmt.Sprint(events[j])
+		})
+		for _, e := range events {
+			sd.AddTransition(lookup[from], lookup[table[from][e]], fmt.Sprint(e))
+		}
+		if len(events) == 0 {
+			sd.AddTransition(lookup[from], nil)
+		}
+	}
+	return sd, nil
+}
+
+// Helperfunction to derive a valid and unused State ID from the text of a
+// state.
+func fsmID(sd *StateDiagram, text string) (id string) {
+	id = invalidIDChars.ReplaceAllString(text, "_")
+	if !IsValidID(id) {
+		// empty or starting with a digit
+		id = "state_" + id
+	}
+	for i, base := 2, id; sd.GetState(id) != nil; i++ {
+		id = fmt.Sprintf("%s_%d", base, i)
+	}
+	return
+}
@@ -0,0 +1,72 @@
+package statediagram_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen/statediagram"
+)
+
+type orderState string
+type orderEvent int
+
+const (
+	pay orderEvent = iota
+	ship
+	cancel
+)
+
+func (e orderEvent) String() string {
+	return [...]string{"pay", "ship", "cancel"}[e]
+}
+
+// Documenting a finite state machine and detecting unreachable states
+func ExampleFromTransitionTable() {
+	table := map[orderState]map[orderEvent]orderState{
+		"new":       {pay: "paid", cancel: "cancelled"},
+		"paid":      {ship: "shipped", cancel: "cancelled"},
+		"on hold":   {pay: "paid"},
+		"shipped":   {},
+		"cancelled": nil,
+	}
+	sd, _ := statediagram.FromTransitionTable(table, "new")
+	fmt.Print(sd)
+	for _, s := range sd.Unreachable() {
+		fmt.Println("unreachable:", s.Label)
+	}
+	//Output:
+	//stateDiagram-v2
+	//cancelled
+	//new
+	//state "on hold" as on_hold
+	//paid
+	//shipped
+	//[*] --> new
+	//cancelled --> [*]
+	//new --> cancelled : cancel
+	//new --> paid : pay
+	//on_hold --> paid : pay
+	//paid --> cancelled : cancel
+	//paid --> shipped : ship
+	//shipped --> [*]
+	//unreachable: on hold
+}
+
+func TestFromTransitionTable(t *testing.T) {
+	// only used as target, ID collisions and invalid IDs
+	table := map[string]map[string]string{
+		"a b": {"x": "a_b"},
+		"1":   {"y": ""},
+	}
+	sd, err := statediagram.FromTransitionTable(table, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, s := range sd.ListStates() {
+		ids = append(ids, s.ID()+"="+s.Label)
+	}
+	if fmt.Sprint(ids) != "[state_= state_1=1 a_b=a b a_b_2=a_b]" {
+		t.Errorf("unexpected States %v", ids)
+	}
+}
@@ -0,0 +1,202 @@
+package statediagram
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+type stateType string
+
+// Type definitions for States as described at
+// https://mermaid.js.org/syntax/stateDiagram.html#choice.
+// The default is StateSimple.
+const (
+	StateSimple stateType = ``
+	StateChoice stateType = `<<choice>>`
+	StateFork   stateType = `<<fork>>`
+	StateJoin   stateType = `<<join>>`
+)
+
+type notePosition string
+
+// Position definitions for notes as described at
+// https://mermaid.js.org/syntax/stateDiagram.html#notes.
+// The default is NoteRightOf.
+const (
+	NoteRightOf notePosition = `right of`
+	NoteLeftOf  notePosition = `left of`
+)
+
+// State represents a single, unique state of the StateDiagram, which becomes
+// a composite State as soon as States are added to it. Create an instance of
+// State via StateDiagram's or State's AddState method, do not create instances
+// directly. Already defined IDs can be looked up via StateDiagram's GetState
+// method or iterated over via its ListStates method.
+type State struct {
+	id           string
+	diagram      *StateDiagram // top lvl pointer
+	parent       *State        // composite State containing this State
+	regions      []*region     // concurrent regions of a composite State
+	Label        string        // Optional description displayed instead of ID
+	Type         stateType     // Optional pseudo state type, e.g. StateFork
+	Note         string        // Optional note, may contain line breaks
+	NotePosition notePosition  // Position of Note, NoteRightOf if not set
+}
+
+// Private constructor for use in Add-functions.
+func stateNew(id string, sd *StateDiagram, parent *State,
+	init []interface{}) (*State, error) {
+	if !IsValidID(id) {
+		return nil, fmt.Errorf("invalid id")
+	}
+	if _, alreadyExists := sd.statesMap[id]; alreadyExists {
+		return nil, fmt.Errorf("id already exists")
+	}
+	s := &State{id: id, diagram: sd, parent: parent}
+	switch l, ok := len(init), false; {
+	case l > 1:
+		switch v := init[1].(type) {
+		case stateType:
+			s.Type = v
+		case string:
+			s.Type = stateType(v)
+		default:
+			return nil, fmt.Errorf("value for Type was no stateType")
+		}
+		fallthrough
+	case l > 0:
+		s.Label, ok = init[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("value for Label was no string")
+		}
+	}
+	sd.statesMap[id] = s
+	sd.states = append(sd.states, s)
+	return s, nil
+}
+
+// ID provides access to the State's readonly field id.
+func (s *State) ID() (id string) {
+	return s.id
+}
+
+// StateDiagram provides access to the top level StateDiagram to be able to
+// access Adder, Getter and Lister methods.
+func (s *State) StateDiagram() (topLevel *StateDiagram) {
+	return s.diagram
+}
+
+// Parent provides access to the composite State that contains this State. If
+// this State is defined on the top level of the StateDiagram, nil is returned.
+func (s *State) Parent() (containingState *State) {
+	return s.parent
+}
+
+// Helperfunction to get the current region of a composite State, the first
+// region is created on demand.
+func (s *State) currentRegion() *region {
+	if len(s.regions) == 0 {
+		s.regions = append(s.regions, &region{})
+	}
+	return s.regions[len(s.regions)-1]
+}
+
+// AddState is used to add a new State to the current region of this composite
+// State. IDs are unique for the whole StateDiagram, see StateDiagram's
+// AddState for details.
+func (s *State) AddState(id string, init ...interface{}) (newState *State,
+	err error) {
+	newState, err = stateNew(id, s.diagram, s, init)
+	if err == nil {
+		r := s.currentRegion()
+		r.states = append(r.states, newState)
+	}
+	return
+}
+
+// AddTransition is used to add a new Transition to the current region of this
+// composite State. A nil State represents the start (as from) or end (as to)
+// [*] of this composite State, see StateDiagram's AddTransition for details.
+func (s *State) AddTransition(from, to *State, init ...interface{}) (
+	newTransition *Transition, err error) {
+	newTransition, err = transitionNew(s.diagram, from, to, init)
+	if err == nil {
+		r := s.currentRegion()
+		r.transitions = append(r.transitions, newTransition)
+	}
+	return
+}
+
+// AddRegion starts a new concurrent region of this composite State, following
+// calls to AddState and AddTransition add to that region. Regions are
+// separated by -- when rendered.
+func (s *State) AddRegion() {
+	s.currentRegion()
+	s.regions = append(s.regions, &region{})
+}
+
+// String renders this diagram element to the state definition lines, followed
+// by the contents of a composite State. Notes are rendered by the containing
+// level, after its Transitions.
+func (s *State) String() (renderedElement string) {
+	var b strings.Builder
+	w := &diagramWriter{Writer: bufio.NewWriter(&b), indent: s.diagram.Indent}
+	s.writeDiagram(w)
+	w.Flush()
+	return b.String()
+}
+
+// Helperfunction for String and StateDiagram's WriteTo, renders the State to w.
+func (s *State) writeDiagram(w *diagramWriter) {
+	declared := false
+	if s.Label != "" {
+		w.startLine()
+		fmt.Fprintf(w, "state \"%s\" as %s\n", s.Label, s.id)
+		declared = true
+	}
+	if len(s.regions) > 0 {
+		w.startLine()
+		w.WriteString("state " + s.id + " {\n")
+		w.level++
+		for i, r := range s.regions {
+			if i > 0 {
+				w.startLine()
+				w.WriteString("--\n")
+			}
+			r.writeDiagram(w)
+		}
+		w.level--
+		w.startLine()
+		w.WriteString("}\n")
+	} else if s.Type != StateSimple {
+		w.startLine()
+		w.WriteString("state " + s.id + " " + string(s.Type) + "\n")
+	} else if !declared {
+		w.startLine()
+		w.WriteString(s.id + "\n")
+	}
+}
+
+// Helperfunction to render the Note of the State to w, if there is one.
+func (s *State) writeNote(w *diagramWriter) {
+	if s.Note == "" {
+		return
+	}
+	position := s.NotePosition
+	if position == "" {
+		position = NoteRightOf
+	}
+	w.startLine()
+	if !strings.Contains(s.Note, "\n") {
+		fmt.Fprintf(w, "note %s %s : %s\n", position, s.id, s.Note)
+		return
+	}
+	fmt.Fprintf(w, "note %s %s\n", position, s.id)
+	for _, line := range strings.Split(s.Note, "\n") {
+		w.startLine()
+		w.WriteString(w.indent + line + "\n")
+	}
+	w.startLine()
+	w.WriteString("end note\n")
+}
@@ -0,0 +1,290 @@
+package statediagram
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/internal/counting"
+)
+
+////////// DiagramDirection ////////////////////////////////////////////////////
+
+type diagramDirection string
+
+// Direction definitions for StateDiagrams as described at
+// https://mermaid.js.org/syntax/stateDiagram.html#setting-the-direction-of-the-diagram.
+// The default is no direction statement which results in DirectionTopDown.
+const (
+	DirectionTopDown   diagramDirection = `TB`
+	DirectionBottomUp  diagramDirection = `BT`
+	DirectionRightLeft diagramDirection = `RL`
+	DirectionLeftRight diagramDirection = `LR`
+)
+
+// IsValidID is used to check if State IDs are valid: IsValidID(string) bool.
+// Use the State's Label to display other names.
+var IsValidID = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`).MatchString
+
+////////// StateDiagram ////////////////////////////////////////////////////////
+
+// StateDiagram objects are the entrypoints to this package, the whole diagram
+// is constructed around a StateDiagram object. Create an instance of
+// StateDiagram via StateDiagram's constructor NewStateDiagram, do not create
+// instances directly.
+type StateDiagram struct {
+	region                       // top level States and Transitions
+	statesMap map[string]*State  // lookup table for existing States
+	states    []*State           // all States in the order of definition
+	Direction diagramDirection   // Optional direction of the diagram
+	Config    *mermaidgen.Config // Optional theme and configuration
+	Indent    string             // Optional indentation of composite States
+}
+
+// NewStateDiagram is the constructor used to create a new StateDiagram object.
+// This object is the entrypoint for any further interactions with your diagram.
+// Always use the constructor, don't create StateDiagram objects directly.
+// An optional initializer parameter can be given for Direction.
+func NewStateDiagram(init ...interface{}) (newStateDiagram *StateDiagram,
+	err error) {
+	sd := &StateDiagram{}
+	sd.statesMap = make(map[string]*State)
+	if len(init) > 0 {
+		switch v := init[0].(type) {
+		case diagramDirection:
+			sd.Direction = v
+		case string:
+			sd.Direction = diagramDirection(v)
+		default:
+			return nil, fmt.Errorf("value for Direction was no diagramDirection")
+		}
+	}
+	return sd, nil
+}
+
+// String recursively renders the whole diagram to mermaid code lines.
+// It is a shorthand for WriteTo with a strings.Builder.
+func (sd *StateDiagram) String() (renderedElement string) {
+	var s strings.Builder
+	sd.WriteTo(&s)
+	return s.String()
+}
+
+// WriteTo writes the mermaid code of the whole diagram to w, descending into
+// composite States, whose contents are indented by Indent, e.g. "  " or "\t".
+// Implements io.WriterTo.
+func (sd *StateDiagram) WriteTo(w io.Writer) (n int64, err error) {
+	cw := &counting.Writer{W: w}
+	b := bufio.NewWriter(cw)
+	dw := &diagramWriter{Writer: b, indent: sd.Indent}
+	dw.WriteString(sd.Config.String())
+	dw.WriteString("stateDiagram-v2\n")
+	if sd.Direction != "" {
+		fmt.Fprintln(dw, "direction", sd.Direction)
+	}
+	sd.region.writeDiagram(dw)
+	err = b.Flush()
+	return cw.N, err
+}
+
+// Render writes the mermaid code of the whole diagram to w.
+// Implements mermaidgen.Diagram.
+func (sd *StateDiagram) Render(w io.Writer) (err error) {
+	_, err = sd.WriteTo(w)
+	return
+}
+
+// Kind returns mermaidgen.KindStateDiagram. Implements mermaidgen.Diagram.
+func (sd *StateDiagram) Kind() (kind mermaidgen.DiagramKind) {
+	return mermaidgen.KindStateDiagram
+}
+
+// GetConfig returns the StateDiagram's Config, which may be nil.
+// Implements mermaidgen.Diagram.
+func (sd *StateDiagram) GetConfig() (config *mermaidgen.Config) {
+	return sd.Config
+}
+
+// SetConfig replaces the StateDiagram's Config, nil removes it.
+// Implements mermaidgen.Diagram.
+func (sd *StateDiagram) SetConfig(config *mermaidgen.Config) {
+	sd.Config = config
+}
+
+// LiveURL renders the StateDiagram and generates a view URL for
+// https://mermaid.live from it, see mermaidgen.LiveURL for details.
+func (sd *StateDiagram) LiveURL() (url string) {
+	url, _ = mermaidgen.LiveURL(sd)
+	return
+}
+
+// ViewInBrowser uses the URL generated by StateDiagram's LiveURL method and
+// opens that URL in the OS's default browser via mermaidgen.ViewInBrowser. It
+// eventually returns any error occured.
+func (sd *StateDiagram) ViewInBrowser() (err error) {
+	return mermaidgen.ViewInBrowser(sd)
+}
+
+////////// add Items ///////////////////////////////////////////////////////////
+
+// AddState is used to add a new top level State to the StateDiagram. If the
+// provided ID already exists or is invalid, no new State is created and an
+// error is returned. The ID can later be used to look up the created State
+// using StateDiagram's GetState method. Optional initializer parameters can be
+// given in the order Label, Type.
+func (sd *StateDiagram) AddState(id string, init ...interface{}) (
+	newState *State, err error) {
+	newState, err = stateNew(id, sd, nil, init)
+	if err == nil {
+		sd.region.states = append(sd.region.states, newState)
+	}
+	return
+}
+
+// AddTransition is used to add a new top level Transition from one State to
+// another. A nil State represents the start (as from) or end (as to) [*] of
+// the diagram. An optional initializer parameter can be given for Label. An
+// error is returned if a State doesn't belong to the StateDiagram.
+func (sd *StateDiagram) AddTransition(from, to *State, init ...interface{}) (
+	newTransition *Transition, err error) {
+	newTransition, err = transitionNew(sd, from, to, init)
+	if err == nil {
+		sd.region.transitions = append(sd.region.transitions, newTransition)
+	}
+	return
+}
+
+////////// get Items ///////////////////////////////////////////////////////////
+
+// GetState looks up a previously defined State by its ID, States of composite
+// States included. If this ID doesn't exist, nil is returned.
+// Use StateDiagram's or State's AddState to create new States.
+func (sd *StateDiagram) GetState(id string) (existingState *State) {
+	// if not found -> nil
+	return sd.statesMap[id]
+}
+
+////////// list Items //////////////////////////////////////////////////////////
+
+// ListStates returns a slice of all States previously added to this
+// StateDiagram or its composite States in the order they were defined.
+func (sd *StateDiagram) ListStates() (allStates []*State) {
+	allStates = make([]*State, len(sd.states))
+	copy(allStates, sd.states)
+	return
+}
+
+// ListTransitions returns a slice of all Transitions previously added to this
+// StateDiagram or its composite States, the Transitions of a composite State
+// follow the Transitions of the level it is defined in.
+func (sd *StateDiagram) ListTransitions() (allTransitions []*Transition) {
+	var collect func(r *region)
+	collect = func(r *region) {
+		allTransitions = append(allTransitions, r.transitions...)
+		for _, s := range r.states {
+			for _, sub := range s.regions {
+				collect(sub)
+			}
+		}
+	}
+	collect(&sd.region)
+	return
+}
+
+////////// analysis ////////////////////////////////////////////////////////////
+
+// Unreachable returns all States, in the order they were defined, that can't
+// be reached from the start [*] of the diagram by following Transitions.
+// Entering a composite State reaches the targets of its start [*] Transitions,
+// reaching its end [*] follows the Transitions leaving the composite State.
+// Without a top level start Transition, all States are unreachable.
+func (sd *StateDiagram) Unreachable() (unreachableStates []*State) {
+	outgoing := make(map[*State][]*Transition)
+	for _, t := range sd.ListTransitions() {
+		if t.From != nil {
+			outgoing[t.From] = append(outgoing[t.From], t)
+		}
+	}
+	reached := make(map[*State]bool)
+	var queue []*State
+	var follow func(ts []*Transition, parent *State)
+	follow = func(ts []*Transition, parent *State) {
+		for _, t := range ts {
+			switch {
+			case t.To != nil && !reached[t.To]:
+				reached[t.To] = true
+				queue = append(queue, t.To)
+			case t.To == nil && parent != nil:
+				// end of a composite State, it is left
+				follow(outgoing[parent], parent.parent)
+			}
+		}
+	}
+	starts := func(r *region) (ts []*Transition) {
+		for _, t := range r.transitions {
+			if t.From == nil {
+				ts = append(ts, t)
+			}
+		}
+		return
+	}
+	follow(starts(&sd.region), nil)
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for _, r := range s.regions {
+			follow(starts(r), s)
+		}
+		follow(outgoing[s], s.parent)
+	}
+	for _, s := range sd.states {
+		if !reached[s] {
+			unreachableStates = append(unreachableStates, s)
+		}
+	}
+	return
+}
+
+////////// rendering helpers ///////////////////////////////////////////////////
+
+// A region holds States and the Transitions between them, it is either the top
+// level of a StateDiagram or one of the concurrent regions of a composite
+// State.
+type region struct {
+	states      []*State
+	transitions []*Transition
+}
+
+// Helperfunction to render the States, the Transitions and the notes of the
+// States of a region.
+func (r *region) writeDiagram(w *diagramWriter) {
+	for _, s := range r.states {
+		s.writeDiagram(w)
+	}
+	for _, t := range r.transitions {
+		w.startLine()
+		w.WriteString(t.String())
+	}
+	for _, s := range r.states {
+		s.writeNote(w)
+	}
+}
+
+// Buffered writer used for rendering, it knows the indentation of the current
+// line.
+type diagramWriter struct {
+	*bufio.Writer
+	indent string // indentation per nesting level
+	level  int    // current nesting level
+}
+
+// Helperfunction to start a new line, writes the indentation of the current
+// nesting level.
+func (w *diagramWriter) startLine() {
+	for i := 0; i < w.level; i++ {
+		w.WriteString(w.indent)
+	}
+}
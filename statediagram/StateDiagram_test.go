@@ -0,0 +1,125 @@
+package statediagram_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen/statediagram"
+)
+
+// Defining and rendering a state diagram
+func ExampleStateDiagram() {
+	sd, _ := statediagram.NewStateDiagram(statediagram.DirectionLeftRight)
+	sd.Indent = "  "
+	off, _ := sd.AddState("Off")
+	on, _ := sd.AddState("On", "Powered on")
+	// a composite State with 2 concurrent regions
+	on.AddState("Idle")
+	on.AddTransition(nil, sd.GetState("Idle"))
+	on.AddRegion()
+	check, _ := on.AddState("Check", "", statediagram.StateChoice)
+	ok, _ := on.AddState("Ok")
+	on.AddTransition(nil, check)
+	on.AddTransition(check, ok, "healthy")
+	on.AddTransition(check, nil, "broken")
+	// top level transitions and notes
+	sd.AddTransition(nil, off)
+	sd.AddTransition(off, on, "switch")
+	sd.AddTransition(on, nil)
+	off.Note = "initial state"
+	on.Note = "multi\nline"
+	on.NotePosition = statediagram.NoteLeftOf
+	fmt.Print(sd)
+	//Output:
+	//stateDiagram-v2
+	//direction LR
+	//Off
+	//state "Powered on" as On
+	//state On {
+	//   Idle
+	//   [*] --> Idle
+	//   --
+	//   state Check <<choice>>
+	//   Ok
+	//   [*] --> Check
+	//   Check --> Ok : healthy
+	//   Check --> [*] : broken
+	//}
+	//[*] --> Off
+	//Off --> On : switch
+	//On --> [*]
+	//note right of Off : initial state
+	//note left of On
+	//   multi
+	//   line
+	//end note
+}
+
+func TestStateDiagram_errors(t *testing.T) {
+	sd, _ := statediagram.NewStateDiagram()
+	a, _ := sd.AddState("A")
+	if _, err := sd.AddState("A"); err == nil {
+		t.Error("duplicate id not detected")
+	}
+	if _, err := a.AddState("A"); err == nil {
+		t.Error("duplicate id in composite State not detected")
+	}
+	if _, err := sd.AddState("my state"); err == nil {
+		t.Error("invalid id not detected")
+	}
+	if _, err := sd.AddState("B", 1); err == nil {
+		t.Error("invalid Label not detected")
+	}
+	if _, err := sd.AddState("B", "b", 1); err == nil {
+		t.Error("invalid Type not detected")
+	}
+	if _, err := sd.AddTransition(nil, nil); err == nil {
+		t.Error("transition from [*] to [*] not detected")
+	}
+	other, _ := statediagram.NewStateDiagram()
+	b, _ := other.AddState("B")
+	if _, err := sd.AddTransition(a, b); err == nil {
+		t.Error("foreign State not detected")
+	}
+	if _, err := sd.AddTransition(a, nil, 1); err == nil {
+		t.Error("invalid Label not detected")
+	}
+	if _, err := statediagram.NewStateDiagram(1); err == nil {
+		t.Error("invalid Direction not detected")
+	}
+}
+
+func TestStateDiagram_Unreachable(t *testing.T) {
+	sd, _ := statediagram.NewStateDiagram()
+	a, _ := sd.AddState("A")
+	c, _ := sd.AddState("C")
+	inner, _ := c.AddState("Inner")
+	c.AddTransition(nil, inner)
+	c.AddTransition(inner, nil)
+	after, _ := sd.AddState("After")
+	lost, _ := c.AddState("Lost")
+	orphan, _ := sd.AddState("Orphan")
+	sd.AddTransition(orphan, a)
+	sd.AddTransition(lost, after)
+	var ids []string
+	for _, s := range sd.Unreachable() {
+		ids = append(ids, s.ID())
+	}
+	if len(ids) != 6 {
+		t.Errorf("without start all States are unreachable: %v", ids)
+	}
+	sd.AddTransition(nil, a)
+	sd.AddTransition(a, c)
+	sd.AddTransition(c, after)
+	ids = nil
+	for _, s := range sd.Unreachable() {
+		ids = append(ids, s.ID())
+	}
+	if strings.Join(ids, ",") != "Lost,Orphan" {
+		t.Errorf("unexpected unreachable States %v", ids)
+	}
+	if len(sd.ListStates()) != 6 || len(sd.ListTransitions()) != 7 {
+		t.Error("unexpected number of States or Transitions")
+	}
+}
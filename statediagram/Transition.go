@@ -0,0 +1,52 @@
+package statediagram
+
+import (
+	"fmt"
+)
+
+// Transition represents a change from one State to another. Create an instance
+// of Transition via StateDiagram's or State's AddTransition method, do not
+// create instances directly.
+type Transition struct {
+	From  *State // State the Transition starts, nil is the start [*]
+	To    *State // State the Transition ends, nil is the end [*]
+	Label string // Optional text describing the Transition, e.g. an event
+}
+
+// Private constructor for use in Add-functions.
+func transitionNew(sd *StateDiagram, from, to *State,
+	init []interface{}) (*Transition, error) {
+	if from == nil && to == nil {
+		return nil, fmt.Errorf("Transition from [*] to [*]")
+	}
+	for _, s := range []*State{from, to} {
+		if s != nil && sd.statesMap[s.id] != s {
+			return nil, fmt.Errorf("State doesn't belong to the StateDiagram")
+		}
+	}
+	t := &Transition{From: from, To: to}
+	if len(init) > 0 {
+		label, ok := init[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("value for Label was no string")
+		}
+		t.Label = label
+	}
+	return t, nil
+}
+
+// String renders this diagram element to a transition line.
+func (t *Transition) String() (renderedElement string) {
+	from, to := "[*]", "[*]"
+	if t.From != nil {
+		from = t.From.id
+	}
+	if t.To != nil {
+		to = t.To.id
+	}
+	renderedElement = from + " --> " + to
+	if t.Label != "" {
+		renderedElement += " : " + t.Label
+	}
+	return renderedElement + "\n"
+}
@@ -0,0 +1,37 @@
+/*
+Package statediagram is an object oriented approach to define mermaid state
+diagrams as defined at https://mermaid.js.org/syntax/stateDiagram.html and
+render them to mermaid code (stateDiagram-v2).
+
+You use the constructor NewStateDiagram to create a new StateDiagram object.
+
+	sd, _ := statediagram.NewStateDiagram()
+
+This object is used to add States and Transitions between them, a nil State
+is the start or end [*] of the diagram.
+
+	idle, _ := sd.AddState("Idle")
+	busy, _ := sd.AddState("Busy")
+	sd.AddTransition(nil, idle)
+	sd.AddTransition(idle, busy, "start")
+	sd.AddTransition(busy, nil)
+
+Once the diagram is completely defined, it can be "rendered" to mermaid code by
+stringifying the StateDiagram object or streamed via WriteTo.
+
+	stateDiagram-v2
+	Idle
+	Busy
+	[*] --> Idle
+	Idle --> Busy : start
+	Busy --> [*]
+
+States become composite States as soon as States are added to them, AddRegion
+splits them into concurrent regions. Forks, joins and choices are States with
+the according Type.
+
+Services built around a finite state machine can document themselves via
+FromTransitionTable, StateDiagram's Unreachable method reports the States the
+machine can never enter.
+*/
+package statediagram
@@ -0,0 +1,97 @@
+package timeline
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+////////// dated events ////////////////////////////////////////////////////////
+
+type grouping string
+
+// Grouping definitions for FromEvents.
+const (
+	GroupByMonth   grouping = `month`
+	GroupByQuarter grouping = `quarter`
+	GroupByYear    grouping = `year`
+)
+
+// Event is a dated event passed to FromEvents, e.g. a release parsed by
+// ParseChangelog.
+type Event struct {
+	Date time.Time // When the event happened
+	Text string    // What happened
+}
+
+// FromEvents creates a Timeline with the given title from dated events, which
+// are grouped into Periods in chronological order, events of the same Period
+// in chronological order, too. Grouped by year, the Periods are labeled with
+// the year, e.g. 2024. Grouped by quarter or month, there is a Section per
+// year with Periods labeled like Q1 or Jan. An error is returned for unknown
+// groupings.
+func FromEvents(title string, events []Event,
+	by grouping) (newTimeline *Timeline, err error) {
+	switch by {
+	case GroupByMonth, GroupByQuarter, GroupByYear:
+	default:
+		return nil, fmt.Errorf("FromEvents: unknown grouping %q", by)
+	}
+	sorted := make([]Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+	t := NewTimeline(title)
+	for _, e := range sorted {
+		year := fmt.Sprint(e.Date.Year())
+		switch by {
+		case GroupByYear:
+			t.AddPeriod(year).AddEvent(e.Text)
+		case GroupByQuarter:
+			quarter := fmt.Sprintf("Q%d", (int(e.Date.Month())+2)/3)
+			t.AddSection(year).AddPeriod(quarter).AddEvent(e.Text)
+		case GroupByMonth:
+			month := e.Date.Month().String()[:3]
+			t.AddSection(year).AddPeriod(month).AddEvent(e.Text)
+		}
+	}
+	return t, nil
+}
+
+////////// changelog ///////////////////////////////////////////////////////////
+
+// Release headings of changelogs, e.g. "## [1.2.0] - 2024-01-31" as used by
+// https://keepachangelog.com or "## [1.2.0](https://...) (2024-01-31)" as
+// generated by conventional-changelog.
+var changelogRelease = regexp.MustCompile(`^#{1,3}\s+\[?([^\]\s(]+)\]?` +
+	`(?:\([^)]*\))?\s*(?:[-–—]\s*|\(\s*)?(\d{4}-\d{2}-\d{2})\b`)
+
+// ParseChangelog reads the release headings of a changelog, e.g. a
+// CHANGELOG.md as described at https://keepachangelog.com, and returns them as
+// Events with the version as Text, e.g. "## [1.2.0] - 2024-01-31" or
+// "## 1.2.0 (2024-01-31)". Headings without date, like "## [Unreleased]", are
+// skipped.
+func ParseChangelog(r io.Reader) (events []Event, err error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		match := changelogRelease.FindStringSubmatch(
+			strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", match[2])
+		if err != nil {
+			return nil, fmt.Errorf("ParseChangelog: %v", err)
+		}
+		events = append(events, Event{Date: date, Text: match[1]})
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ParseChangelog: %v", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,72 @@
+package timeline_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Heiko-san/mermaidgen/timeline"
+)
+
+// Generating a release history from a changelog
+func ExampleParseChangelog() {
+	changelog := `# Changelog
+
+## [Unreleased]
+
+## [1.2.0] - 2024-05-02
+### Added
+- Something
+
+## [1.1.1] - 2024-02-20
+## [1.1.0] - 2024-01-10
+## [1.0.0](https://example.com/compare/v0.9.0...v1.0.0) (2023-11-30)
+`
+	events, _ := timeline.ParseChangelog(strings.NewReader(changelog))
+	t, _ := timeline.FromEvents("Releases", events, timeline.GroupByQuarter)
+	fmt.Print(t)
+	//Output:
+	//timeline
+	//title Releases
+	//section 2023
+	//Q4 : 1.0.0
+	//section 2024
+	//Q1 : 1.1.0 : 1.1.1
+	//Q2 : 1.2.0
+}
+
+func TestFromEvents(t *testing.T) {
+	day := func(date string) time.Time {
+		d, _ := time.Parse("2006-01-02", date)
+		return d
+	}
+	events := []timeline.Event{{day("2024-03-01"), "b"},
+		{day("2023-12-24"), "a"}, {day("2024-03-31"), "c"}}
+	tl, _ := timeline.FromEvents("", events, timeline.GroupByMonth)
+	want := "timeline\nsection 2023\nDec : a\nsection 2024\nMar : b : c\n"
+	if tl.String() != want {
+		t.Errorf("unexpected Timeline\n%s", tl)
+	}
+	tl, _ = timeline.FromEvents("", events, timeline.GroupByYear)
+	if tl.String() != "timeline\n2023 : a\n2024 : b : c\n" {
+		t.Errorf("unexpected Timeline\n%s", tl)
+	}
+	if _, err := timeline.FromEvents("", events, "week"); err == nil {
+		t.Error("unknown grouping not detected")
+	}
+}
+
+func TestParseChangelog(t *testing.T) {
+	events, err := timeline.ParseChangelog(strings.NewReader(
+		"## v2.0.0 - 2024-13-01\n"))
+	if err == nil || events != nil {
+		t.Error("invalid date not detected")
+	}
+	events, _ = timeline.ParseChangelog(strings.NewReader(
+		"## 2.0.0 (2024-06-01)\n## [1.0.0] – 2020-01-01 [YANKED]\n"))
+	if len(events) != 2 || events[0].Text != "2.0.0" ||
+		events[1].Date.Year() != 2020 {
+		t.Errorf("unexpected Events %v", events)
+	}
+}
@@ -0,0 +1,95 @@
+package timeline
+
+import (
+	"strings"
+)
+
+////////// Section /////////////////////////////////////////////////////////////
+
+// Section represents a titled group of Periods of the Timeline. Create an
+// instance of Section via Timeline's AddSection method, do not create instances
+// directly. Already defined titles can be looked up via Timeline's GetSection
+// method or iterated over via its ListSections method.
+type Section struct {
+	periodList
+	title string
+}
+
+// Title provides access to the Section's readonly field title.
+func (s *Section) Title() (title string) {
+	return s.title
+}
+
+// Timeline provides access to the top level Timeline to be able to access
+// Adder, Getter and Lister methods.
+func (s *Section) Timeline() (topLevel *Timeline) {
+	return s.timeline
+}
+
+// AddPeriod is used to add a new Period with the given label to the Section.
+// If a Period with this label already exists in the Section, it is returned
+// instead.
+func (s *Section) AddPeriod(label string) (period *Period) {
+	return s.addPeriod(label, s)
+}
+
+// GetPeriod looks up a previously defined Period of the Section by its label.
+// If this label doesn't exist, nil is returned.
+// Use Section's AddPeriod to create new Periods.
+func (s *Section) GetPeriod(label string) (existingPeriod *Period) {
+	// if not found -> nil
+	return s.periodsMap[label]
+}
+
+// ListPeriods returns a slice of all Periods previously added to this Section
+// in the order they are rendered.
+func (s *Section) ListPeriods() (allPeriods []*Period) {
+	return s.listPeriods()
+}
+
+////////// Period //////////////////////////////////////////////////////////////
+
+// Period represents a labeled time period of the Timeline with its events.
+// Create an instance of Period via Timeline's or Section's AddPeriod method,
+// do not create instances directly. Already defined labels can be looked up
+// via GetPeriod or iterated over via ListPeriods of the same type.
+type Period struct {
+	label    string
+	timeline *Timeline // top lvl pointer
+	section  *Section  // nil for Periods without Section
+	Events   []string  // The events in the order of definition
+}
+
+// Label provides access to the Period's readonly field label.
+func (p *Period) Label() (label string) {
+	return p.label
+}
+
+// Timeline provides access to the top level Timeline to be able to access
+// Adder, Getter and Lister methods.
+func (p *Period) Timeline() (topLevel *Timeline) {
+	return p.timeline
+}
+
+// Section returns the Section the Period belongs to, nil if it has none.
+func (p *Period) Section() (section *Section) {
+	return p.section
+}
+
+// AddEvent is used to append events to the Period.
+func (p *Period) AddEvent(events ...string) {
+	p.Events = append(p.Events, events...)
+}
+
+// String renders this diagram element to a time period line. Colons, which
+// separate events in mermaid, and # are replaced by their full width forms,
+// line breaks by <br>.
+func (p *Period) String() (renderedElement string) {
+	var b strings.Builder
+	b.WriteString(text(p.label))
+	for _, e := range p.Events {
+		b.WriteString(" : " + text(e))
+	}
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,209 @@
+package timeline
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/internal/counting"
+)
+
+////////// Timeline ////////////////////////////////////////////////////////////
+
+// Timeline objects are the entrypoints to this package, the whole diagram is
+// constructed around a Timeline object. Create an instance of Timeline via
+// Timeline's constructor NewTimeline, do not create instances directly.
+//
+// A Timeline consists of time Periods with one or more events each. Periods
+// can be grouped into Sections, Periods without Section are rendered first.
+type Timeline struct {
+	periodList                      // Periods without Section
+	sectionsMap map[string]*Section // lookup table for existing Sections
+	sections    []*Section          // Sections for ordered rendering
+	Title       string              // Optional title of the Timeline
+	Config      *mermaidgen.Config  // Optional theme and configuration
+	Indent      string              // Optional indentation per level
+}
+
+// NewTimeline is the constructor used to create a new Timeline object.
+// This object is the entrypoint for any further interactions with your diagram.
+// Always use the constructor, don't create Timeline objects directly.
+func NewTimeline(title string) (newTimeline *Timeline) {
+	t := &Timeline{Title: title}
+	t.periodList.timeline = t
+	t.periodsMap = make(map[string]*Period)
+	t.sectionsMap = make(map[string]*Section)
+	return t
+}
+
+// String renders the whole diagram to mermaid code lines.
+// It is a shorthand for WriteTo with a strings.Builder.
+func (t *Timeline) String() (renderedElement string) {
+	var s strings.Builder
+	t.WriteTo(&s)
+	return s.String()
+}
+
+// WriteTo writes the mermaid code of the whole Timeline to w. The title,
+// Sections and Periods are indented by Indent, Periods of Sections twice.
+// Implements io.WriterTo.
+func (t *Timeline) WriteTo(w io.Writer) (n int64, err error) {
+	cw := &counting.Writer{W: w}
+	b := bufio.NewWriter(cw)
+	b.WriteString(t.Config.String())
+	b.WriteString("timeline\n")
+	if t.Title != "" {
+		b.WriteString(t.Indent + "title " + text(t.Title) + "\n")
+	}
+	t.render(b, t.Indent)
+	for _, s := range t.sections {
+		b.WriteString(t.Indent + "section " + text(s.title) + "\n")
+		s.render(b, t.Indent+t.Indent)
+	}
+	err = b.Flush()
+	return cw.N, err
+}
+
+// Render writes the mermaid code of the whole diagram to w.
+// Implements mermaidgen.Diagram.
+func (t *Timeline) Render(w io.Writer) (err error) {
+	_, err = t.WriteTo(w)
+	return
+}
+
+// Kind returns mermaidgen.KindTimeline. Implements mermaidgen.Diagram.
+func (t *Timeline) Kind() (kind mermaidgen.DiagramKind) {
+	return mermaidgen.KindTimeline
+}
+
+// GetConfig returns the Timeline's Config, which may be nil.
+// Implements mermaidgen.Diagram.
+func (t *Timeline) GetConfig() (config *mermaidgen.Config) {
+	return t.Config
+}
+
+// SetConfig replaces the Timeline's Config, nil removes it.
+// Implements mermaidgen.Diagram.
+func (t *Timeline) SetConfig(config *mermaidgen.Config) {
+	t.Config = config
+}
+
+// LiveURL renders the Timeline and generates a view URL for
+// https://mermaid.live from it, see mermaidgen.LiveURL for details.
+func (t *Timeline) LiveURL() (url string) {
+	url, _ = mermaidgen.LiveURL(t)
+	return
+}
+
+// ViewInBrowser uses the URL generated by Timeline's LiveURL method and opens
+// that URL in the OS's default browser via mermaidgen.ViewInBrowser. It
+// eventually returns any error occured.
+func (t *Timeline) ViewInBrowser() (err error) {
+	return mermaidgen.ViewInBrowser(t)
+}
+
+////////// add Items ///////////////////////////////////////////////////////////
+
+// AddSection is used to add a new Section with the given title to the
+// Timeline. If a Section with this title already exists, it is returned
+// instead.
+func (t *Timeline) AddSection(title string) (section *Section) {
+	section = t.sectionsMap[title]
+	if section == nil {
+		section = &Section{title: title}
+		section.periodList.timeline = t
+		section.periodsMap = make(map[string]*Period)
+		t.sectionsMap[title] = section
+		t.sections = append(t.sections, section)
+	}
+	return
+}
+
+// AddPeriod is used to add a new Period with the given label to the Timeline
+// without Section. If a Period without Section with this label already exists,
+// it is returned instead.
+func (t *Timeline) AddPeriod(label string) (period *Period) {
+	return t.addPeriod(label, nil)
+}
+
+////////// get Items ///////////////////////////////////////////////////////////
+
+// GetSection looks up a previously defined Section by its title.
+// If this title doesn't exist, nil is returned.
+// Use Timeline's AddSection to create new Sections.
+func (t *Timeline) GetSection(title string) (existingSection *Section) {
+	// if not found -> nil
+	return t.sectionsMap[title]
+}
+
+// GetPeriod looks up a previously defined Period without Section by its label.
+// If this label doesn't exist, nil is returned.
+// Use Timeline's AddPeriod to create new Periods.
+func (t *Timeline) GetPeriod(label string) (existingPeriod *Period) {
+	// if not found -> nil
+	return t.periodsMap[label]
+}
+
+////////// list Items //////////////////////////////////////////////////////////
+
+// ListSections returns a slice of all Sections previously added to this
+// Timeline in the order they are rendered.
+func (t *Timeline) ListSections() (allSections []*Section) {
+	allSections = make([]*Section, len(t.sections))
+	copy(allSections, t.sections)
+	return
+}
+
+// ListPeriods returns a slice of all Periods without Section previously added
+// to this Timeline in the order they are rendered.
+func (t *Timeline) ListPeriods() (allPeriods []*Period) {
+	return t.listPeriods()
+}
+
+////////// periods /////////////////////////////////////////////////////////////
+
+// The Periods of a Timeline or a Section.
+type periodList struct {
+	timeline   *Timeline          // top lvl pointer
+	periodsMap map[string]*Period // lookup table for existing Periods
+	periods    []*Period          // Periods for ordered rendering
+}
+
+// Helperfunction to add a Period or to get the existing one.
+func (pl *periodList) addPeriod(label string, s *Section) (period *Period) {
+	period = pl.periodsMap[label]
+	if period == nil {
+		period = &Period{label: label, timeline: pl.timeline, section: s}
+		pl.periodsMap[label] = period
+		pl.periods = append(pl.periods, period)
+	}
+	return
+}
+
+// Helperfunction to copy the Periods.
+func (pl *periodList) listPeriods() (allPeriods []*Period) {
+	allPeriods = make([]*Period, len(pl.periods))
+	copy(allPeriods, pl.periods)
+	return
+}
+
+// Helperfunction to render the Periods with the given indentation.
+func (pl *periodList) render(b *bufio.Writer, indentation string) {
+	for _, p := range pl.periods {
+		b.WriteString(indentation + p.String())
+	}
+}
+
+////////// rendering helpers ///////////////////////////////////////////////////
+
+// Replacements of characters mermaid uses as separators or for comments in
+// timelines, which can't be escaped: they are replaced by their full width
+// forms.
+var textReplacer = strings.NewReplacer("#", "＃", ":", "：", "\r\n", "<br>",
+	"\n", "<br>")
+
+// Helperfunction to replace the separator characters of texts.
+func text(s string) string {
+	return textReplacer.Replace(s)
+}
@@ -0,0 +1,56 @@
+package timeline_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Heiko-san/mermaidgen"
+	"github.com/Heiko-san/mermaidgen/timeline"
+)
+
+// Defining and rendering a timeline
+func ExampleTimeline() {
+	t := timeline.NewTimeline("History of Social Media Platform")
+	t.Indent = "  "
+	t.AddPeriod("2002").AddEvent("LinkedIn")
+	section := t.AddSection("2004-2006")
+	section.AddPeriod("2004").AddEvent("Facebook", "Google")
+	section.AddPeriod("2005").AddEvent("YouTube")
+	// existing Periods are extended
+	section.AddPeriod("2004").AddEvent("fix: crash", "C# support")
+	fmt.Print(t)
+	//Output:
+	//timeline
+	//   title History of Social Media Platform
+	//   2002 : LinkedIn
+	//   section 2004-2006
+	//     2004 : Facebook : Google : fix： crash : C＃ support
+	//     2005 : YouTube
+}
+
+func TestTimeline(t *testing.T) {
+	tl := timeline.NewTimeline("")
+	s := tl.AddSection("s")
+	p := s.AddPeriod("p")
+	if tl.AddSection("s") != s || tl.GetSection("s") != s ||
+		s.GetPeriod("p") != p || tl.GetPeriod("p") != nil {
+		t.Error("unexpected lookup")
+	}
+	q := tl.AddPeriod("q")
+	if p.Section() != s || q.Section() != nil || p.Timeline() != tl ||
+		s.Timeline() != tl || p.Label() != "p" || s.Title() != "s" {
+		t.Error("unexpected accessors")
+	}
+	if len(tl.ListSections()) != 1 || len(tl.ListPeriods()) != 1 ||
+		len(s.ListPeriods()) != 1 {
+		t.Error("unexpected lists")
+	}
+	q.AddEvent("a\nb #1")
+	if tl.String() != "timeline\nq : a<br>b ＃1\nsection s\np\n" {
+		t.Errorf("unexpected rendering\n%s", tl)
+	}
+	var d mermaidgen.Diagram = tl
+	if d.Kind() != mermaidgen.KindTimeline || d.GetConfig() != nil {
+		t.Error("unexpected Diagram")
+	}
+}
@@ -0,0 +1,34 @@
+/*
+Package timeline is an object oriented approach to define mermaid timelines as
+defined at https://mermaid.js.org/syntax/timeline.html and render them to
+mermaid code.
+
+You use the constructor NewTimeline to create a new Timeline object with its
+title.
+
+	t := timeline.NewTimeline("Release history")
+
+This object is used to add time Periods with their events, optionally grouped
+into Sections.
+
+	t.AddPeriod("2023").AddEvent("v1.0")
+	t.AddSection("2024").AddPeriod("Q1").AddEvent("v1.1", "v1.2")
+
+Once the diagram is completely defined, it can be "rendered" to mermaid code by
+stringifying the Timeline object or streamed via WriteTo.
+
+	timeline
+	title Release history
+	2023 : v1.0
+	section 2024
+	Q1 : v1.1 : v1.2
+
+Instead of defining it manually, a Timeline can be generated from dated events
+via FromEvents, which groups them by month, quarter or year. ParseChangelog
+reads such events from the release headings of a CHANGELOG.md.
+
+	f, _ := os.Open("CHANGELOG.md")
+	events, err := timeline.ParseChangelog(f)
+	t, err := timeline.FromEvents("Releases", events, timeline.GroupByYear)
+*/
+package timeline
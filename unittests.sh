@@ -2,6 +2,6 @@
 
 set -e
 
-go test -covermode=set -coverprofile "cover.out" github.com/Heiko-san/mermaidgen/flowchart github.com/Heiko-san/mermaidgen/gantt github.com/Heiko-san/mermaidgen/sequence
+go test -covermode=set -coverprofile "cover.out" github.com/Heiko-san/mermaidgen/...
 go tool cover -html="cover.out" -o cover.html
 xdg-open cover.html